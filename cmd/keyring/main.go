@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/encryption"
+)
+
+func main() {
+	path := flag.String("path", "cert/keyring.json", "keyring file path")
+	rotate := flag.Bool("rotate", false, "add a new key and make it current")
+	flag.Parse()
+
+	keyring, err := encryption.LoadKeyring(*path)
+
+	if os.IsNotExist(err) {
+		keyring = &encryption.Keyring{
+			Current:  "v1",
+			Keys:     map[string]string{"v1": encryption.GenerateKey()},
+			IndexKey: encryption.GenerateKey(),
+		}
+
+		if err := keyring.Save(*path); err != nil {
+			log.Fatal("Error saving keyring", err)
+		}
+
+		log.Printf("Keyring created at %s with key v1", *path)
+		return
+	}
+
+	if err != nil {
+		log.Fatal("Error loading keyring", err)
+	}
+
+	if !*rotate {
+		log.Printf("Keyring at %s already exists, current key is %s", *path, keyring.Current)
+		return
+	}
+
+	keyID := fmt.Sprintf("v%d", len(keyring.Keys)+1)
+	keyring.Keys[keyID] = encryption.GenerateKey()
+	keyring.Current = keyID
+
+	if err := keyring.Save(*path); err != nil {
+		log.Fatal("Error saving keyring", err)
+	}
+
+	log.Printf("Keyring rotated, current key is %s", keyID)
+}
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    key VARCHAR(255) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL DEFAULT 'secret',
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_api_keys_account_id ON api_keys(account_id);
+CREATE INDEX idx_api_keys_key ON api_keys(key);
+
+INSERT INTO api_keys (account_id, key, type, scopes, created_at, updated_at)
+SELECT id, api_key, 'secret', ARRAY['invoices:read', 'invoices:write', 'refunds:write', 'accounts:read', 'keys:write'], created_at, updated_at
+FROM accounts;
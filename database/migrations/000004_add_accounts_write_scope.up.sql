@@ -0,0 +1,4 @@
+UPDATE api_keys
+SET scopes = array_append(scopes, 'accounts:write')
+WHERE key IN (SELECT api_key FROM accounts)
+    AND NOT ('accounts:write' = ANY(scopes));
@@ -0,0 +1 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS frozen_at TIMESTAMP NULL;
@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+DROP TRIGGER IF EXISTS audit_log_no_update_delete ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+
+DROP INDEX IF EXISTS idx_audit_log_actor;
+DROP INDEX IF EXISTS idx_audit_log_seq;
+
+ALTER TABLE audit_log
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS seq;
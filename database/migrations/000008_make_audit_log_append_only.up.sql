@@ -0,0 +1,23 @@
+ALTER TABLE audit_log
+    ADD COLUMN IF NOT EXISTS seq BIGSERIAL,
+    ADD COLUMN IF NOT EXISTS ip TEXT NULL,
+    ADD COLUMN IF NOT EXISTS request_id TEXT NULL,
+    ADD COLUMN IF NOT EXISTS prev_hash TEXT NULL,
+    ADD COLUMN IF NOT EXISTS hash TEXT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_log_seq ON audit_log(seq);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_type, actor_id);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
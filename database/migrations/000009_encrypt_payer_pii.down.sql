@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_payer_key_id;
+DROP INDEX IF EXISTS idx_payer_tax_id_index;
+
+ALTER TABLE payers
+    DROP COLUMN IF EXISTS tax_id_index,
+    DROP COLUMN IF EXISTS wrapped_key,
+    DROP COLUMN IF EXISTS key_id;
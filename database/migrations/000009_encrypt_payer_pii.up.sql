@@ -0,0 +1,7 @@
+ALTER TABLE payers
+    ADD COLUMN IF NOT EXISTS key_id TEXT NULL,
+    ADD COLUMN IF NOT EXISTS wrapped_key TEXT NULL,
+    ADD COLUMN IF NOT EXISTS tax_id_index TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payer_tax_id_index ON payers(tax_id_index);
+CREATE INDEX IF NOT EXISTS idx_payer_key_id ON payers(key_id);
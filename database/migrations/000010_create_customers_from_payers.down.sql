@@ -0,0 +1,17 @@
+UPDATE api_keys
+SET scopes = array_remove(array_remove(scopes, 'customers:read'), 'customers:write');
+
+DROP INDEX IF EXISTS idx_invoice_customer_id;
+DROP INDEX IF EXISTS idx_payer_account_email_index;
+DROP INDEX IF EXISTS idx_payer_account_tax_id_index;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS customer_id;
+
+DELETE FROM payers
+WHERE invoice_id IS NULL;
+
+ALTER TABLE payers
+    ALTER COLUMN invoice_id SET NOT NULL,
+    DROP COLUMN IF EXISTS email_index,
+    DROP COLUMN IF EXISTS account_id;
@@ -0,0 +1,28 @@
+ALTER TABLE payers
+    ADD COLUMN IF NOT EXISTS account_id UUID NULL REFERENCES accounts(id),
+    ADD COLUMN IF NOT EXISTS email_index TEXT NULL,
+    ALTER COLUMN invoice_id DROP NOT NULL;
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS customer_id UUID NULL REFERENCES payers(id);
+
+UPDATE payers p
+SET account_id = i.account_id
+FROM invoices i
+WHERE p.invoice_id = i.id
+    AND p.account_id IS NULL;
+
+UPDATE invoices i
+SET customer_id = p.id
+FROM payers p
+WHERE p.invoice_id = i.id
+    AND i.customer_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payer_account_tax_id_index ON payers(account_id, tax_id_index);
+CREATE INDEX IF NOT EXISTS idx_payer_account_email_index ON payers(account_id, email_index);
+CREATE INDEX IF NOT EXISTS idx_invoice_customer_id ON invoices(customer_id);
+
+UPDATE api_keys
+SET scopes = scopes || ARRAY['customers:read', 'customers:write']
+WHERE key IN (SELECT api_key FROM accounts)
+    AND NOT ('customers:write' = ANY(scopes));
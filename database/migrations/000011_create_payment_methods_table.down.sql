@@ -0,0 +1,5 @@
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS payment_method_id;
+
+DROP TABLE IF EXISTS payment_methods;
+DROP TABLE IF EXISTS card_vault;
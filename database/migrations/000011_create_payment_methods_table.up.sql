@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS card_vault (
+    token VARCHAR(64) PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    number TEXT NOT NULL,
+    cardholder_name TEXT NOT NULL,
+    key_id TEXT NOT NULL,
+    wrapped_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    customer_id UUID NOT NULL REFERENCES payers(id),
+    type VARCHAR(20) NOT NULL,
+    vault_token VARCHAR(64) NULL REFERENCES card_vault(token) ON DELETE SET NULL,
+    brand VARCHAR(20) NOT NULL DEFAULT '',
+    last4 VARCHAR(4) NOT NULL DEFAULT '',
+    expiry_month INTEGER NOT NULL DEFAULT 0,
+    expiry_year INTEGER NOT NULL DEFAULT 0,
+    cardholder_name VARCHAR(255) NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_payment_methods_customer_id ON payment_methods(customer_id);
+CREATE INDEX idx_payment_methods_expiry ON payment_methods(expiry_year, expiry_month) WHERE type = 'card' AND status = 'active';
+CREATE UNIQUE INDEX idx_payment_methods_default ON payment_methods(customer_id) WHERE is_default AND deleted_at IS NULL;
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS payment_method_id UUID NULL REFERENCES payment_methods(id);
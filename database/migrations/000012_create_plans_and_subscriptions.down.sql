@@ -0,0 +1,10 @@
+UPDATE api_keys
+SET scopes = array_remove(array_remove(scopes, 'subscriptions:read'), 'subscriptions:write');
+
+DROP INDEX IF EXISTS idx_invoice_subscription_id;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS subscription_id;
+
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS plans;
@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    name VARCHAR(255) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    interval VARCHAR(10) NOT NULL,
+    interval_count INTEGER NOT NULL DEFAULT 1,
+    trial_days INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    archived_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_plans_account_id ON plans(account_id);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    customer_id UUID NOT NULL REFERENCES payers(id),
+    payment_method_id UUID NULL REFERENCES payment_methods(id),
+    plan_id UUID NOT NULL REFERENCES plans(id),
+    status VARCHAR(20) NOT NULL,
+    current_period_start TIMESTAMP NULL,
+    current_period_end TIMESTAMP NULL,
+    next_billing_at TIMESTAMP NOT NULL,
+    trial_end TIMESTAMP NULL,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    pending_proration DECIMAL(10,2) NOT NULL DEFAULT 0,
+    latest_invoice_id UUID NULL REFERENCES invoices(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    paused_at TIMESTAMP NULL,
+    cancelled_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_subscriptions_account_id ON subscriptions(account_id);
+CREATE INDEX idx_subscriptions_customer_id ON subscriptions(customer_id);
+CREATE INDEX idx_subscriptions_next_billing_at ON subscriptions(next_billing_at) WHERE status IN ('trialing', 'active', 'past_due');
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS subscription_id UUID NULL REFERENCES subscriptions(id);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_subscription_id ON invoices(subscription_id);
+
+UPDATE api_keys
+SET scopes = scopes || ARRAY['subscriptions:read', 'subscriptions:write']
+WHERE key IN (SELECT api_key FROM accounts)
+    AND NOT ('subscriptions:write' = ANY(scopes));
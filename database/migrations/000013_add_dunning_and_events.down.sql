@@ -0,0 +1,19 @@
+UPDATE api_keys
+SET scopes = array_remove(scopes, 'events:read');
+
+DROP INDEX IF EXISTS idx_subscriptions_next_retry_at;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS next_retry_at,
+    DROP COLUMN IF EXISTS dunning_started_at,
+    DROP COLUMN IF EXISTS dunning_amount,
+    DROP COLUMN IF EXISTS dunning_attempts,
+    DROP COLUMN IF EXISTS dunning_step;
+
+DROP INDEX IF EXISTS idx_invoice_subscription_period;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS period_start,
+    DROP COLUMN IF EXISTS decline_code;
+
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    type VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_events_account_id_created_at ON events(account_id, created_at DESC);
+CREATE INDEX idx_events_resource_id ON events(resource_id);
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS decline_code VARCHAR(40) NULL,
+    ADD COLUMN IF NOT EXISTS period_start DATE NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoice_subscription_period ON invoices(subscription_id, period_start)
+    WHERE period_start IS NOT NULL AND status IN ('pending', 'approved');
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS dunning_step VARCHAR(20) NULL,
+    ADD COLUMN IF NOT EXISTS dunning_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS dunning_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS dunning_started_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS next_retry_at TIMESTAMP NULL;
+
+CREATE INDEX idx_subscriptions_next_retry_at ON subscriptions(next_retry_at) WHERE status = 'past_due';
+
+UPDATE api_keys
+SET scopes = scopes || ARRAY['events:read']
+WHERE key IN (SELECT api_key FROM accounts)
+    AND NOT ('events:read' = ANY(scopes));
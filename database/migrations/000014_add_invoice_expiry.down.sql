@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_invoice_pending_due_date;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS provider_charge_id,
+    DROP COLUMN IF EXISTS grace_days;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS invoice_grace_days;
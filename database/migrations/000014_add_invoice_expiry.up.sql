@@ -0,0 +1,8 @@
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS invoice_grace_days INTEGER NOT NULL DEFAULT 60;
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS grace_days INTEGER NOT NULL DEFAULT 60,
+    ADD COLUMN IF NOT EXISTS provider_charge_id VARCHAR(100) NULL;
+
+CREATE INDEX IF NOT EXISTS idx_invoice_pending_due_date ON invoices(due_date) WHERE status = 'pending';
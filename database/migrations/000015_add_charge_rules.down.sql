@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_invoice_provider_charge_id;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS paid_at,
+    DROP COLUMN IF EXISTS paid_amount,
+    DROP COLUMN IF EXISTS charge_rules;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS charge_defaults;
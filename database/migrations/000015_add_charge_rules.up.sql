@@ -0,0 +1,11 @@
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS charge_defaults JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS charge_rules JSONB NULL,
+    ADD COLUMN IF NOT EXISTS paid_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS paid_at TIMESTAMP NULL;
+
+UPDATE invoices SET paid_amount = amount, paid_at = updated_at WHERE status = 'approved';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoice_provider_charge_id ON invoices(provider_charge_id) WHERE provider_charge_id IS NOT NULL;
@@ -0,0 +1,5 @@
+UPDATE api_keys
+SET scopes = array_remove(array_remove(scopes, 'payouts:read'), 'payouts:write');
+
+DROP TABLE IF EXISTS payouts;
+DROP TABLE IF EXISTS payout_destinations;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS payout_destinations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    type VARCHAR(20) NOT NULL,
+    holder_name VARCHAR(100) NOT NULL,
+    holder_tax_id TEXT NOT NULL,
+    pix_key_type VARCHAR(20) NOT NULL DEFAULT '',
+    pix_key TEXT NOT NULL,
+    bank_ispb VARCHAR(8) NOT NULL DEFAULT '',
+    branch VARCHAR(5) NOT NULL DEFAULT '',
+    account_number TEXT NOT NULL,
+    account_type VARCHAR(20) NOT NULL DEFAULT '',
+    key_id TEXT NOT NULL,
+    wrapped_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_payout_destinations_account_id ON payout_destinations(account_id);
+
+CREATE TABLE IF NOT EXISTS payouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    destination_id UUID NOT NULL REFERENCES payout_destinations(id),
+    amount DECIMAL(10,2) NOT NULL,
+    description VARCHAR(140) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    provider_transfer_id VARCHAR(100) NULL,
+    failure_reason TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    paid_at TIMESTAMP NULL,
+    failed_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_payouts_account_id_created_at ON payouts(account_id, created_at DESC);
+CREATE INDEX idx_payouts_in_flight ON payouts(updated_at) WHERE status IN ('pending', 'processing');
+
+UPDATE api_keys
+SET scopes = scopes || ARRAY['payouts:read', 'payouts:write']
+WHERE key IN (SELECT api_key FROM accounts)
+    AND NOT ('payouts:read' = ANY(scopes));
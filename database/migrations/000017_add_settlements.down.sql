@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS settlements;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS reserved_balance,
+    DROP COLUMN IF EXISTS pending_balance;
@@ -0,0 +1,30 @@
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS pending_balance DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reserved_balance DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- Payouts still in flight were debited from the balance before it was split;
+-- carry them over to the reserved balance.
+UPDATE accounts a
+SET reserved_balance = p.amount
+FROM (
+    SELECT account_id, SUM(amount) AS amount
+    FROM payouts
+    WHERE status IN ('pending', 'processing')
+    GROUP BY account_id
+) p
+WHERE p.account_id = a.id;
+
+CREATE TABLE IF NOT EXISTS settlements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    invoice_id UUID NOT NULL REFERENCES invoices(id),
+    payment_method VARCHAR(20) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    available_on DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_settlements_due ON settlements(available_on) WHERE status = 'scheduled';
+CREATE INDEX idx_settlements_account_id ON settlements(account_id, available_on) WHERE status = 'scheduled';
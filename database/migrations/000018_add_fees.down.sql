@@ -0,0 +1,14 @@
+ALTER TABLE settlements
+    DROP COLUMN IF EXISTS fee_account_id,
+    DROP COLUMN IF EXISTS fee;
+
+DELETE FROM accounts WHERE id = '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS net_amount,
+    DROP COLUMN IF EXISTS fee_amount,
+    DROP COLUMN IF EXISTS fee_rule,
+    DROP COLUMN IF EXISTS installments;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS fee_schedule;
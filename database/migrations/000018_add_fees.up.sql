@@ -0,0 +1,22 @@
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS fee_schedule JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS installments INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS fee_rule JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS fee_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS net_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- Invoices issued before fees were charged kept their whole amount.
+UPDATE invoices
+SET net_amount = CASE WHEN paid_amount > 0 THEN paid_amount ELSE amount END;
+
+-- Internal account that collects the platform's fee revenue. It has no API
+-- key, so it can't be used to authenticate.
+INSERT INTO accounts (id, name, email, api_key)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Platform fees', 'fees@platform.internal', md5(random()::text))
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE settlements
+    ADD COLUMN IF NOT EXISTS fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fee_account_id UUID NULL REFERENCES accounts(id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_settlements_invoice_id;
+DROP TABLE IF EXISTS refunds;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS refunded_amount,
+    DROP COLUMN IF EXISTS splits;
@@ -0,0 +1,16 @@
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS splits JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS refunded_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(10,2) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    reversals JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refunds_invoice_id ON refunds(invoice_id);
+CREATE INDEX idx_settlements_invoice_id ON settlements(invoice_id);
@@ -0,0 +1,5 @@
+UPDATE api_keys
+SET scopes = array_remove(array_remove(scopes, 'transfers:read'), 'transfers:write');
+
+DROP TABLE IF EXISTS balance_transactions;
+DROP TABLE IF EXISTS transfers;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_account_id UUID NOT NULL REFERENCES accounts(id),
+    destination_account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(10,2) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    idempotency_key VARCHAR(255) NULL,
+    status VARCHAR(20) NOT NULL,
+    reversal_reason VARCHAR(255) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reversed_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX idx_transfers_idempotency_key ON transfers(source_account_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
+CREATE INDEX idx_transfers_source_account_id ON transfers(source_account_id, created_at DESC);
+CREATE INDEX idx_transfers_destination_account_id ON transfers(destination_account_id, created_at DESC);
+
+-- Debit and credit entries for every movement of the available balance.
+CREATE TABLE IF NOT EXISTS balance_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(10,2) NOT NULL,
+    balance_after DECIMAL(10,2) NOT NULL,
+    source_type VARCHAR(50) NOT NULL,
+    source_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_balance_transactions_account_id ON balance_transactions(account_id, created_at);
+
+UPDATE api_keys
+SET scopes = scopes || ARRAY['transfers:read', 'transfers:write']
+WHERE key IN (SELECT api_key FROM accounts)
+    AND NOT ('transfers:read' = ANY(scopes));
@@ -0,0 +1,10 @@
+UPDATE api_keys SET scopes = array_remove(scopes, 'balance:read');
+
+DROP INDEX IF EXISTS idx_balance_transactions_source;
+DROP INDEX IF EXISTS idx_balance_transactions_account_seq;
+
+DELETE FROM balance_transactions WHERE source_id IS NULL;
+ALTER TABLE balance_transactions ALTER COLUMN source_id SET NOT NULL;
+ALTER TABLE balance_transactions DROP COLUMN IF EXISTS description;
+ALTER TABLE balance_transactions DROP COLUMN IF EXISTS type;
+ALTER TABLE balance_transactions DROP COLUMN IF EXISTS seq;
@@ -0,0 +1,15 @@
+-- Entries are kept in the order they were applied, which created_at alone
+-- can't tell apart when one movement writes several of them.
+ALTER TABLE balance_transactions ADD COLUMN seq BIGSERIAL;
+ALTER TABLE balance_transactions ADD COLUMN type VARCHAR(30) NOT NULL DEFAULT 'transfer';
+ALTER TABLE balance_transactions ALTER COLUMN type DROP DEFAULT;
+ALTER TABLE balance_transactions ADD COLUMN description VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE balance_transactions ALTER COLUMN source_id DROP NOT NULL;
+
+CREATE INDEX idx_balance_transactions_account_seq ON balance_transactions(account_id, seq);
+CREATE INDEX idx_balance_transactions_source ON balance_transactions(source_type, source_id);
+
+UPDATE api_keys
+SET scopes = scopes || ARRAY['balance:read']
+WHERE key IN (SELECT api_key FROM accounts)
+    AND NOT ('balance:read' = ANY(scopes));
@@ -0,0 +1,5 @@
+UPDATE api_keys
+SET scopes = array_remove(array_remove(scopes, 'disputes:read'), 'disputes:write');
+
+DROP TABLE IF EXISTS dispute_evidence;
+DROP TABLE IF EXISTS disputes;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    reason VARCHAR(50) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    holds JSONB NOT NULL DEFAULT '[]',
+    deadline TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    submitted_at TIMESTAMP NULL,
+    resolved_at TIMESTAMP NULL
+);
+
+-- An invoice can only be contested once at a time.
+CREATE UNIQUE INDEX idx_disputes_open_invoice ON disputes(invoice_id) WHERE status IN ('needs_response', 'under_review');
+CREATE INDEX idx_disputes_account_id ON disputes(account_id, created_at DESC);
+CREATE INDEX idx_disputes_deadline ON disputes(deadline) WHERE status = 'needs_response';
+
+CREATE TABLE IF NOT EXISTS dispute_evidence (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dispute_id UUID NOT NULL REFERENCES disputes(id),
+    kind VARCHAR(10) NOT NULL,
+    text TEXT NOT NULL DEFAULT '',
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    size INTEGER NOT NULL DEFAULT 0,
+    content BYTEA NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_dispute_evidence_dispute_id ON dispute_evidence(dispute_id);
+
+UPDATE api_keys
+SET scopes = scopes || ARRAY['disputes:read', 'disputes:write']
+WHERE key IN (SELECT api_key FROM accounts)
+    AND NOT ('disputes:read' = ANY(scopes));
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS balance_holds;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS held_balance,
+    DROP COLUMN IF EXISTS reserve_policy;
@@ -0,0 +1,19 @@
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS held_balance DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reserve_policy JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS balance_holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    kind VARCHAR(20) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    invoice_id UUID NULL REFERENCES invoices(id),
+    status VARCHAR(20) NOT NULL,
+    release_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_balance_holds_account_id ON balance_holds(account_id, created_at DESC);
+CREATE INDEX idx_balance_holds_release_at ON balance_holds(release_at) WHERE status = 'active';
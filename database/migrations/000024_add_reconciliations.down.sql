@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_invoice_paid_at;
+DROP INDEX IF EXISTS idx_invoice_reference;
+DROP TABLE IF EXISTS reconciliations;
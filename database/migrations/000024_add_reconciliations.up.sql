@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS reconciliations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    period_from DATE NOT NULL,
+    period_to DATE NOT NULL,
+    matched INTEGER NOT NULL DEFAULT 0,
+    unmatched INTEGER NOT NULL DEFAULT 0,
+    mismatched INTEGER NOT NULL DEFAULT 0,
+    not_settled INTEGER NOT NULL DEFAULT 0,
+    missing INTEGER NOT NULL DEFAULT 0,
+    items JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reconciliations_period ON reconciliations(period_from, period_to);
+CREATE INDEX IF NOT EXISTS idx_invoice_reference ON invoices(reference) WHERE reference IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_invoice_paid_at ON invoices(paid_at) WHERE provider_charge_id IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP SEQUENCE IF EXISTS cnab_our_number_seq;
+DROP SEQUENCE IF EXISTS cnab_remittance_seq;
+DROP TABLE IF EXISTS cnab_files;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS cnab_files (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL,
+    sequence INTEGER NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content BYTEA NOT NULL,
+    items JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_cnab_files_kind ON cnab_files(kind, created_at DESC);
+
+CREATE SEQUENCE IF NOT EXISTS cnab_remittance_seq;
+CREATE SEQUENCE IF NOT EXISTS cnab_our_number_seq;
@@ -1,15 +1,40 @@
 package bootstrap
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"database/sql"
 
 	"github.com/NewLeonardooliv/gateway-payment/internal/config"
+	"github.com/NewLeonardooliv/gateway-payment/internal/jobs"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
 	account_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/account"
+	api_key_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/api_key"
+	audit_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/audit"
+	balance_hold_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_hold"
+	balance_transaction_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_transaction"
+	bank_statement_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/bank_statement"
+	card_vault_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/card_vault"
+	cnab_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/cnab"
+	dispute_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/dispute"
+	event_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/event"
 	invoice_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/invoice"
+	operator_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/operator"
+	payer_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/payer"
+	payment_method_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/payment_method"
+	payout_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/payout"
+	plan_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/plan"
+	reconciliation_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/reconciliation"
+	refund_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/refund"
+	settlement_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/settlement"
+	subscription_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/subscription"
+	transfer_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/transfer"
 	"github.com/NewLeonardooliv/gateway-payment/internal/service"
 	"github.com/NewLeonardooliv/gateway-payment/internal/shared"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/encryption"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/inter"
 	"github.com/NewLeonardooliv/gateway-payment/internal/web/server"
 )
 
@@ -24,19 +49,197 @@ func LoadWeb() {
 
 	defer db.Close()
 
+	apiKeyCacheTTL, err := time.ParseDuration(shared.GetEnv("API_KEY_CACHE_TTL", "30s"))
+	if err != nil {
+		log.Fatal("Invalid API_KEY_CACHE_TTL", err)
+	}
+
+	apiKeyRepository := api_key_repository.NewCachedAPIKeyRepository(
+		api_key_repository.NewAPIKeyRepository(db),
+		apiKeyCacheTTL,
+	)
+
+	auditRepository := audit_repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepository)
+
+	apiKeyService := service.NewAPIKeyService(apiKeyRepository, auditService)
+
+	eventRepository := event_repository.NewEventRepository(db)
+	eventService := service.NewEventService(eventRepository)
+
+	settlementSchedule, err := config.GetSettlementSchedule()
+	if err != nil {
+		log.Fatal("Invalid settlement schedule", err)
+	}
+
 	accountRepository := account_repository.NewAccountRepository(db)
-	accountService := service.NewAccountService(accountRepository)
 
-	interInvoiceRepository := invoice_repository.NewInterInvoiceRepository(
+	settlementRepository := settlement_repository.NewSettlementRepository(db)
+	settlementService := service.NewSettlementService(settlementRepository, accountRepository, settlementSchedule, config.PlatformAccountID, eventService, auditService)
+
+	feeSchedule, err := config.GetFeeSchedule()
+	if err != nil {
+		log.Fatal("Invalid fee schedule", err)
+	}
+
+	accountService := service.NewAccountService(accountRepository, apiKeyRepository, feeSchedule, settlementService, auditService)
+
+	interClient := inter.NewClient(
 		shared.GetEnv("INTERBANK_CLIENT_ID", ""),
 		shared.GetEnv("INTERBANK_CLIENT_SECRET", ""),
+		"boleto-cobranca.read boleto-cobranca.write pagamento-pix.write pagamento-pix.read extrato.read",
+	)
+
+	interInvoiceRepository := invoice_repository.NewInterInvoiceRepository(interClient)
+
+	keyManager, err := encryption.NewFileKeyManager(shared.GetEnv("ENCRYPTION_KEYRING_PATH", "cert/keyring.json"))
+	if err != nil {
+		log.Fatal("Error loading encryption keyring", err)
+	}
+
+	fieldCipher := encryption.NewFieldCipher(keyManager, keyManager.IndexKey())
+
+	payerRepository := payer_repository.NewPayerRepository(db, fieldCipher)
+	payerService := service.NewPayerService(payerRepository)
+	customerService := service.NewCustomerService(payerRepository, auditService)
+
+	paymentMethodRepository := payment_method_repository.NewPaymentMethodRepository(db)
+	cardVaultRepository := card_vault_repository.NewCardVaultRepository(db, fieldCipher)
+	paymentMethodService := service.NewPaymentMethodService(paymentMethodRepository, cardVaultRepository, customerService, auditService)
+
+	boletoProvider, err := config.GetBoletoProvider()
+	if err != nil {
+		log.Fatal("Invalid boleto provider", err)
+	}
+
+	invoiceRepository := invoice_repository.NewPostgresInvoiceRepository(db, fieldCipher)
+	refundRepository := refund_repository.NewRefundRepository(db)
+	invoiceService := service.NewInvoiceService(invoiceRepository, interInvoiceRepository, refundRepository, *accountService, customerService, paymentMethodService, settlementService, eventService, auditService, boletoProvider)
+
+	dunningPolicy, err := config.GetDunningPolicy()
+	if err != nil {
+		log.Fatal("Invalid dunning policy", err)
+	}
+
+	planRepository := plan_repository.NewPlanRepository(db)
+	subscriptionRepository := subscription_repository.NewSubscriptionRepository(db)
+	subscriptionService := service.NewSubscriptionService(planRepository, subscriptionRepository, customerService, paymentMethodService, invoiceService, eventService, auditService, dunningPolicy)
+
+	var payoutProvider repository.PayoutProvider = payout_repository.NewSimulatedPayoutProvider()
+	if shared.GetEnv("PAYOUT_PROVIDER", "simulator") == "inter" {
+		payoutProvider = payout_repository.NewInterPayoutProvider(interClient)
+	}
+
+	payoutDestinationRepository := payout_repository.NewPayoutDestinationRepository(db, fieldCipher)
+	payoutRepository := payout_repository.NewPayoutRepository(db)
+	payoutService := service.NewPayoutService(payoutDestinationRepository, payoutRepository, payoutProvider, *accountService, eventService, auditService)
+
+	transferRepository := transfer_repository.NewTransferRepository(db)
+	transferService := service.NewTransferService(transferRepository, *accountService, eventService, auditService)
+
+	balanceTransactionRepository := balance_transaction_repository.NewBalanceTransactionRepository(db)
+	balanceService := service.NewBalanceService(balanceTransactionRepository, accountRepository)
+
+	balanceHoldRepository := balance_hold_repository.NewBalanceHoldRepository(db)
+	balanceHoldService := service.NewBalanceHoldService(balanceHoldRepository, *accountService, eventService, auditService)
+
+	disputeRepository := dispute_repository.NewDisputeRepository(db)
+	disputeService := service.NewDisputeService(disputeRepository, invoiceService, eventService, auditService)
+
+	var statementProvider repository.BankStatementProvider = bank_statement_repository.NewFileStatementProvider(shared.GetEnv("STATEMENT_FILE_PATH", "cert/extrato.json"))
+	if shared.GetEnv("STATEMENT_PROVIDER", "file") == "inter" {
+		statementProvider = bank_statement_repository.NewInterStatementProvider(interClient)
+	}
+
+	reconciliationRepository := reconciliation_repository.NewReconciliationRepository(db)
+	reconciliationService := service.NewReconciliationService(reconciliationRepository, statementProvider, auditService)
+
+	cnabRepository := cnab_repository.NewCNABRepository(db)
+	cnabService := service.NewCNABService(cnabRepository, invoiceRepository, invoiceService, config.GetCNABCompany(), boletoProvider, auditService)
+
+	operatorRepository := operator_repository.NewOperatorRepository(db)
+	operatorService := service.NewOperatorService(operatorRepository, auditService, shared.GetEnv("ADMIN_JWT_SECRET", ""))
+
+	err = operatorService.Bootstrap(
+		context.Background(),
+		shared.GetEnv("ADMIN_BOOTSTRAP_EMAIL", ""),
+		shared.GetEnv("ADMIN_BOOTSTRAP_PASSWORD", ""),
 	)
 
-	invoiceService := service.NewInvoiceService(interInvoiceRepository, *accountService)
+	if err != nil {
+		log.Fatal("Error creating bootstrap operator", err)
+	}
+
+	reencryptionInterval, err := time.ParseDuration(shared.GetEnv("PAYER_REENCRYPTION_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatal("Invalid PAYER_REENCRYPTION_INTERVAL", err)
+	}
+
+	paymentMethodExpiryInterval, err := time.ParseDuration(shared.GetEnv("PAYMENT_METHOD_EXPIRY_INTERVAL", "6h"))
+	if err != nil {
+		log.Fatal("Invalid PAYMENT_METHOD_EXPIRY_INTERVAL", err)
+	}
+
+	subscriptionBillingInterval, err := time.ParseDuration(shared.GetEnv("SUBSCRIPTION_BILLING_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatal("Invalid SUBSCRIPTION_BILLING_INTERVAL", err)
+	}
+
+	dunningInterval, err := time.ParseDuration(shared.GetEnv("DUNNING_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatal("Invalid DUNNING_INTERVAL", err)
+	}
+
+	invoiceExpiryInterval, err := time.ParseDuration(shared.GetEnv("INVOICE_EXPIRY_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatal("Invalid INVOICE_EXPIRY_INTERVAL", err)
+	}
+
+	payoutInterval, err := time.ParseDuration(shared.GetEnv("PAYOUT_INTERVAL", "5m"))
+	if err != nil {
+		log.Fatal("Invalid PAYOUT_INTERVAL", err)
+	}
+
+	settlementInterval, err := time.ParseDuration(shared.GetEnv("SETTLEMENT_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatal("Invalid SETTLEMENT_INTERVAL", err)
+	}
+
+	disputeExpiryInterval, err := time.ParseDuration(shared.GetEnv("DISPUTE_EXPIRY_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatal("Invalid DISPUTE_EXPIRY_INTERVAL", err)
+	}
+
+	holdReleaseInterval, err := time.ParseDuration(shared.GetEnv("HOLD_RELEASE_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatal("Invalid HOLD_RELEASE_INTERVAL", err)
+	}
+
+	reconciliationInterval, err := time.ParseDuration(shared.GetEnv("RECONCILIATION_INTERVAL", "24h"))
+	if err != nil {
+		log.Fatal("Invalid RECONCILIATION_INTERVAL", err)
+	}
+
+	scheduler := jobs.NewScheduler()
+	scheduler.Every(reencryptionInterval, jobs.NewPayerReencryptionJob(payerService))
+	scheduler.Every(paymentMethodExpiryInterval, jobs.NewPaymentMethodExpiryJob(paymentMethodService))
+	scheduler.Every(subscriptionBillingInterval, jobs.NewSubscriptionBillingJob(subscriptionService))
+	scheduler.Every(dunningInterval, jobs.NewDunningJob(subscriptionService))
+	scheduler.Every(invoiceExpiryInterval, jobs.NewInvoiceExpiryJob(invoiceService))
+	scheduler.Every(payoutInterval, jobs.NewPayoutJob(payoutService))
+	scheduler.Every(settlementInterval, jobs.NewSettlementJob(settlementService))
+	scheduler.Every(disputeExpiryInterval, jobs.NewDisputeExpiryJob(disputeService))
+	scheduler.Every(holdReleaseInterval, jobs.NewBalanceHoldReleaseJob(balanceHoldService))
+	scheduler.Every(reconciliationInterval, jobs.NewReconciliationJob(reconciliationService))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scheduler.Start(ctx)
 
 	port := shared.GetEnv("HTTP_PORT", "8080")
 
-	server := server.NewServer(accountService, invoiceService, port)
+	server := server.NewServer(accountService, invoiceService, customerService, paymentMethodService, subscriptionService, eventService, payoutService, transferService, balanceService, disputeService, balanceHoldService, reconciliationService, cnabService, apiKeyService, operatorService, auditService, shared.GetEnv("INTERBANK_WEBHOOK_SECRET", ""), shared.GetEnv("ENV", "dev") != "prod", port)
 	server.ConfigureRoutes()
 
 	if err := server.Start(); err != nil {
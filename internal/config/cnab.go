@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/cnab"
+)
+
+// GetBoletoProvider reads who registers boletos with the bank. With "cnab"
+// new boletos skip Inter and wait for the next remittance.
+func GetBoletoProvider() (domain.BoletoProvider, error) {
+	provider := domain.BoletoProvider(shared.GetEnv("BOLETO_PROVIDER", string(domain.BoletoProviderInter)))
+
+	switch provider {
+	case domain.BoletoProviderInter, domain.BoletoProviderCNAB:
+		return provider, nil
+	default:
+		return "", fmt.Errorf("BOLETO_PROVIDER must be inter or cnab, got %q", provider)
+	}
+}
+
+// GetCNABCompany reads the boleto collection contract used in CNAB files.
+// Remittances can't be generated until the bank, tax id and agreement are
+// set.
+func GetCNABCompany() cnab.Company {
+	return cnab.Company{
+		BankCode:     shared.GetEnv("CNAB_BANK_CODE", ""),
+		BankName:     shared.GetEnv("CNAB_BANK_NAME", ""),
+		Name:         shared.GetEnv("CNAB_COMPANY_NAME", ""),
+		TaxID:        shared.GetEnv("CNAB_COMPANY_TAX_ID", ""),
+		Agreement:    shared.GetEnv("CNAB_AGREEMENT", ""),
+		Branch:       shared.GetEnv("CNAB_BRANCH", ""),
+		BranchDigit:  shared.GetEnv("CNAB_BRANCH_DIGIT", ""),
+		Account:      shared.GetEnv("CNAB_ACCOUNT", ""),
+		AccountDigit: shared.GetEnv("CNAB_ACCOUNT_DIGIT", ""),
+		Wallet:       shared.GetEnv("CNAB_WALLET", "1"),
+	}
+}
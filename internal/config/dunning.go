@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared"
+)
+
+// GetDunningPolicy reads the recurring charge recovery settings. Setting
+// DUNNING_FALLBACK_METHOD to "none" skips the boleto/Pix fallback.
+func GetDunningPolicy() (domain.DunningPolicy, error) {
+	var retryDays []int
+	for _, value := range strings.Split(shared.GetEnv("DUNNING_RETRY_DAYS", "1,3,7"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		day, err := strconv.Atoi(value)
+		if err != nil {
+			return domain.DunningPolicy{}, fmt.Errorf("%w: invalid retry day %q", domain.ErrInvalidDunningPolicy, value)
+		}
+
+		retryDays = append(retryDays, day)
+	}
+
+	fallbackMethod := domain.PaymentMethod(shared.GetEnv("DUNNING_FALLBACK_METHOD", "boleto"))
+	if fallbackMethod == "none" {
+		fallbackMethod = ""
+	}
+
+	fallbackDays, err := strconv.Atoi(shared.GetEnv("DUNNING_FALLBACK_DAYS", "5"))
+	if err != nil {
+		return domain.DunningPolicy{}, fmt.Errorf("%w: invalid fallback days", domain.ErrInvalidDunningPolicy)
+	}
+
+	finalStatus := domain.SubscriptionStatus(shared.GetEnv("DUNNING_FINAL_STATUS", "unpaid"))
+
+	return domain.NewDunningPolicy(retryDays, fallbackMethod, fallbackDays, finalStatus)
+}
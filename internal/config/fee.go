@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared"
+)
+
+// PlatformAccountID is the internal account created by the migrations that
+// collects the platform's fee revenue.
+const PlatformAccountID = "00000000-0000-0000-0000-000000000001"
+
+// GetFeeSchedule reads the default pricing from FEE_SCHEDULE as
+// method:percent+fixed pairs, e.g. "card:3.99+0.39,pix:0.99+0,boleto:0+3.49",
+// and the card installment surcharge from FEE_CARD_INSTALLMENT_PERCENT.
+// Methods left out are free.
+func GetFeeSchedule() (domain.FeeSchedule, error) {
+	schedule := domain.FeeSchedule{}
+
+	for _, entry := range strings.Split(shared.GetEnv("FEE_SCHEDULE", "card:3.99+0.39,pix:0.99+0,boleto:0+3.49"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, value, found := strings.Cut(entry, ":")
+		percentValue, fixedValue, _ := strings.Cut(value, "+")
+
+		percent, err := strconv.ParseFloat(strings.TrimSpace(percentValue), 64)
+		if !found || err != nil || percent < 0 {
+			return nil, fmt.Errorf("%w: invalid entry %q", domain.ErrInvalidFeeSchedule, entry)
+		}
+
+		var fixed float64
+		if strings.TrimSpace(fixedValue) != "" {
+			fixed, err = strconv.ParseFloat(strings.TrimSpace(fixedValue), 64)
+			if err != nil || fixed < 0 {
+				return nil, fmt.Errorf("%w: invalid entry %q", domain.ErrInvalidFeeSchedule, entry)
+			}
+		}
+
+		switch domain.PaymentMethod(strings.TrimSpace(method)) {
+		case domain.PaymentMethodBoleto, domain.PaymentMethodCard, domain.PaymentMethodPix:
+		default:
+			return nil, fmt.Errorf("%w: unknown payment method %q", domain.ErrInvalidFeeSchedule, method)
+		}
+
+		schedule[domain.PaymentMethod(strings.TrimSpace(method))] = domain.FeeRule{Percent: percent, Fixed: fixed}
+	}
+
+	installmentPercent, err := strconv.ParseFloat(shared.GetEnv("FEE_CARD_INSTALLMENT_PERCENT", "1.49"), 64)
+	if err != nil || installmentPercent < 0 {
+		return nil, fmt.Errorf("%w: invalid card installment percent", domain.ErrInvalidFeeSchedule)
+	}
+
+	card := schedule[domain.PaymentMethodCard]
+	card.InstallmentPercent = installmentPercent
+	schedule[domain.PaymentMethodCard] = card
+
+	return schedule, nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared"
+)
+
+// GetSettlementSchedule reads SETTLEMENT_DAYS as method:days pairs, e.g.
+// "card:30,pix:0,boleto:1". Methods left out settle on the same day.
+func GetSettlementSchedule() (domain.SettlementSchedule, error) {
+	schedule := domain.SettlementSchedule{}
+
+	for _, entry := range strings.Split(shared.GetEnv("SETTLEMENT_DAYS", "card:30,pix:0,boleto:1"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, value, found := strings.Cut(entry, ":")
+		days, err := strconv.Atoi(strings.TrimSpace(value))
+		if !found || err != nil || days < 0 {
+			return nil, fmt.Errorf("%w: invalid entry %q", domain.ErrInvalidSettlementSchedule, entry)
+		}
+
+		switch domain.PaymentMethod(strings.TrimSpace(method)) {
+		case domain.PaymentMethodBoleto, domain.PaymentMethodCard, domain.PaymentMethodPix:
+		default:
+			return nil, fmt.Errorf("%w: unknown payment method %q", domain.ErrInvalidSettlementSchedule, method)
+		}
+
+		schedule[domain.PaymentMethod(strings.TrimSpace(method))] = days
+	}
+
+	return schedule, nil
+}
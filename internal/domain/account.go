@@ -9,16 +9,46 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultInvoiceGraceDays matches the longest period Inter keeps a charge
+// payable after its due date.
+const (
+	DefaultInvoiceGraceDays = 60
+	MaxInvoiceGraceDays     = 60
+)
+
 type Account struct {
-	ID        string
-	Name      string
-	Email     string
-	APIKey    string
-	Balance   float64
-	mu        sync.RWMutex
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt time.Time
+	ID               string
+	Name             string
+	Email            string
+	APIKey           string
+	Balance          float64
+	PendingBalance   float64
+	ReservedBalance  float64
+	HeldBalance      float64
+	InvoiceGraceDays int
+	ChargeDefaults   ChargeDefaults
+	FeeSchedule      FeeSchedule
+	ReservePolicy    ReservePolicy
+	mu               sync.RWMutex
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        time.Time
+	FrozenAt         time.Time
+}
+
+// BalanceChange is a movement across an account's balances: Balance is what
+// is available, PendingBalance holds funds waiting to settle and
+// ReservedBalance funds committed elsewhere, such as payouts in flight.
+type BalanceChange struct {
+	Available float64
+	Pending   float64
+	Reserved  float64
+}
+
+type AccountFilter struct {
+	Query          string
+	IncludeDeleted bool
+	Limit          int
 }
 
 func generateAPIKey() string {
@@ -30,22 +60,167 @@ func generateAPIKey() string {
 
 func NewAccount(name, email string) *Account {
 	account := &Account{
-		ID:        uuid.New().String(),
-		Name:      name,
-		Email:     email,
-		APIKey:    generateAPIKey(),
-		Balance:   0,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:               uuid.New().String(),
+		Name:             name,
+		Email:            email,
+		APIKey:           generateAPIKey(),
+		Balance:          0,
+		InvoiceGraceDays: DefaultInvoiceGraceDays,
+		FeeSchedule:      FeeSchedule{},
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
 	}
 
 	return account
 }
 
-func (account *Account) AddBalance(amount float64) {
+func (account *Account) Update(name, email string) {
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	if name != "" {
+		account.Name = name
+	}
+
+	if email != "" {
+		account.Email = email
+	}
+
+	account.UpdatedAt = time.Now()
+}
+
+// SetInvoiceGraceDays sets how many days past the due date new invoices stay
+// payable before they expire.
+func (account *Account) SetInvoiceGraceDays(days int) error {
+	if days < 0 || days > MaxInvoiceGraceDays {
+		return ErrInvalidGraceDays
+	}
+
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	account.InvoiceGraceDays = days
+	account.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (account *Account) SetChargeDefaults(defaults ChargeDefaults) {
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	account.ChargeDefaults = defaults
+	account.UpdatedAt = time.Now()
+}
+
+// SetFeeSchedule replaces the account's pricing; methods it leaves out are
+// charged at the platform default.
+func (account *Account) SetFeeSchedule(schedule FeeSchedule) {
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	account.FeeSchedule = schedule
+	account.UpdatedAt = time.Now()
+}
+
+// SetReservePolicy replaces the account's rolling reserve. A zero policy
+// turns it off; holds already placed keep their release dates.
+func (account *Account) SetReservePolicy(policy ReservePolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
 	account.mu.Lock()
 	defer account.mu.Unlock()
 
-	account.Balance += amount
+	account.ReservePolicy = policy
 	account.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// HasNegativeBalance tells whether debits the account couldn't cover, such
+// as disputes, have left it owing money. Payouts stay blocked until then.
+func (account *Account) HasNegativeBalance() bool {
+	return account.Balance < 0
+}
+
+func (account *Account) IsDeleted() bool {
+	return !account.DeletedAt.IsZero()
+}
+
+func (account *Account) Delete() error {
+	if account.IsDeleted() {
+		return ErrAccountDeleted
+	}
+
+	account.DeletedAt = time.Now()
+	account.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (account *Account) Restore() error {
+	if !account.IsDeleted() {
+		return ErrAccountNotDeleted
+	}
+
+	account.DeletedAt = time.Time{}
+	account.APIKey = generateAPIKey()
+	account.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (account *Account) IsFrozen() bool {
+	return !account.FrozenAt.IsZero()
+}
+
+func (account *Account) Freeze() error {
+	if account.IsFrozen() {
+		return ErrAccountFrozen
+	}
+
+	account.FrozenAt = time.Now()
+	account.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (account *Account) Unfreeze() error {
+	if !account.IsFrozen() {
+		return ErrAccountNotFrozen
+	}
+
+	account.FrozenAt = time.Time{}
+	account.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (account *Account) Snapshot() map[string]any {
+	snapshot := map[string]any{
+		"name":               account.Name,
+		"email":              account.Email,
+		"balance":            account.Balance,
+		"pending_balance":    account.PendingBalance,
+		"reserved_balance":   account.ReservedBalance,
+		"held_balance":       account.HeldBalance,
+		"invoice_grace_days": account.InvoiceGraceDays,
+		"charge_defaults":    account.ChargeDefaults,
+		"fee_schedule":       account.FeeSchedule,
+		"reserve_policy":     account.ReservePolicy,
+		"deleted_at":         nil,
+		"frozen_at":          nil,
+	}
+
+	if account.IsDeleted() {
+		snapshot["deleted_at"] = account.DeletedAt
+	}
+
+	if account.IsFrozen() {
+		snapshot["frozen_at"] = account.FrozenAt
+	}
+
+	return snapshot
 }
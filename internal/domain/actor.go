@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+type ActorType string
+
+const (
+	ActorTypeAPIKey   ActorType = "api_key"
+	ActorTypeOperator ActorType = "operator"
+	ActorTypeSystem   ActorType = "system"
+)
+
+type actorContextKey struct{}
+
+type Actor struct {
+	Type ActorType
+	ID   string
+}
+
+var SystemActor = Actor{Type: ActorTypeSystem, ID: "system"}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorContextKey{}).(Actor); ok {
+		return actor
+	}
+
+	return SystemActor
+}
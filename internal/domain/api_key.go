@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Scope string
+
+const (
+	ScopeInvoicesRead       Scope = "invoices:read"
+	ScopeInvoicesWrite      Scope = "invoices:write"
+	ScopeRefundsWrite       Scope = "refunds:write"
+	ScopeAccountsRead       Scope = "accounts:read"
+	ScopeAccountsWrite      Scope = "accounts:write"
+	ScopeKeysWrite          Scope = "keys:write"
+	ScopeCustomersRead      Scope = "customers:read"
+	ScopeCustomersWrite     Scope = "customers:write"
+	ScopeSubscriptionsRead  Scope = "subscriptions:read"
+	ScopeSubscriptionsWrite Scope = "subscriptions:write"
+	ScopeEventsRead         Scope = "events:read"
+	ScopePayoutsRead        Scope = "payouts:read"
+	ScopePayoutsWrite       Scope = "payouts:write"
+	ScopeTransfersRead      Scope = "transfers:read"
+	ScopeTransfersWrite     Scope = "transfers:write"
+	ScopeBalanceRead        Scope = "balance:read"
+	ScopeDisputesRead       Scope = "disputes:read"
+	ScopeDisputesWrite      Scope = "disputes:write"
+)
+
+var AllScopes = []Scope{
+	ScopeInvoicesRead,
+	ScopeInvoicesWrite,
+	ScopeRefundsWrite,
+	ScopeAccountsRead,
+	ScopeAccountsWrite,
+	ScopeKeysWrite,
+	ScopeCustomersRead,
+	ScopeCustomersWrite,
+	ScopeSubscriptionsRead,
+	ScopeSubscriptionsWrite,
+	ScopeEventsRead,
+	ScopePayoutsRead,
+	ScopePayoutsWrite,
+	ScopeTransfersRead,
+	ScopeTransfersWrite,
+	ScopeBalanceRead,
+	ScopeDisputesRead,
+	ScopeDisputesWrite,
+}
+
+type KeyType string
+
+const (
+	KeyTypeSecret      KeyType = "secret"
+	KeyTypePublishable KeyType = "publishable"
+)
+
+// Publishable keys are meant to live in browsers and mobile apps, so they can
+// only ever create charges and only through methods that don't carry card data.
+var publishableScopes = map[Scope]bool{
+	ScopeInvoicesWrite: true,
+}
+
+var publishablePaymentMethods = map[PaymentMethod]bool{
+	PaymentMethodPix: true,
+}
+
+type APIKey struct {
+	ID        string
+	AccountID string
+	Key       string
+	Type      KeyType
+	Scopes    []Scope
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	RevokedAt time.Time
+	// AccountFrozen is only filled when the key is looked up for authentication.
+	AccountFrozen bool
+}
+
+func generateKey(keyType KeyType) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	prefix := "sk_"
+	if keyType == KeyTypePublishable {
+		prefix = "pk_"
+	}
+
+	return prefix + hex.EncodeToString(b)
+}
+
+func NewAPIKey(accountID string, keyType KeyType, scopes []Scope) (*APIKey, error) {
+	if keyType != KeyTypeSecret && keyType != KeyTypePublishable {
+		return nil, ErrInvalidKeyType
+	}
+
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	for _, scope := range scopes {
+		if !IsValidScope(scope) {
+			return nil, ErrInvalidScope
+		}
+
+		if keyType == KeyTypePublishable && !publishableScopes[scope] {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	return &APIKey{
+		ID:        uuid.New().String(),
+		AccountID: accountID,
+		Key:       generateKey(keyType),
+		Type:      keyType,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// NewMasterAPIKey wraps the key generated with the account so it keeps full
+// access to every scope.
+func NewMasterAPIKey(account *Account) *APIKey {
+	return &APIKey{
+		ID:        uuid.New().String(),
+		AccountID: account.ID,
+		Key:       account.APIKey,
+		Type:      KeyTypeSecret,
+		Scopes:    AllScopes,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func IsValidScope(scope Scope) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (key *APIKey) HasScope(scope Scope) bool {
+	for _, s := range key.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (keyType KeyType) CanCharge(method PaymentMethod) bool {
+	if keyType == KeyTypePublishable {
+		return publishablePaymentMethods[method]
+	}
+
+	return true
+}
+
+func (key *APIKey) IsRevoked() bool {
+	return !key.RevokedAt.IsZero()
+}
+
+func (key *APIKey) Revoke() error {
+	if key.IsRevoked() {
+		return ErrAPIKeyRevoked
+	}
+
+	key.RevokedAt = time.Now()
+	key.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (key *APIKey) Snapshot() map[string]any {
+	snapshot := map[string]any{
+		"account_id": key.AccountID,
+		"type":       key.Type,
+		"scopes":     key.Scopes,
+		"revoked_at": nil,
+	}
+
+	if key.IsRevoked() {
+		snapshot["revoked_at"] = key.RevokedAt
+	}
+
+	return snapshot
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEntry struct {
+	ID         string
+	Seq        int64
+	ActorType  ActorType
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     map[string]any
+	After      map[string]any
+	IP         string
+	RequestID  string
+	PrevHash   string
+	Hash       string
+	CreatedAt  time.Time
+}
+
+type AuditFilter struct {
+	TargetType string
+	TargetID   string
+	ActorType  ActorType
+	ActorID    string
+	Limit      int
+}
+
+func NewAuditEntry(actor Actor, info RequestInfo, action, targetType, targetID string, before, after map[string]any) *AuditEntry {
+	before, after = diff(before, after)
+
+	return &AuditEntry{
+		ID:         uuid.New().String(),
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+		IP:         info.IP,
+		RequestID:  info.RequestID,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// diff keeps only the fields that changed so entries show what an operation
+// actually did instead of two full snapshots.
+func diff(before, after map[string]any) (map[string]any, map[string]any) {
+	if before == nil || after == nil {
+		return before, after
+	}
+
+	changedBefore := map[string]any{}
+	changedAfter := map[string]any{}
+
+	for key, value := range after {
+		if previous, ok := before[key]; !ok || !reflect.DeepEqual(previous, value) {
+			changedAfter[key] = value
+			if ok {
+				changedBefore[key] = previous
+			}
+		}
+	}
+
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			changedBefore[key] = value
+		}
+	}
+
+	return changedBefore, changedAfter
+}
+
+// CanonicalJSON round-trips the snapshot through JSON so the same bytes come
+// out whether the map was built in memory or read back from the database.
+func CanonicalJSON(snapshot map[string]any) string {
+	if snapshot == nil {
+		return ""
+	}
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return ""
+	}
+
+	var normalized map[string]any
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return ""
+	}
+
+	b, _ = json.Marshal(normalized)
+
+	return string(b)
+}
+
+func (entry *AuditEntry) ComputeHash() string {
+	payload := strings.Join([]string{
+		entry.PrevHash,
+		entry.ID,
+		string(entry.ActorType),
+		entry.ActorID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		CanonicalJSON(entry.Before),
+		CanonicalJSON(entry.After),
+		entry.IP,
+		entry.RequestID,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}, "\n")
+
+	sum := sha256.Sum256([]byte(payload))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const MaxReserveDays = 180
+
+type BalanceHoldKind string
+
+const (
+	BalanceHoldRollingReserve BalanceHoldKind = "rolling_reserve"
+	BalanceHoldManual         BalanceHoldKind = "manual"
+)
+
+type BalanceHoldStatus string
+
+const (
+	BalanceHoldStatusActive   BalanceHoldStatus = "active"
+	BalanceHoldStatusReleased BalanceHoldStatus = "released"
+)
+
+// ReservePolicy withholds Percent of every settlement of the account for
+// Days after it would have become available.
+type ReservePolicy struct {
+	Percent float64 `json:"percent"`
+	Days    int     `json:"days"`
+}
+
+func (policy ReservePolicy) IsEnabled() bool {
+	return policy.Percent > 0 && policy.Days > 0
+}
+
+func (policy ReservePolicy) Validate() error {
+	if policy == (ReservePolicy{}) {
+		return nil
+	}
+
+	if policy.Percent <= 0 || policy.Percent > 100 || policy.Days <= 0 || policy.Days > MaxReserveDays {
+		return ErrInvalidReservePolicy
+	}
+
+	return nil
+}
+
+// BalanceHold keeps money in the account's held balance, out of reach of
+// payouts and transfers, until ReleaseAt. A manual hold without ReleaseAt
+// stays until an operator releases it.
+type BalanceHold struct {
+	ID         string
+	AccountID  string
+	Kind       BalanceHoldKind
+	Amount     float64
+	Reason     string
+	InvoiceID  string
+	Status     BalanceHoldStatus
+	ReleaseAt  time.Time
+	CreatedAt  time.Time
+	ReleasedAt time.Time
+}
+
+type BalanceHoldFilter struct {
+	AccountID string
+	Status    BalanceHoldStatus
+	Limit     int
+}
+
+func NewManualHold(accountID string, amount float64, reason string, releaseAt time.Time) (*BalanceHold, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	return &BalanceHold{
+		ID:        uuid.New().String(),
+		AccountID: accountID,
+		Kind:      BalanceHoldManual,
+		Amount:    amount,
+		Reason:    reason,
+		Status:    BalanceHoldStatusActive,
+		ReleaseAt: releaseAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (hold *BalanceHold) IsDueAt(t time.Time) bool {
+	return hold.Status == BalanceHoldStatusActive && !hold.ReleaseAt.IsZero() && !hold.ReleaseAt.After(t)
+}
+
+func (hold *BalanceHold) Release() error {
+	if hold.Status != BalanceHoldStatusActive {
+		return ErrInvalidHoldStatus
+	}
+
+	hold.Status = BalanceHoldStatusReleased
+	hold.ReleasedAt = time.Now()
+
+	return nil
+}
+
+func (hold *BalanceHold) Snapshot() map[string]any {
+	return map[string]any{
+		"account_id": hold.AccountID,
+		"kind":       hold.Kind,
+		"amount":     hold.Amount,
+		"reason":     hold.Reason,
+		"invoice_id": hold.InvoiceID,
+		"status":     hold.Status,
+		"release_at": hold.ReleaseAt,
+	}
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BalanceTransactionType string
+
+const (
+	BalanceTransactionCharge        BalanceTransactionType = "charge"
+	BalanceTransactionFee           BalanceTransactionType = "fee"
+	BalanceTransactionFeeRevenue    BalanceTransactionType = "fee_revenue"
+	BalanceTransactionRefund        BalanceTransactionType = "refund"
+	BalanceTransactionPayout        BalanceTransactionType = "payout"
+	BalanceTransactionPayoutFailure BalanceTransactionType = "payout_failure"
+	BalanceTransactionAdjustment    BalanceTransactionType = "adjustment"
+	BalanceTransactionTransfer      BalanceTransactionType = "transfer"
+	BalanceTransactionDispute       BalanceTransactionType = "dispute"
+	BalanceTransactionDisputeWon    BalanceTransactionType = "dispute_won"
+	BalanceTransactionHold          BalanceTransactionType = "hold"
+	BalanceTransactionHoldRelease   BalanceTransactionType = "hold_release"
+)
+
+// BalanceTransaction is one movement of an account's available balance.
+// Amount is signed and BalanceAfter is the available balance right after it
+// was applied, so a list of entries reads like a bank statement.
+type BalanceTransaction struct {
+	ID           string
+	AccountID    string
+	Type         BalanceTransactionType
+	Amount       float64
+	BalanceAfter float64
+	SourceType   string
+	SourceID     string
+	Description  string
+	CreatedAt    time.Time
+}
+
+type BalanceTransactionFilter struct {
+	AccountID  string
+	Type       BalanceTransactionType
+	SourceType string
+	SourceID   string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// NewBalanceTransaction describes a movement before it is applied. The
+// repository applying it fills in the account, amount and resulting balance.
+func NewBalanceTransaction(transactionType BalanceTransactionType, sourceType, sourceID, description string) *BalanceTransaction {
+	return &BalanceTransaction{
+		ID:          uuid.New().String(),
+		Type:        transactionType,
+		SourceType:  sourceType,
+		SourceID:    sourceID,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Statement is an account's balance transactions over one calendar month.
+type Statement struct {
+	AccountID      string
+	AccountName    string
+	Month          time.Time
+	OpeningBalance float64
+	ClosingBalance float64
+	Credits        float64
+	Debits         float64
+	Transactions   []*BalanceTransaction
+}
+
+func NewStatement(account *Account, month time.Time, openingBalance float64, transactions []*BalanceTransaction) *Statement {
+	statement := &Statement{
+		AccountID:      account.ID,
+		AccountName:    account.Name,
+		Month:          month,
+		OpeningBalance: openingBalance,
+		ClosingBalance: openingBalance,
+		Transactions:   transactions,
+	}
+
+	for _, transaction := range transactions {
+		if transaction.Amount >= 0 {
+			statement.Credits = roundCents(statement.Credits + transaction.Amount)
+		} else {
+			statement.Debits = roundCents(statement.Debits - transaction.Amount)
+		}
+
+		statement.ClosingBalance = transaction.BalanceAfter
+	}
+
+	return statement
+}
+
+func IsValidBalanceTransactionType(transactionType BalanceTransactionType) bool {
+	switch transactionType {
+	case BalanceTransactionCharge, BalanceTransactionFee, BalanceTransactionFeeRevenue, BalanceTransactionRefund,
+		BalanceTransactionPayout, BalanceTransactionPayoutFailure, BalanceTransactionAdjustment, BalanceTransactionTransfer,
+		BalanceTransactionDispute, BalanceTransactionDisputeWon, BalanceTransactionHold, BalanceTransactionHoldRelease:
+		return true
+	}
+
+	return false
+}
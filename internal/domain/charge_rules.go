@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+type AdjustmentType string
+
+const (
+	AdjustmentFixed   AdjustmentType = "fixed"
+	AdjustmentPercent AdjustmentType = "percent"
+)
+
+const MaxDiscountTiers = 3
+
+// Fine is charged once as soon as the invoice is paid late.
+type Fine struct {
+	Type  AdjustmentType `json:"type"`
+	Value float64        `json:"value"`
+}
+
+// Interest accrues for every day past the due date: a fixed amount per day,
+// or a monthly percentage charged pro rata die.
+type Interest struct {
+	Type  AdjustmentType `json:"type"`
+	Value float64        `json:"value"`
+}
+
+// Discount applies when the invoice is paid on or before Until.
+type Discount struct {
+	Type  AdjustmentType `json:"type"`
+	Value float64        `json:"value"`
+	Until time.Time      `json:"until"`
+}
+
+// DiscountRule is an account-level discount, anchored to each invoice's due
+// date instead of a fixed day.
+type DiscountRule struct {
+	Type          AdjustmentType `json:"type"`
+	Value         float64        `json:"value"`
+	DaysBeforeDue int            `json:"days_before_due"`
+}
+
+type ChargeDefaults struct {
+	Fine      *Fine          `json:"fine,omitempty"`
+	Interest  *Interest      `json:"interest,omitempty"`
+	Discounts []DiscountRule `json:"discounts,omitempty"`
+}
+
+type ChargeBreakdown struct {
+	Amount   float64
+	Discount float64
+	Fine     float64
+	Interest float64
+	Total    float64
+}
+
+func (rules ChargeDefaults) IsZero() bool {
+	return rules.Fine == nil && rules.Interest == nil && len(rules.Discounts) == 0
+}
+
+// ApplyChargeDefaults fills in whatever the invoice didn't set itself.
+// Default discounts that would already have lapsed, or that would wipe out
+// the whole amount, are left out.
+func (invoice *Invoice) ApplyChargeDefaults(defaults ChargeDefaults, today time.Time) {
+	if invoice.Fine == nil {
+		invoice.Fine = defaults.Fine
+	}
+
+	if invoice.Interest == nil {
+		invoice.Interest = defaults.Interest
+	}
+
+	if len(invoice.Discounts) == 0 {
+		for _, rule := range defaults.Discounts {
+			until := invoice.DueDate.AddDate(0, 0, -rule.DaysBeforeDue)
+			if until.Before(today) || (rule.Type == AdjustmentFixed && rule.Value >= invoice.Amount) {
+				continue
+			}
+
+			invoice.Discounts = append(invoice.Discounts, Discount{
+				Type:  rule.Type,
+				Value: rule.Value,
+				Until: until,
+			})
+		}
+	}
+}
+
+// AmountDueAt computes what the payer owes when paying on paidAt: the best
+// discount still available when early or on time, fine plus interest for
+// each day late otherwise.
+func (invoice *Invoice) AmountDueAt(paidAt time.Time) ChargeBreakdown {
+	breakdown := ChargeBreakdown{Amount: invoice.Amount}
+	paidOn := dateOf(paidAt)
+	daysLate := int(paidOn.Sub(dateOf(invoice.DueDate)).Hours() / 24)
+
+	if daysLate <= 0 {
+		for _, discount := range invoice.Discounts {
+			if paidOn.After(dateOf(discount.Until)) {
+				continue
+			}
+
+			breakdown.Discount = math.Max(breakdown.Discount, adjustment(discount.Type, discount.Value, invoice.Amount))
+		}
+	} else {
+		if invoice.Fine != nil {
+			breakdown.Fine = adjustment(invoice.Fine.Type, invoice.Fine.Value, invoice.Amount)
+		}
+
+		if invoice.Interest != nil {
+			daily := invoice.Interest.Value
+			if invoice.Interest.Type == AdjustmentPercent {
+				daily = invoice.Amount * invoice.Interest.Value / 100 / 30
+			}
+
+			breakdown.Interest = roundCents(daily * float64(daysLate))
+		}
+	}
+
+	breakdown.Total = roundCents(invoice.Amount - breakdown.Discount + breakdown.Fine + breakdown.Interest)
+
+	return breakdown
+}
+
+func adjustment(adjustmentType AdjustmentType, value, amount float64) float64 {
+	if adjustmentType == AdjustmentPercent {
+		return roundCents(amount * value / 100)
+	}
+
+	return roundCents(value)
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxRemittanceTitles bounds how many boletos a single remittance
+// registers; what is left goes in the next one.
+const MaxRemittanceTitles = 1000
+
+// BoletoProvider is who registers boletos with the bank: Inter's API as each
+// invoice is created, or the CNAB remittance files generated afterwards.
+type BoletoProvider string
+
+const (
+	BoletoProviderInter BoletoProvider = "inter"
+	BoletoProviderCNAB  BoletoProvider = "cnab"
+)
+
+type CNABFileKind string
+
+const (
+	CNABFileRemittance CNABFileKind = "remittance"
+	CNABFileReturn     CNABFileKind = "return"
+)
+
+type CNABOutcome string
+
+const (
+	CNABOutcomeRegistered CNABOutcome = "registered"
+	CNABOutcomeSettled    CNABOutcome = "settled"
+	CNABOutcomeRejected   CNABOutcome = "rejected"
+	CNABOutcomeIgnored    CNABOutcome = "ignored"
+	CNABOutcomeNotFound   CNABOutcome = "not_found"
+	CNABOutcomeFailed     CNABOutcome = "failed"
+)
+
+// CNABFile is a remittance the gateway generated or a return the bank sent,
+// kept with its content so it can be downloaded again. Items lists what
+// each title in it meant for the gateway's invoices.
+type CNABFile struct {
+	ID        string
+	Kind      CNABFileKind
+	Sequence  int
+	FileName  string
+	Content   []byte
+	Items     []CNABFileItem
+	CreatedAt time.Time
+}
+
+type CNABFileItem struct {
+	OurNumber  string      `json:"our_number"`
+	InvoiceID  string      `json:"invoice_id,omitempty"`
+	Movement   string      `json:"movement,omitempty"`
+	Amount     float64     `json:"amount"`
+	PaidAmount float64     `json:"paid_amount,omitempty"`
+	Outcome    CNABOutcome `json:"outcome"`
+	Note       string      `json:"note,omitempty"`
+}
+
+type CNABFileFilter struct {
+	Kind  CNABFileKind
+	Limit int
+}
+
+func NewCNABFile(kind CNABFileKind, sequence int, fileName string, content []byte, items []CNABFileItem) *CNABFile {
+	return &CNABFile{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Sequence:  sequence,
+		FileName:  fileName,
+		Content:   content,
+		Items:     items,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customers are the account-scoped payers that invoices point at, so repeat
+// buyers keep a single record and history instead of one payer per invoice.
+type CustomerFilter struct {
+	AccountID      string
+	TaxID          string
+	Email          string
+	Query          string
+	IncludeDeleted bool
+	Limit          int
+}
+
+type CustomerTotals struct {
+	InvoiceCount   int
+	TotalAmount    float64
+	ApprovedAmount float64
+	LastInvoiceAt  time.Time
+}
+
+func NewCustomer(accountID string, details Payer) *Payer {
+	details.ID = uuid.New().String()
+	details.AccountID = accountID
+	details.CreatedAt = time.Now()
+	details.UpdatedAt = time.Now()
+	details.DeletedAt = time.Time{}
+
+	return &details
+}
+
+// Update copies the non-empty details over the customer and reports whether
+// anything actually changed.
+func (payer *Payer) Update(details Payer) bool {
+	changed := false
+
+	fields := payer.SensitiveFields()
+	for name, value := range details.SensitiveFields() {
+		if *value != "" && *value != *fields[name] {
+			*fields[name] = *value
+			changed = true
+		}
+	}
+
+	if details.Name != "" && details.Name != payer.Name {
+		payer.Name = details.Name
+		changed = true
+	}
+
+	if changed {
+		payer.UpdatedAt = time.Now()
+	}
+
+	return changed
+}
+
+func (payer *Payer) IsDeleted() bool {
+	return !payer.DeletedAt.IsZero()
+}
+
+func (payer *Payer) Delete() error {
+	if payer.IsDeleted() {
+		return ErrCustomerDeleted
+	}
+
+	payer.DeletedAt = time.Now()
+	payer.UpdatedAt = payer.DeletedAt
+
+	return nil
+}
+
+// Snapshot leaves contact details out on purpose: the audit log is stored in
+// plaintext and must not undo the payer field encryption.
+func (payer *Payer) Snapshot() map[string]any {
+	snapshot := map[string]any{
+		"account_id": payer.AccountID,
+		"name":       payer.Name,
+		"deleted_at": nil,
+	}
+
+	if payer.IsDeleted() {
+		snapshot["deleted_at"] = payer.DeletedAt
+	}
+
+	return snapshot
+}
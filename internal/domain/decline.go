@@ -0,0 +1,34 @@
+package domain
+
+type DeclineCode string
+
+const (
+	DeclineInsufficientFunds        DeclineCode = "insufficient_funds"
+	DeclineDoNotHonor               DeclineCode = "do_not_honor"
+	DeclineProcessingError          DeclineCode = "processing_error"
+	DeclineProviderUnavailable      DeclineCode = "provider_unavailable"
+	DeclineExpiredCard              DeclineCode = "expired_card"
+	DeclineLostOrStolenCard         DeclineCode = "lost_or_stolen_card"
+	DeclineInvalidCard              DeclineCode = "invalid_card"
+	DeclinePaymentMethodUnavailable DeclineCode = "payment_method_unavailable"
+)
+
+var cardDeclineCodes = []DeclineCode{
+	DeclineInsufficientFunds,
+	DeclineDoNotHonor,
+	DeclineProcessingError,
+	DeclineExpiredCard,
+	DeclineLostOrStolenCard,
+	DeclineInvalidCard,
+}
+
+// IsRetryable separates soft declines, which may clear up on a later attempt,
+// from hard ones where charging the same method again is pointless.
+func (code DeclineCode) IsRetryable() bool {
+	switch code {
+	case DeclineExpiredCard, DeclineLostOrStolenCard, DeclineInvalidCard, DeclinePaymentMethodUnavailable:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DisputeResponseDays is how long a merchant has to answer a new dispute
+// with evidence.
+const DisputeResponseDays = 7
+
+type DisputeStatus string
+
+const (
+	DisputeStatusNeedsResponse DisputeStatus = "needs_response"
+	DisputeStatusUnderReview   DisputeStatus = "under_review"
+	DisputeStatusWon           DisputeStatus = "won"
+	DisputeStatusLost          DisputeStatus = "lost"
+)
+
+type DisputeReason string
+
+const (
+	DisputeReasonFraudulent           DisputeReason = "fraudulent"
+	DisputeReasonUnrecognized         DisputeReason = "unrecognized"
+	DisputeReasonDuplicate            DisputeReason = "duplicate"
+	DisputeReasonProductNotReceived   DisputeReason = "product_not_received"
+	DisputeReasonProductUnacceptable  DisputeReason = "product_unacceptable"
+	DisputeReasonCreditNotProcessed   DisputeReason = "credit_not_processed"
+	DisputeReasonSubscriptionCanceled DisputeReason = "subscription_canceled"
+	DisputeReasonGeneral              DisputeReason = "general"
+)
+
+// Dispute is a cardholder contesting a card payment with their bank. While it
+// is open the disputed amount is held in the reserved balance of the accounts
+// liable for chargebacks; Holds says how much each one put up.
+type Dispute struct {
+	ID          string
+	InvoiceID   string
+	AccountID   string
+	Reason      DisputeReason
+	Amount      float64
+	Status      DisputeStatus
+	Holds       []DisputeHold
+	Deadline    time.Time
+	CreatedAt   time.Time
+	SubmittedAt time.Time
+	ResolvedAt  time.Time
+}
+
+type DisputeHold struct {
+	AccountID string  `json:"account_id"`
+	Amount    float64 `json:"amount"`
+}
+
+type DisputeEvidenceKind string
+
+const (
+	DisputeEvidenceText DisputeEvidenceKind = "text"
+	DisputeEvidenceFile DisputeEvidenceKind = "file"
+)
+
+// DisputeEvidence is a statement or a document the merchant sends to contest
+// a dispute. Content is only loaded when the file itself is asked for.
+type DisputeEvidence struct {
+	ID          string
+	DisputeID   string
+	Kind        DisputeEvidenceKind
+	Text        string
+	FileName    string
+	ContentType string
+	Size        int
+	Content     []byte
+	CreatedAt   time.Time
+}
+
+type DisputeFilter struct {
+	AccountID string
+	InvoiceID string
+	Status    DisputeStatus
+	Limit     int
+}
+
+// OpenDispute contests amount of a paid card invoice. The accounts liable for
+// chargebacks put it up in proportion to their gross share, with rounding
+// left to the first of them.
+func (invoice *Invoice) OpenDispute(reason DisputeReason, amount float64) (*Dispute, error) {
+	if invoice.Status != StatusApproved || PaymentMethod(invoice.PaymentType) != PaymentMethodCard {
+		return nil, ErrInvalidStatus
+	}
+
+	if amount <= 0 || amount > roundCents(invoice.PaidAmount-invoice.RefundedAmount) {
+		return nil, ErrInvalidDisputeAmount
+	}
+
+	var liable []SplitShare
+	var base float64
+	for _, share := range invoice.Shares(invoice.PaidAmount, invoice.FeeAmount) {
+		if share.ChargebackLiable {
+			liable = append(liable, share)
+			base += share.Gross
+		}
+	}
+
+	holds := make([]DisputeHold, len(liable))
+	left := amount
+
+	for i := len(liable) - 1; i >= 0; i-- {
+		portion := left
+		if i > 0 && base > 0 {
+			portion = roundCents(amount * liable[i].Gross / base)
+		}
+
+		holds[i] = DisputeHold{AccountID: liable[i].AccountID, Amount: portion}
+		left = roundCents(left - portion)
+	}
+
+	now := time.Now()
+
+	return &Dispute{
+		ID:        uuid.New().String(),
+		InvoiceID: invoice.ID,
+		AccountID: invoice.AccountID,
+		Reason:    reason,
+		Amount:    amount,
+		Status:    DisputeStatusNeedsResponse,
+		Holds:     holds,
+		Deadline:  now.AddDate(0, 0, DisputeResponseDays),
+		CreatedAt: now,
+	}, nil
+}
+
+func (dispute *Dispute) IsOpen() bool {
+	return dispute.Status == DisputeStatusNeedsResponse || dispute.Status == DisputeStatusUnderReview
+}
+
+func (dispute *Dispute) IsOverdueAt(t time.Time) bool {
+	return dispute.Status == DisputeStatusNeedsResponse && t.After(dispute.Deadline)
+}
+
+// CanTakeEvidence reports whether the merchant can still add evidence.
+func (dispute *Dispute) CanTakeEvidence() error {
+	if dispute.Status != DisputeStatusNeedsResponse {
+		return ErrInvalidDisputeStatus
+	}
+
+	if dispute.IsOverdueAt(time.Now()) {
+		return ErrDisputeDeadlinePassed
+	}
+
+	return nil
+}
+
+// Submit hands the evidence over for review; none can be added afterwards.
+func (dispute *Dispute) Submit() error {
+	if err := dispute.CanTakeEvidence(); err != nil {
+		return err
+	}
+
+	dispute.Status = DisputeStatusUnderReview
+	dispute.SubmittedAt = time.Now()
+
+	return nil
+}
+
+// Resolve closes the dispute as won or lost.
+func (dispute *Dispute) Resolve(outcome DisputeStatus) error {
+	if outcome != DisputeStatusWon && outcome != DisputeStatusLost {
+		return ErrInvalidDisputeStatus
+	}
+
+	if !dispute.IsOpen() {
+		return ErrInvalidDisputeStatus
+	}
+
+	dispute.Status = outcome
+	dispute.ResolvedAt = time.Now()
+
+	return nil
+}
+
+func NewDisputeTextEvidence(disputeID, text string) *DisputeEvidence {
+	return &DisputeEvidence{
+		ID:        uuid.New().String(),
+		DisputeID: disputeID,
+		Kind:      DisputeEvidenceText,
+		Text:      text,
+		CreatedAt: time.Now(),
+	}
+}
+
+func NewDisputeFileEvidence(disputeID, fileName, contentType string, content []byte) *DisputeEvidence {
+	return &DisputeEvidence{
+		ID:          uuid.New().String(),
+		DisputeID:   disputeID,
+		Kind:        DisputeEvidenceFile,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        len(content),
+		Content:     content,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func IsValidDisputeReason(reason DisputeReason) bool {
+	switch reason {
+	case DisputeReasonFraudulent, DisputeReasonUnrecognized, DisputeReasonDuplicate, DisputeReasonProductNotReceived,
+		DisputeReasonProductUnacceptable, DisputeReasonCreditNotProcessed, DisputeReasonSubscriptionCanceled, DisputeReasonGeneral:
+		return true
+	}
+
+	return false
+}
+
+func (dispute *Dispute) Snapshot() map[string]any {
+	return map[string]any{
+		"invoice_id": dispute.InvoiceID,
+		"reason":     dispute.Reason,
+		"amount":     dispute.Amount,
+		"status":     dispute.Status,
+		"holds":      dispute.Holds,
+		"deadline":   dispute.Deadline,
+	}
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type DunningStep string
+
+const (
+	DunningStepRetry    DunningStep = "retry"
+	DunningStepFallback DunningStep = "fallback"
+	DunningStepRemind   DunningStep = "remind"
+	DunningStepFinal    DunningStep = "final"
+)
+
+// DunningPolicy drives recovery of a failed recurring charge: the saved
+// method is retried on RetryDays (counted from the first failure), then an
+// invoice is issued with FallbackMethod due in FallbackDays, and if that goes
+// unpaid the subscription ends up in FinalStatus.
+type DunningPolicy struct {
+	RetryDays      []int
+	FallbackMethod PaymentMethod
+	FallbackDays   int
+	FinalStatus    SubscriptionStatus
+}
+
+func NewDunningPolicy(retryDays []int, fallbackMethod PaymentMethod, fallbackDays int, finalStatus SubscriptionStatus) (DunningPolicy, error) {
+	for i, day := range retryDays {
+		if day <= 0 || (i > 0 && day <= retryDays[i-1]) {
+			return DunningPolicy{}, fmt.Errorf("%w: retry days must be positive and increasing", ErrInvalidDunningPolicy)
+		}
+	}
+
+	switch fallbackMethod {
+	case "", PaymentMethodBoleto, PaymentMethodPix:
+	default:
+		return DunningPolicy{}, fmt.Errorf("%w: fallback method must be boleto or pix", ErrInvalidDunningPolicy)
+	}
+
+	if fallbackDays <= 0 {
+		return DunningPolicy{}, fmt.Errorf("%w: fallback days must be positive", ErrInvalidDunningPolicy)
+	}
+
+	switch finalStatus {
+	case SubscriptionStatusPastDue, SubscriptionStatusUnpaid, SubscriptionStatusCancelled:
+	default:
+		return DunningPolicy{}, fmt.Errorf("%w: final status must be past_due, unpaid or cancelled", ErrInvalidDunningPolicy)
+	}
+
+	return DunningPolicy{
+		RetryDays:      retryDays,
+		FallbackMethod: fallbackMethod,
+		FallbackDays:   fallbackDays,
+		FinalStatus:    finalStatus,
+	}, nil
+}
+
+func (subscription *Subscription) InDunning() bool {
+	return subscription.DunningStep != ""
+}
+
+// PaymentFailed opens dunning for a rejected period charge. While dunning is
+// already running the amount is added to what is owed and the schedule stays.
+func (subscription *Subscription) PaymentFailed(policy DunningPolicy, amount float64, code DeclineCode, now time.Time) {
+	subscription.Status = SubscriptionStatusPastDue
+	subscription.UpdatedAt = now
+
+	if subscription.InDunning() {
+		subscription.DunningAmount = roundCents(subscription.DunningAmount + amount)
+		return
+	}
+
+	subscription.DunningStartedAt = now
+	subscription.DunningAmount = amount
+	subscription.DunningAttempts = 1
+	subscription.scheduleAfterFailure(policy, code, now)
+}
+
+func (subscription *Subscription) RetryFailed(policy DunningPolicy, code DeclineCode, now time.Time) {
+	subscription.DunningAttempts++
+	subscription.UpdatedAt = now
+	subscription.scheduleAfterFailure(policy, code, now)
+}
+
+// scheduleAfterFailure keeps retrying the saved method on soft declines and
+// moves straight to the fallback once retries run out or the decline is hard.
+func (subscription *Subscription) scheduleAfterFailure(policy DunningPolicy, code DeclineCode, now time.Time) {
+	retries := subscription.DunningAttempts - 1
+
+	switch {
+	case code.IsRetryable() && retries < len(policy.RetryDays):
+		subscription.schedule(DunningStepRetry, subscription.DunningStartedAt.AddDate(0, 0, policy.RetryDays[retries]))
+	case policy.FallbackMethod != "":
+		subscription.schedule(DunningStepFallback, now)
+	default:
+		subscription.schedule(DunningStepFinal, now)
+	}
+}
+
+// FallbackIssued follows the boleto or Pix invoice sent after card retries:
+// a reminder goes out the day before it is due and dunning ends the day after.
+func (subscription *Subscription) FallbackIssued(invoice *Invoice, now time.Time) {
+	subscription.LatestInvoiceID = invoice.ID
+	subscription.UpdatedAt = now
+
+	if invoice.Status == StatusRejected {
+		subscription.schedule(DunningStepFinal, now)
+		return
+	}
+
+	subscription.schedule(DunningStepRemind, invoice.DueDate.AddDate(0, 0, -1))
+}
+
+func (subscription *Subscription) ReminderSent(invoice *Invoice, now time.Time) {
+	subscription.UpdatedAt = now
+	subscription.schedule(DunningStepFinal, invoice.DueDate.AddDate(0, 0, 1))
+}
+
+func (subscription *Subscription) PaymentRecovered(invoice *Invoice, now time.Time) {
+	subscription.LatestInvoiceID = invoice.ID
+	subscription.Status = SubscriptionStatusActive
+	subscription.UpdatedAt = now
+	subscription.clearDunning()
+}
+
+// ExhaustDunning gives up on the owed amount and applies the policy's final
+// status. Unpaid subscriptions stop renewing until they are resumed.
+func (subscription *Subscription) ExhaustDunning(policy DunningPolicy, now time.Time) {
+	subscription.Status = policy.FinalStatus
+	subscription.UpdatedAt = now
+	subscription.clearDunning()
+
+	if policy.FinalStatus == SubscriptionStatusCancelled {
+		subscription.CancelledAt = now
+	}
+}
+
+func (subscription *Subscription) schedule(step DunningStep, at time.Time) {
+	subscription.DunningStep = step
+	subscription.NextRetryAt = at
+}
+
+func (subscription *Subscription) clearDunning() {
+	subscription.DunningStep = ""
+	subscription.DunningAttempts = 0
+	subscription.DunningAmount = 0
+	subscription.DunningStartedAt = time.Time{}
+	subscription.NextRetryAt = time.Time{}
+}
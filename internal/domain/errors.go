@@ -2,12 +2,137 @@ package domain
 
 import "errors"
 
+type ErrorKind string
+
+const (
+	KindValidation          ErrorKind = "validation"
+	KindUnauthorized        ErrorKind = "unauthorized"
+	KindForbidden           ErrorKind = "forbidden"
+	KindNotFound            ErrorKind = "not_found"
+	KindConflict            ErrorKind = "conflict"
+	KindProviderUnavailable ErrorKind = "provider_unavailable"
+	KindInternal            ErrorKind = "internal"
+)
+
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func KindOf(err error) ErrorKind {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return KindValidation
+	}
+
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+
+	return KindInternal
+}
+
+func CodeOf(err error) string {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return "validation_failed"
+	}
+
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+
+	return "internal_error"
+}
+
 var (
-	ErrAccountNotFound      = errors.New("account not found")
-	ErrDuplicatedAPIKey     = errors.New("api key already exists")
-	ErrInvoiceNotFound      = errors.New("invoice not found")
-	ErrUnauthorizedAccess   = errors.New("unauthorized not found")
-	ErrInvalidAmount        = errors.New("invalid amount")
-	ErrInvalidStatus        = errors.New("invalid status")
-	ErrMethodNotImplemented = errors.New("method not implemented")
+	ErrAccountNotFound           = NewError(KindNotFound, "account_not_found", "account not found")
+	ErrDuplicatedAPIKey          = NewError(KindConflict, "duplicated_api_key", "api key already exists")
+	ErrInvoiceNotFound           = NewError(KindNotFound, "invoice_not_found", "invoice not found")
+	ErrUnauthorizedAccess        = NewError(KindForbidden, "unauthorized_access", "access to this resource is not allowed")
+	ErrInvalidAmount             = NewError(KindValidation, "invalid_amount", "invalid amount")
+	ErrInvalidStatus             = NewError(KindConflict, "invalid_status", "invalid status")
+	ErrMethodNotImplemented      = NewError(KindInternal, "method_not_implemented", "method not implemented")
+	ErrAuthenticationRequired    = NewError(KindUnauthorized, "authentication_required", "authentication required")
+	ErrInvalidAPIKey             = NewError(KindUnauthorized, "invalid_api_key", "invalid api key")
+	ErrAPIKeyNotFound            = NewError(KindNotFound, "api_key_not_found", "api key not found")
+	ErrAPIKeyRevoked             = NewError(KindConflict, "api_key_revoked", "api key already revoked")
+	ErrInvalidKeyType            = NewError(KindValidation, "invalid_key_type", "invalid api key type")
+	ErrInvalidScope              = NewError(KindValidation, "invalid_scope", "invalid scope")
+	ErrInsufficientScope         = NewError(KindForbidden, "insufficient_scope", "api key does not have the required scope")
+	ErrPaymentTypeNotAllowed     = NewError(KindForbidden, "payment_type_not_allowed", "payment type not allowed for this api key")
+	ErrAccountDeleted            = NewError(KindConflict, "account_deleted", "account is deleted")
+	ErrAccountNotDeleted         = NewError(KindConflict, "account_not_deleted", "account is not deleted")
+	ErrDuplicatedEmail           = NewError(KindConflict, "duplicated_email", "email already exists")
+	ErrAccountFrozen             = NewError(KindConflict, "account_frozen", "account is frozen")
+	ErrAccountNotFrozen          = NewError(KindConflict, "account_not_frozen", "account is not frozen")
+	ErrReasonRequired            = NewError(KindValidation, "reason_required", "reason is required")
+	ErrOperatorNotFound          = NewError(KindNotFound, "operator_not_found", "operator not found")
+	ErrInvalidCredentials        = NewError(KindUnauthorized, "invalid_credentials", "invalid credentials")
+	ErrInvalidRole               = NewError(KindValidation, "invalid_role", "invalid role")
+	ErrPermissionDenied          = NewError(KindForbidden, "permission_denied", "permission denied")
+	ErrAuditChainBroken          = NewError(KindInternal, "audit_chain_broken", "audit log hash chain is broken")
+	ErrCustomerNotFound          = NewError(KindNotFound, "customer_not_found", "customer not found")
+	ErrCustomerDeleted           = NewError(KindConflict, "customer_deleted", "customer is deleted")
+	ErrPaymentMethodNotFound     = NewError(KindNotFound, "payment_method_not_found", "payment method not found")
+	ErrPaymentMethodExpired      = NewError(KindConflict, "payment_method_expired", "payment method is expired")
+	ErrPaymentMethodMismatch     = NewError(KindValidation, "payment_method_mismatch", "payment type does not match the saved payment method")
+	ErrInvalidPaymentMethodType  = NewError(KindValidation, "invalid_payment_method_type", "invalid payment method type")
+	ErrNoDefaultPaymentMethod    = NewError(KindConflict, "no_default_payment_method", "customer has no default payment method")
+	ErrPlanNotFound              = NewError(KindNotFound, "plan_not_found", "plan not found")
+	ErrPlanArchived              = NewError(KindConflict, "plan_archived", "plan is archived")
+	ErrInvalidPlanInterval       = NewError(KindValidation, "invalid_plan_interval", "invalid plan interval")
+	ErrSubscriptionNotFound      = NewError(KindNotFound, "subscription_not_found", "subscription not found")
+	ErrSubscriptionCancelled     = NewError(KindConflict, "subscription_cancelled", "subscription is cancelled")
+	ErrInvalidSubscriptionStatus = NewError(KindConflict, "invalid_subscription_status", "operation not allowed in the current subscription status")
+	ErrInvalidGraceDays          = NewError(KindValidation, "invalid_grace_days", "grace days must be between 0 and 60")
+	ErrInvalidDunningPolicy      = NewError(KindValidation, "invalid_dunning_policy", "invalid dunning policy")
+	ErrProviderUnavailable       = NewError(KindProviderUnavailable, "provider_unavailable", "payment provider is unavailable")
+	ErrPeriodAlreadyInvoiced     = NewError(KindConflict, "period_already_invoiced", "the subscription period already has an open invoice")
+	ErrInvalidWebhookToken       = NewError(KindUnauthorized, "invalid_webhook_token", "invalid webhook token")
+	ErrInsufficientBalance       = NewError(KindConflict, "insufficient_balance", "insufficient balance")
+	ErrPayoutNotFound            = NewError(KindNotFound, "payout_not_found", "payout not found")
+	ErrPayoutDestinationNotFound = NewError(KindNotFound, "payout_destination_not_found", "payout destination not found")
+	ErrInvalidPayoutStatus       = NewError(KindConflict, "invalid_payout_status", "operation not allowed in the current payout status")
+	ErrInvalidSettlementSchedule = NewError(KindValidation, "invalid_settlement_schedule", "invalid settlement schedule")
+	ErrInvalidFeeSchedule        = NewError(KindValidation, "invalid_fee_schedule", "invalid fee schedule")
+	ErrInstallmentsNotAllowed    = NewError(KindValidation, "installments_not_allowed", "installments are only available for card payments")
+	ErrInvalidSplitRecipient     = NewError(KindValidation, "invalid_split_recipient", "split recipient must be another active account")
+	ErrTransferNotFound          = NewError(KindNotFound, "transfer_not_found", "transfer not found")
+	ErrInvalidTransferAccount    = NewError(KindValidation, "invalid_transfer_account", "transfer destination must be another active account")
+	ErrInvalidTransferStatus     = NewError(KindConflict, "invalid_transfer_status", "operation not allowed in the current transfer status")
+	ErrIdempotencyKeyReused      = NewError(KindConflict, "idempotency_key_reused", "idempotency key was already used for a different request")
+	ErrInvalidRefundAmount       = NewError(KindValidation, "invalid_refund_amount", "refund amount must be positive and at most what is left to refund and not under dispute")
+	ErrDisputeNotFound           = NewError(KindNotFound, "dispute_not_found", "dispute not found")
+	ErrDisputeEvidenceNotFound   = NewError(KindNotFound, "dispute_evidence_not_found", "dispute evidence not found")
+	ErrInvalidDisputeAmount      = NewError(KindValidation, "invalid_dispute_amount", "dispute amount must be positive and at most what was paid and not refunded")
+	ErrInvalidDisputeStatus      = NewError(KindConflict, "invalid_dispute_status", "operation not allowed in the current dispute status")
+	ErrDisputeAlreadyOpen        = NewError(KindConflict, "dispute_already_open", "invoice already has an open dispute")
+	ErrDisputeDeadlinePassed     = NewError(KindConflict, "dispute_deadline_passed", "the deadline to respond to the dispute has passed")
+	ErrDisputeEvidenceRequired   = NewError(KindValidation, "dispute_evidence_required", "add evidence before submitting the dispute")
+	ErrInvalidReservePolicy      = NewError(KindValidation, "invalid_reserve_policy", "reserve percent must be up to 100 and days between 1 and 180")
+	ErrBalanceHoldNotFound       = NewError(KindNotFound, "balance_hold_not_found", "balance hold not found")
+	ErrInvalidHoldStatus         = NewError(KindConflict, "invalid_hold_status", "operation not allowed in the current hold status")
+	ErrNegativeBalance           = NewError(KindConflict, "negative_balance", "payouts are blocked while the account balance is negative")
+	ErrReconciliationNotFound    = NewError(KindNotFound, "reconciliation_not_found", "reconciliation not found")
+	ErrCNABFileNotFound          = NewError(KindNotFound, "cnab_file_not_found", "CNAB file not found")
+	ErrCNABNotConfigured         = NewError(KindConflict, "cnab_not_configured", "CNAB collection agreement is not configured")
+	ErrCNABDisabled              = NewError(KindConflict, "cnab_disabled", "boletos are registered through Inter, set BOLETO_PROVIDER=cnab to collect them with remittances")
+	ErrNoInvoicesToRegister      = NewError(KindConflict, "no_invoices_to_register", "there are no pending boleto invoices to register")
+	ErrCNABInvoiceChanged        = NewError(KindConflict, "cnab_invoice_changed", "an invoice changed while the remittance was generated, try again")
 )
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a merchant-facing notification about something that happened to
+// one of their resources, kept so it can be listed or delivered later.
+type Event struct {
+	ID           string
+	AccountID    string
+	Type         string
+	ResourceType string
+	ResourceID   string
+	Data         map[string]any
+	CreatedAt    time.Time
+}
+
+type EventFilter struct {
+	AccountID  string
+	Type       string
+	ResourceID string
+	Limit      int
+}
+
+func NewEvent(accountID, eventType, resourceType, resourceID string, data map[string]any) *Event {
+	return &Event{
+		ID:           uuid.New().String(),
+		AccountID:    accountID,
+		Type:         eventType,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Data:         data,
+		CreatedAt:    time.Now(),
+	}
+}
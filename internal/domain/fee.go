@@ -0,0 +1,45 @@
+package domain
+
+import "math"
+
+const MaxInstallments = 12
+
+// FeeRule is what the platform charges for one payment method: a percentage
+// of the amount paid plus a fixed amount. Card payments split into
+// installments add InstallmentPercent for every installment after the first.
+type FeeRule struct {
+	Percent            float64 `json:"percent"`
+	Fixed              float64 `json:"fixed"`
+	InstallmentPercent float64 `json:"installment_percent,omitempty"`
+}
+
+// FeeSchedule is a pricing plan. The schedule stored on an account only
+// lists the methods it pays differently from the platform default.
+type FeeSchedule map[PaymentMethod]FeeRule
+
+// FeeFor never charges more than the amount itself, so the net amount of a
+// small payment bottoms out at zero.
+func (rule FeeRule) FeeFor(amount float64, installments int) float64 {
+	percent := rule.Percent
+	if installments > 1 {
+		percent += rule.InstallmentPercent * float64(installments-1)
+	}
+
+	fee := roundCents(amount*percent/100 + rule.Fixed)
+
+	return math.Max(0, math.Min(fee, amount))
+}
+
+// Merge returns the schedule with the methods in overrides replaced.
+func (schedule FeeSchedule) Merge(overrides FeeSchedule) FeeSchedule {
+	merged := FeeSchedule{}
+	for method, rule := range schedule {
+		merged[method] = rule
+	}
+
+	for method, rule := range overrides {
+		merged[method] = rule
+	}
+
+	return merged
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "strings"
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func NewFieldError(field, code, message string) *FieldError {
+	return &FieldError{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+
+	return e.Field + ": " + e.Message
+}
+
+func NewValidationError(errors ...*FieldError) *ValidationError {
+	return &ValidationError{
+		Errors: errors,
+	}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Error()
+	}
+
+	return "validation failed: " + strings.Join(messages, "; ")
+}
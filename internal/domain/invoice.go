@@ -13,10 +13,13 @@ const (
 	StatusPending  Status = "pending"
 	StatusApproved Status = "approved"
 	StatusRejected Status = "rejected"
+	StatusExpired  Status = "expired"
+	StatusRefunded Status = "refunded"
 )
 
 type Payer struct {
 	ID        string
+	AccountID string
 	Name      string
 	TaxID     string
 	Email     string
@@ -33,19 +36,59 @@ type Payer struct {
 }
 
 type Invoice struct {
-	ID             string
-	Payer          Payer
-	Reference      string
-	AccountID      string
-	Amount         float64
-	Status         Status
-	Description    string
-	PaymentType    string
-	CardLastDigits string
-	DueDate        time.Time
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	DeletedAt      time.Time
+	ID               string
+	CustomerID       string
+	PaymentMethodID  string
+	SubscriptionID   string
+	PeriodStart      time.Time
+	Payer            Payer
+	Reference        string
+	AccountID        string
+	Amount           float64
+	Status           Status
+	Description      string
+	PaymentType      string
+	CardLastDigits   string
+	DeclineCode      DeclineCode
+	DueDate          time.Time
+	GraceDays        int
+	ProviderChargeID string
+	Fine             *Fine
+	Interest         *Interest
+	Discounts        []Discount
+	Splits           []SplitRule
+	Installments     int
+	FeeRule          FeeRule
+	FeeAmount        float64
+	NetAmount        float64
+	PaidAmount       float64
+	RefundedAmount   float64
+	PaidAt           time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        time.Time
+}
+
+func (payer *Payer) SensitiveFields() map[string]*string {
+	return map[string]*string{
+		"tax_id":   &payer.TaxID,
+		"email":    &payer.Email,
+		"phone":    &payer.Phone,
+		"address":  &payer.Address,
+		"number":   &payer.Number,
+		"district": &payer.District,
+		"city":     &payer.City,
+		"state":    &payer.State,
+		"zip_code": &payer.ZipCode,
+	}
+}
+
+type InvoiceFilter struct {
+	AccountID  string
+	CustomerID string
+	Status     Status
+	Query      string
+	Limit      int
 }
 
 type CreditCard struct {
@@ -61,22 +104,9 @@ func NewInvoice(accountID string, amount float64, description string, paymentTyp
 		return nil, ErrInvalidAmount
 	}
 
-	cardLastDigits := card.Number[len(card.Number)-4:]
-
-	invoicePayer := &Payer{
-		ID:        uuid.New().String(),
-		Name:      payer.Name,
-		TaxID:     payer.TaxID,
-		Email:     payer.Email,
-		Phone:     payer.Phone,
-		Address:   payer.Address,
-		Number:    payer.Number,
-		District:  payer.District,
-		City:      payer.City,
-		State:     payer.State,
-		ZipCode:   payer.ZipCode,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	cardLastDigits := ""
+	if len(card.Number) >= 4 {
+		cardLastDigits = card.Number[len(card.Number)-4:]
 	}
 
 	return &Invoice{
@@ -87,16 +117,37 @@ func NewInvoice(accountID string, amount float64, description string, paymentTyp
 		Description:    description,
 		PaymentType:    paymentType,
 		CardLastDigits: cardLastDigits,
-		Payer:          *invoicePayer,
+		CustomerID:     payer.ID,
+		Payer:          payer,
 		DueDate:        dueDate,
 		Reference:      reference,
+		Installments:   1,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}, nil
 }
 
+func (invoice *Invoice) AttachCustomer(customer *Payer) {
+	invoice.CustomerID = customer.ID
+	invoice.Payer = *customer
+}
+
+func (invoice *Invoice) AttachPaymentMethod(method *SavedPaymentMethod) error {
+	if invoice.PaymentType != "" && PaymentMethod(invoice.PaymentType) != method.Type {
+		return ErrPaymentMethodMismatch
+	}
+
+	invoice.PaymentMethodID = method.ID
+	invoice.PaymentType = string(method.Type)
+	invoice.CardLastDigits = method.Last4
+
+	return nil
+}
+
+// Process authorizes a card charge on the spot. Boleto and Pix invoices stay
+// pending until the provider webhook or a CNAB return reports the payment.
 func (invoice *Invoice) Process() error {
-	if invoice.Amount > 10000 {
+	if PaymentMethod(invoice.PaymentType) != PaymentMethodCard || invoice.Amount > 10000 {
 		return nil
 	}
 
@@ -110,6 +161,16 @@ func (invoice *Invoice) Process() error {
 
 	invoice.UpdateStatus(newStatus)
 
+	if newStatus == StatusApproved {
+		invoice.PaidAmount = invoice.Amount
+		invoice.PaidAt = invoice.UpdatedAt
+		invoice.applyFee(invoice.PaidAmount)
+	}
+
+	if newStatus == StatusRejected {
+		invoice.DeclineCode = cardDeclineCodes[randomSource.Intn(len(cardDeclineCodes))]
+	}
+
 	return nil
 }
 
@@ -123,3 +184,69 @@ func (invoice *Invoice) UpdateStatus(newStatus Status) error {
 
 	return nil
 }
+
+// ExpiresAt is the moment the invoice stops being payable: the end of the
+// last day of its grace period.
+func (invoice *Invoice) ExpiresAt() time.Time {
+	return invoice.DueDate.AddDate(0, 0, invoice.GraceDays+1)
+}
+
+// MarkPaid settles a pending invoice with what the payer actually paid, which
+// differs from Amount once discounts, fines or interest kick in.
+func (invoice *Invoice) MarkPaid(amount float64, paidAt time.Time) error {
+	if err := invoice.UpdateStatus(StatusApproved); err != nil {
+		return err
+	}
+
+	invoice.PaidAmount = amount
+	invoice.PaidAt = paidAt
+	invoice.applyFee(amount)
+
+	return nil
+}
+
+// ApplyFeeRule prices the invoice. The rule stays with the invoice, so a
+// later change to the account's plan doesn't affect charges already issued.
+func (invoice *Invoice) ApplyFeeRule(rule FeeRule) error {
+	if invoice.Installments > 1 && PaymentMethod(invoice.PaymentType) != PaymentMethodCard {
+		return ErrInstallmentsNotAllowed
+	}
+
+	invoice.FeeRule = rule
+	invoice.applyFee(invoice.Amount)
+
+	return nil
+}
+
+// applyFee works out the fee on what is being paid, which for boleto and Pix
+// is only known once the payment arrives.
+func (invoice *Invoice) applyFee(amount float64) {
+	invoice.FeeAmount = invoice.FeeRule.FeeFor(amount, invoice.Installments)
+	invoice.NetAmount = roundCents(amount - invoice.FeeAmount)
+}
+
+func (invoice *Invoice) Expire() error {
+	return invoice.UpdateStatus(StatusExpired)
+}
+
+func (invoice *Invoice) Snapshot() map[string]any {
+	return map[string]any{
+		"account_id":        invoice.AccountID,
+		"customer_id":       invoice.CustomerID,
+		"payment_method_id": invoice.PaymentMethodID,
+		"subscription_id":   invoice.SubscriptionID,
+		"amount":            invoice.Amount,
+		"status":            invoice.Status,
+		"payment_type":      invoice.PaymentType,
+		"decline_code":      invoice.DeclineCode,
+		"reference":         invoice.Reference,
+		"due_date":          invoice.DueDate,
+		"grace_days":        invoice.GraceDays,
+		"installments":      invoice.Installments,
+		"fee_amount":        invoice.FeeAmount,
+		"net_amount":        invoice.NetAmount,
+		"splits":            invoice.Splits,
+		"paid_amount":       invoice.PaidAmount,
+		"refunded_amount":   invoice.RefundedAmount,
+	}
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Role string
+
+const (
+	RoleSupport Role = "support"
+	RoleFinance Role = "finance"
+	RoleAdmin   Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermissionAccountsRead    Permission = "accounts:read"
+	PermissionAccountsCreate  Permission = "accounts:create"
+	PermissionInvoicesRead    Permission = "invoices:read"
+	PermissionBalanceAdjust   Permission = "balance:adjust"
+	PermissionPricingManage   Permission = "pricing:manage"
+	PermissionDisputesManage  Permission = "disputes:manage"
+	PermissionRiskManage      Permission = "risk:manage"
+	PermissionReconcile       Permission = "reconciliation:run"
+	PermissionCNABManage      Permission = "cnab:manage"
+	PermissionAccountsFreeze  Permission = "accounts:freeze"
+	PermissionAccountsRestore Permission = "accounts:restore"
+	PermissionOperatorsManage Permission = "operators:manage"
+	PermissionAuditRead       Permission = "audit:read"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleSupport: {
+		PermissionAccountsRead,
+		PermissionInvoicesRead,
+	},
+	RoleFinance: {
+		PermissionAccountsRead,
+		PermissionInvoicesRead,
+		PermissionBalanceAdjust,
+		PermissionPricingManage,
+		PermissionDisputesManage,
+		PermissionRiskManage,
+		PermissionReconcile,
+		PermissionCNABManage,
+		PermissionAuditRead,
+	},
+	RoleAdmin: {
+		PermissionAccountsRead,
+		PermissionAccountsCreate,
+		PermissionInvoicesRead,
+		PermissionBalanceAdjust,
+		PermissionPricingManage,
+		PermissionDisputesManage,
+		PermissionRiskManage,
+		PermissionReconcile,
+		PermissionCNABManage,
+		PermissionAccountsFreeze,
+		PermissionAccountsRestore,
+		PermissionOperatorsManage,
+		PermissionAuditRead,
+	},
+}
+
+type Operator struct {
+	ID           string
+	Name         string
+	Email        string
+	PasswordHash string
+	TOTPSecret   string
+	Role         Role
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DisabledAt   time.Time
+}
+
+type operatorContextKey struct{}
+
+func IsValidRole(role Role) bool {
+	_, ok := rolePermissions[role]
+
+	return ok
+}
+
+func NewOperator(name, email string, role Role, passwordHash, totpSecret string) (*Operator, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	return &Operator{
+		ID:           uuid.New().String(),
+		Name:         name,
+		Email:        email,
+		PasswordHash: passwordHash,
+		TOTPSecret:   totpSecret,
+		Role:         role,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}, nil
+}
+
+func (operator *Operator) Can(permission Permission) bool {
+	for _, p := range rolePermissions[operator.Role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (operator *Operator) IsDisabled() bool {
+	return !operator.DisabledAt.IsZero()
+}
+
+func WithOperator(ctx context.Context, operator *Operator) context.Context {
+	return context.WithValue(ctx, operatorContextKey{}, operator)
+}
+
+func OperatorFromContext(ctx context.Context) (*Operator, bool) {
+	operator, ok := ctx.Value(operatorContextKey{}).(*Operator)
+
+	return operator, ok && operator != nil
+}
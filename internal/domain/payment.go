@@ -5,6 +5,7 @@ type PaymentMethod string
 const (
 	PaymentMethodBoleto PaymentMethod = "boleto"
 	PaymentMethodCard   PaymentMethod = "card"
+	PaymentMethodPix    PaymentMethod = "pix"
 )
 
 type PaymentRequest struct {
@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PayoutDestinationType string
+
+const (
+	PayoutDestinationBankAccount PayoutDestinationType = "bank_account"
+	PayoutDestinationPixKey      PayoutDestinationType = "pix_key"
+)
+
+type PixKeyType string
+
+const (
+	PixKeyCPF    PixKeyType = "cpf"
+	PixKeyCNPJ   PixKeyType = "cnpj"
+	PixKeyEmail  PixKeyType = "email"
+	PixKeyPhone  PixKeyType = "phone"
+	PixKeyRandom PixKeyType = "random"
+)
+
+type BankAccountType string
+
+const (
+	BankAccountChecking BankAccountType = "checking"
+	BankAccountSavings  BankAccountType = "savings"
+	BankAccountPayment  BankAccountType = "payment"
+)
+
+// PayoutDestination is where an account's money can be sent: either a Pix
+// key, or a bank account identified by the institution's ISPB.
+type PayoutDestination struct {
+	ID            string
+	AccountID     string
+	Type          PayoutDestinationType
+	HolderName    string
+	HolderTaxID   string
+	PixKeyType    PixKeyType
+	PixKey        string
+	BankISPB      string
+	Branch        string
+	AccountNumber string
+	AccountType   BankAccountType
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     time.Time
+}
+
+func NewPixKeyDestination(accountID, holderName, holderTaxID string, keyType PixKeyType, key string) *PayoutDestination {
+	destination := newPayoutDestination(accountID, PayoutDestinationPixKey, holderName, holderTaxID)
+	destination.PixKeyType = keyType
+	destination.PixKey = key
+
+	return destination
+}
+
+func NewBankAccountDestination(accountID, holderName, holderTaxID, bankISPB, branch, accountNumber string, accountType BankAccountType) *PayoutDestination {
+	destination := newPayoutDestination(accountID, PayoutDestinationBankAccount, holderName, holderTaxID)
+	destination.BankISPB = bankISPB
+	destination.Branch = branch
+	destination.AccountNumber = accountNumber
+	destination.AccountType = accountType
+
+	return destination
+}
+
+func newPayoutDestination(accountID string, destinationType PayoutDestinationType, holderName, holderTaxID string) *PayoutDestination {
+	return &PayoutDestination{
+		ID:          uuid.New().String(),
+		AccountID:   accountID,
+		Type:        destinationType,
+		HolderName:  holderName,
+		HolderTaxID: holderTaxID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (destination *PayoutDestination) SensitiveFields() map[string]*string {
+	return map[string]*string{
+		"holder_tax_id":  &destination.HolderTaxID,
+		"pix_key":        &destination.PixKey,
+		"account_number": &destination.AccountNumber,
+	}
+}
+
+func (destination *PayoutDestination) IsDeleted() bool {
+	return !destination.DeletedAt.IsZero()
+}
+
+func (destination *PayoutDestination) Delete() error {
+	if destination.IsDeleted() {
+		return ErrPayoutDestinationNotFound
+	}
+
+	destination.DeletedAt = time.Now()
+	destination.UpdatedAt = destination.DeletedAt
+
+	return nil
+}
+
+func (destination *PayoutDestination) Snapshot() map[string]any {
+	snapshot := map[string]any{
+		"type":         destination.Type,
+		"holder_name":  destination.HolderName,
+		"pix_key_type": destination.PixKeyType,
+		"bank_ispb":    destination.BankISPB,
+		"branch":       destination.Branch,
+		"deleted_at":   nil,
+	}
+
+	if destination.IsDeleted() {
+		snapshot["deleted_at"] = destination.DeletedAt
+	}
+
+	return snapshot
+}
+
+type PayoutStatus string
+
+const (
+	PayoutStatusPending    PayoutStatus = "pending"
+	PayoutStatusProcessing PayoutStatus = "processing"
+	PayoutStatusPaid       PayoutStatus = "paid"
+	PayoutStatusFailed     PayoutStatus = "failed"
+)
+
+// Payout moves money out of an account's balance. The amount is reserved when
+// the payout is created; it goes pending -> processing once the provider
+// accepts the transfer, then ends paid or failed. A failed payout has its
+// amount returned to the available balance.
+type Payout struct {
+	ID                 string
+	AccountID          string
+	DestinationID      string
+	Amount             float64
+	Description        string
+	Status             PayoutStatus
+	ProviderTransferID string
+	FailureReason      string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	PaidAt             time.Time
+	FailedAt           time.Time
+}
+
+type PayoutFilter struct {
+	AccountID string
+	Status    PayoutStatus
+	Limit     int
+}
+
+// PayoutResult is what the provider reports about a transfer.
+type PayoutResult struct {
+	ProviderTransferID string
+	Status             PayoutStatus
+	FailureReason      string
+}
+
+func NewPayout(accountID, destinationID string, amount float64, description string) (*Payout, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	return &Payout{
+		ID:            uuid.New().String(),
+		AccountID:     accountID,
+		DestinationID: destinationID,
+		Amount:        amount,
+		Description:   description,
+		Status:        PayoutStatusPending,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}, nil
+}
+
+func (payout *Payout) IsFinal() bool {
+	return payout.Status == PayoutStatusPaid || payout.Status == PayoutStatusFailed
+}
+
+// Apply moves the payout to the status reported by the provider. Reports
+// that don't advance the lifecycle are ignored.
+func (payout *Payout) Apply(result PayoutResult) error {
+	if payout.IsFinal() {
+		return ErrInvalidPayoutStatus
+	}
+
+	if result.ProviderTransferID != "" {
+		payout.ProviderTransferID = result.ProviderTransferID
+	}
+
+	switch result.Status {
+	case PayoutStatusProcessing:
+		payout.Status = PayoutStatusProcessing
+	case PayoutStatusPaid:
+		payout.Status = PayoutStatusPaid
+		payout.PaidAt = time.Now()
+	case PayoutStatusFailed:
+		payout.Fail(result.FailureReason)
+		return nil
+	default:
+		return nil
+	}
+
+	payout.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (payout *Payout) Fail(reason string) {
+	payout.Status = PayoutStatusFailed
+	payout.FailureReason = reason
+	payout.FailedAt = time.Now()
+	payout.UpdatedAt = payout.FailedAt
+}
+
+func (payout *Payout) Snapshot() map[string]any {
+	return map[string]any{
+		"destination_id":       payout.DestinationID,
+		"amount":               payout.Amount,
+		"status":               payout.Status,
+		"provider_transfer_id": payout.ProviderTransferID,
+		"failure_reason":       payout.FailureReason,
+	}
+}
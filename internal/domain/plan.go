@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PlanInterval string
+
+const (
+	PlanIntervalDay   PlanInterval = "day"
+	PlanIntervalWeek  PlanInterval = "week"
+	PlanIntervalMonth PlanInterval = "month"
+	PlanIntervalYear  PlanInterval = "year"
+)
+
+type Plan struct {
+	ID            string
+	AccountID     string
+	Name          string
+	Amount        float64
+	Interval      PlanInterval
+	IntervalCount int
+	TrialDays     int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	ArchivedAt    time.Time
+}
+
+func NewPlan(accountID, name string, amount float64, interval PlanInterval, intervalCount, trialDays int) (*Plan, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	switch interval {
+	case PlanIntervalDay, PlanIntervalWeek, PlanIntervalMonth, PlanIntervalYear:
+	default:
+		return nil, ErrInvalidPlanInterval
+	}
+
+	if intervalCount <= 0 {
+		intervalCount = 1
+	}
+
+	return &Plan{
+		ID:            uuid.New().String(),
+		AccountID:     accountID,
+		Name:          name,
+		Amount:        amount,
+		Interval:      interval,
+		IntervalCount: intervalCount,
+		TrialDays:     trialDays,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}, nil
+}
+
+// PeriodEnd returns the end of the billing period that starts at start.
+func (plan *Plan) PeriodEnd(start time.Time) time.Time {
+	switch plan.Interval {
+	case PlanIntervalDay:
+		return start.AddDate(0, 0, plan.IntervalCount)
+	case PlanIntervalWeek:
+		return start.AddDate(0, 0, 7*plan.IntervalCount)
+	case PlanIntervalYear:
+		return start.AddDate(plan.IntervalCount, 0, 0)
+	default:
+		return start.AddDate(0, plan.IntervalCount, 0)
+	}
+}
+
+func (plan *Plan) IsArchived() bool {
+	return !plan.ArchivedAt.IsZero()
+}
+
+func (plan *Plan) Archive() error {
+	if plan.IsArchived() {
+		return ErrPlanArchived
+	}
+
+	plan.ArchivedAt = time.Now()
+	plan.UpdatedAt = plan.ArchivedAt
+
+	return nil
+}
+
+func (plan *Plan) Snapshot() map[string]any {
+	snapshot := map[string]any{
+		"name":           plan.Name,
+		"amount":         plan.Amount,
+		"interval":       plan.Interval,
+		"interval_count": plan.IntervalCount,
+		"trial_days":     plan.TrialDays,
+		"archived_at":    nil,
+	}
+
+	if plan.IsArchived() {
+		snapshot["archived_at"] = plan.ArchivedAt
+	}
+
+	return snapshot
+}
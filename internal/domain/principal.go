@@ -0,0 +1,48 @@
+package domain
+
+import "context"
+
+type principalContextKey struct{}
+
+type Principal struct {
+	AccountID string
+	KeyID     string
+	KeyType   KeyType
+	Scopes    []Scope
+	// AccountFrozen lets reads through but blocks every write of the account.
+	AccountFrozen bool
+}
+
+func NewPrincipal(apiKey *APIKey) *Principal {
+	return &Principal{
+		AccountID:     apiKey.AccountID,
+		KeyID:         apiKey.ID,
+		KeyType:       apiKey.Type,
+		Scopes:        apiKey.Scopes,
+		AccountFrozen: apiKey.AccountFrozen,
+	}
+}
+
+func (principal *Principal) HasScope(scope Scope) bool {
+	for _, s := range principal.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (principal *Principal) CanCharge(method PaymentMethod) bool {
+	return principal.KeyType.CanCharge(method)
+}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+
+	return principal, ok && principal != nil
+}
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxReconciliationDays bounds the period of a single reconciliation, since
+// the whole bank statement for it is loaded at once.
+const MaxReconciliationDays = 31
+
+type ReconciliationItemStatus string
+
+const (
+	ReconciliationMatched        ReconciliationItemStatus = "matched"
+	ReconciliationUnmatched      ReconciliationItemStatus = "unmatched"
+	ReconciliationAmountMismatch ReconciliationItemStatus = "amount_mismatch"
+	ReconciliationNotSettled     ReconciliationItemStatus = "not_settled"
+	ReconciliationMissingPayment ReconciliationItemStatus = "missing_payment"
+)
+
+// BankStatementEntry is a line of the bank statement. Credits from boleto
+// and Pix charges carry the seuNumero we sent when creating them (the
+// invoice reference) and the bank's own identifiers for the charge.
+type BankStatementEntry struct {
+	ID          string
+	Date        time.Time
+	Amount      float64
+	Type        string
+	Description string
+	SeuNumero   string
+	NossoNumero string
+	ChargeID    string
+}
+
+func (entry BankStatementEntry) IsCredit() bool {
+	return entry.Amount > 0
+}
+
+// ReconciliationInvoice is what reconciliation needs to know about an
+// invoice charged through the bank.
+type ReconciliationInvoice struct {
+	ID               string
+	AccountID        string
+	Reference        string
+	ProviderChargeID string
+	Status           Status
+	PaidAmount       float64
+	PaidAt           time.Time
+}
+
+func (invoice *ReconciliationInvoice) IsPaid() bool {
+	return !invoice.PaidAt.IsZero()
+}
+
+type ReconciliationItem struct {
+	Status      ReconciliationItemStatus `json:"status"`
+	EntryID     string                   `json:"entry_id,omitempty"`
+	EntryDate   time.Time                `json:"entry_date,omitempty"`
+	SeuNumero   string                   `json:"seu_numero,omitempty"`
+	NossoNumero string                   `json:"nosso_numero,omitempty"`
+	InvoiceID   string                   `json:"invoice_id,omitempty"`
+	AccountID   string                   `json:"account_id,omitempty"`
+	Expected    float64                  `json:"expected"`
+	Received    float64                  `json:"received"`
+	Note        string                   `json:"note,omitempty"`
+}
+
+type ReconciliationSummary struct {
+	Matched    int `json:"matched"`
+	Unmatched  int `json:"unmatched"`
+	Mismatched int `json:"mismatched"`
+	NotSettled int `json:"not_settled"`
+	Missing    int `json:"missing"`
+}
+
+// Reconciliation compares the bank's credits for a period with the invoices
+// the gateway settled in it. From and To are inclusive dates.
+type Reconciliation struct {
+	ID        string
+	From      time.Time
+	To        time.Time
+	Summary   ReconciliationSummary
+	Items     []ReconciliationItem
+	CreatedAt time.Time
+}
+
+type ReconciliationFilter struct {
+	From  time.Time
+	To    time.Time
+	Limit int
+}
+
+// Reconcile matches each credit of the statement to an invoice, first by the
+// bank's charge identifiers and then by seuNumero. A seuNumero is only the
+// reference the merchant chose, so it is trusted alone only when exactly one
+// invoice carries it. The amount is checked against what the gateway
+// recorded as paid. Invoices paid in the period that no credit accounts for
+// are reported as missing. invoices must hold the ones paid in the period
+// plus any the credits refer to.
+func Reconcile(from, to time.Time, entries []BankStatementEntry, invoices []*ReconciliationInvoice) *Reconciliation {
+	reconciliation := &Reconciliation{
+		ID:        uuid.New().String(),
+		From:      dateOf(from),
+		To:        dateOf(to),
+		CreatedAt: time.Now(),
+	}
+
+	byReference := map[string][]*ReconciliationInvoice{}
+	byChargeID := map[string]*ReconciliationInvoice{}
+	for _, invoice := range invoices {
+		if invoice.Reference != "" {
+			byReference[invoice.Reference] = append(byReference[invoice.Reference], invoice)
+		}
+
+		if invoice.ProviderChargeID != "" {
+			byChargeID[invoice.ProviderChargeID] = invoice
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsCredit() {
+			continue
+		}
+
+		item := ReconciliationItem{
+			EntryID:     entry.ID,
+			EntryDate:   entry.Date,
+			SeuNumero:   entry.SeuNumero,
+			NossoNumero: entry.NossoNumero,
+			Received:    entry.Amount,
+		}
+
+		invoice := byChargeID[entry.ChargeID]
+		if invoice == nil {
+			invoice = byChargeID[entry.NossoNumero]
+		}
+
+		if invoice == nil && len(byReference[entry.SeuNumero]) == 1 {
+			invoice = byReference[entry.SeuNumero][0]
+		}
+
+		switch {
+		case invoice == nil:
+			item.Status = ReconciliationUnmatched
+			item.Note = entry.Description
+		case seen[invoice.ID]:
+			item.Status = ReconciliationUnmatched
+			item.InvoiceID = invoice.ID
+			item.AccountID = invoice.AccountID
+			item.Note = "invoice already matched by another credit"
+		default:
+			seen[invoice.ID] = true
+
+			item.InvoiceID = invoice.ID
+			item.AccountID = invoice.AccountID
+			item.Expected = invoice.PaidAmount
+
+			if !invoice.IsPaid() {
+				item.Status = ReconciliationNotSettled
+				item.Note = "invoice is " + string(invoice.Status) + " in the gateway"
+			} else if roundCents(invoice.PaidAmount) != roundCents(entry.Amount) {
+				item.Status = ReconciliationAmountMismatch
+			} else {
+				item.Status = ReconciliationMatched
+			}
+		}
+
+		reconciliation.add(item)
+	}
+
+	end := reconciliation.To.AddDate(0, 0, 1)
+	for _, invoice := range invoices {
+		if seen[invoice.ID] || !invoice.IsPaid() {
+			continue
+		}
+
+		paidOn := dateOf(invoice.PaidAt)
+		if paidOn.Before(reconciliation.From) || !paidOn.Before(end) {
+			continue
+		}
+
+		reconciliation.add(ReconciliationItem{
+			Status:    ReconciliationMissingPayment,
+			SeuNumero: invoice.Reference,
+			InvoiceID: invoice.ID,
+			AccountID: invoice.AccountID,
+			Expected:  invoice.PaidAmount,
+		})
+	}
+
+	return reconciliation
+}
+
+func (reconciliation *Reconciliation) add(item ReconciliationItem) {
+	reconciliation.Items = append(reconciliation.Items, item)
+
+	switch item.Status {
+	case ReconciliationMatched:
+		reconciliation.Summary.Matched++
+	case ReconciliationUnmatched:
+		reconciliation.Summary.Unmatched++
+	case ReconciliationAmountMismatch:
+		reconciliation.Summary.Mismatched++
+	case ReconciliationNotSettled:
+		reconciliation.Summary.NotSettled++
+	case ReconciliationMissingPayment:
+		reconciliation.Summary.Missing++
+	}
+}
+
+func (reconciliation *Reconciliation) HasIssues() bool {
+	return len(reconciliation.Items) > reconciliation.Summary.Matched
+}
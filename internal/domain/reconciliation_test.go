@@ -0,0 +1,100 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	bank_statement_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/bank_statement"
+)
+
+func date(day string) time.Time {
+	parsed, err := time.ParseInLocation(time.DateOnly, day, time.Local)
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed
+}
+
+func statement(t *testing.T, from, to time.Time) []domain.BankStatementEntry {
+	t.Helper()
+
+	entries, err := bank_statement_repository.NewFileStatementProvider("testdata/extrato.json").Statement(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("reading statement fixture: %v", err)
+	}
+
+	return entries
+}
+
+func TestReconcile(t *testing.T) {
+	from, to := date("2030-01-15"), date("2030-01-16")
+
+	invoices := []*domain.ReconciliationInvoice{
+		{ID: "inv-1", AccountID: "acc-1", Reference: "PEDIDO-1", ProviderChargeID: "00010000001", Status: domain.StatusApproved, PaidAmount: 150, PaidAt: date("2030-01-15")},
+		// PEDIDO-2 was used by two merchants, so only the charge identifier
+		// can tell their credits apart.
+		{ID: "inv-2", AccountID: "acc-1", Reference: "PEDIDO-2", ProviderChargeID: "c0de-0002", Status: domain.StatusApproved, PaidAmount: 100, PaidAt: date("2030-01-15")},
+		{ID: "inv-3", AccountID: "acc-2", Reference: "PEDIDO-2", ProviderChargeID: "c0de-0003", Status: domain.StatusApproved, PaidAmount: 50, PaidAt: date("2030-01-16")},
+		{ID: "inv-4", AccountID: "acc-2", Reference: "PEDIDO-4", Status: domain.StatusPending},
+		{ID: "inv-5", AccountID: "acc-1", Reference: "PEDIDO-5", ProviderChargeID: "c0de-0005", Status: domain.StatusApproved, PaidAmount: 30, PaidAt: date("2030-01-20")},
+	}
+
+	reconciliation := domain.Reconcile(from, to, statement(t, from, to), invoices)
+
+	want := []struct {
+		status    domain.ReconciliationItemStatus
+		entryID   string
+		invoiceID string
+	}{
+		{domain.ReconciliationMatched, "2001", "inv-1"},
+		{domain.ReconciliationAmountMismatch, "2002", "inv-2"},
+		{domain.ReconciliationUnmatched, "2003", ""},
+		{domain.ReconciliationNotSettled, "2004", "inv-4"},
+		{domain.ReconciliationMissingPayment, "", "inv-3"},
+	}
+
+	if len(reconciliation.Items) != len(want) {
+		t.Fatalf("got %d items, want %d: %+v", len(reconciliation.Items), len(want), reconciliation.Items)
+	}
+
+	for i, item := range reconciliation.Items {
+		if item.Status != want[i].status || item.EntryID != want[i].entryID || item.InvoiceID != want[i].invoiceID {
+			t.Errorf("item %d = {%s %q %q}, want {%s %q %q}", i, item.Status, item.EntryID, item.InvoiceID, want[i].status, want[i].entryID, want[i].invoiceID)
+		}
+	}
+
+	summary := domain.ReconciliationSummary{Matched: 1, Unmatched: 1, Mismatched: 1, NotSettled: 1, Missing: 1}
+	if reconciliation.Summary != summary {
+		t.Errorf("summary = %+v, want %+v", reconciliation.Summary, summary)
+	}
+
+	if !reconciliation.HasIssues() {
+		t.Error("HasIssues() = false, want true")
+	}
+}
+
+func TestReconcileMatchesCreditOnce(t *testing.T) {
+	from, to := date("2030-01-15"), date("2030-01-15")
+
+	invoices := []*domain.ReconciliationInvoice{
+		{ID: "inv-1", AccountID: "acc-1", Reference: "PEDIDO-1", ProviderChargeID: "00010000001", Status: domain.StatusApproved, PaidAmount: 150, PaidAt: date("2030-01-15")},
+	}
+
+	entries := statement(t, from, to)
+	entries = append(entries, entries[0])
+	entries[len(entries)-1].ID = "2001-dup"
+
+	reconciliation := domain.Reconcile(from, to, entries, invoices)
+
+	if reconciliation.Summary.Matched != 1 {
+		t.Errorf("matched = %d, want 1", reconciliation.Summary.Matched)
+	}
+
+	last := reconciliation.Items[len(reconciliation.Items)-1]
+	if last.EntryID != "2001-dup" || last.Status != domain.ReconciliationUnmatched || last.InvoiceID != "inv-1" {
+		t.Errorf("duplicate credit = %+v, want unmatched against inv-1", last)
+	}
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Refund gives part or all of a paid invoice back to the payer. Reversals
+// say how much each account that shared in the payment gives back.
+type Refund struct {
+	ID        string
+	InvoiceID string
+	AccountID string
+	Amount    float64
+	Reason    string
+	Reversals []SplitReversal
+	CreatedAt time.Time
+}
+
+type SplitReversal struct {
+	AccountID string  `json:"account_id"`
+	Amount    float64 `json:"amount"`
+}
+
+// Refund takes amount back out of the invoice. Every account gives back the
+// same proportion of its gross share, with rounding left to the invoice's own
+// account; the platform keeps its fee.
+func (invoice *Invoice) Refund(amount float64, reason string) (*Refund, error) {
+	if invoice.Status != StatusApproved {
+		return nil, ErrInvalidStatus
+	}
+
+	if amount <= 0 || amount > roundCents(invoice.PaidAmount-invoice.RefundedAmount) {
+		return nil, ErrInvalidRefundAmount
+	}
+
+	shares := invoice.Shares(invoice.PaidAmount, invoice.FeeAmount)
+	reversals := make([]SplitReversal, len(shares))
+	left := amount
+
+	for i := len(shares) - 1; i >= 0; i-- {
+		portion := left
+		if i > 0 {
+			portion = roundCents(amount * shares[i].Gross / invoice.PaidAmount)
+		}
+
+		reversals[i] = SplitReversal{AccountID: shares[i].AccountID, Amount: portion}
+		left = roundCents(left - portion)
+	}
+
+	invoice.RefundedAmount = roundCents(invoice.RefundedAmount + amount)
+	if invoice.RefundedAmount >= invoice.PaidAmount {
+		invoice.Status = StatusRefunded
+	}
+
+	invoice.UpdatedAt = time.Now()
+
+	return &Refund{
+		ID:        uuid.New().String(),
+		InvoiceID: invoice.ID,
+		AccountID: invoice.AccountID,
+		Amount:    amount,
+		Reason:    reason,
+		Reversals: reversals,
+		CreatedAt: invoice.UpdatedAt,
+	}, nil
+}
+
+func (refund *Refund) Snapshot() map[string]any {
+	return map[string]any{
+		"invoice_id": refund.InvoiceID,
+		"amount":     refund.Amount,
+		"reason":     refund.Reason,
+		"reversals":  refund.Reversals,
+	}
+}
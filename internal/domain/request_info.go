@@ -0,0 +1,20 @@
+package domain
+
+import "context"
+
+type requestInfoContextKey struct{}
+
+type RequestInfo struct {
+	IP        string
+	RequestID string
+}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoContextKey{}, info)
+}
+
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoContextKey{}).(RequestInfo)
+
+	return info
+}
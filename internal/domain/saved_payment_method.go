@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentMethodStatus string
+
+const (
+	PaymentMethodStatusActive  PaymentMethodStatus = "active"
+	PaymentMethodStatusExpired PaymentMethodStatus = "expired"
+)
+
+// SavedPaymentMethod is a customer's reusable way to pay. Cards only keep the
+// vault token plus display data; the card number itself lives in the vault.
+// Pix and boleto entries carry no data and just record the preference.
+type SavedPaymentMethod struct {
+	ID             string
+	AccountID      string
+	CustomerID     string
+	Type           PaymentMethod
+	VaultToken     string
+	Brand          string
+	Last4          string
+	ExpiryMonth    int
+	ExpiryYear     int
+	CardholderName string
+	IsDefault      bool
+	Status         PaymentMethodStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      time.Time
+}
+
+func NewSavedPaymentMethod(accountID, customerID string, methodType PaymentMethod) (*SavedPaymentMethod, error) {
+	switch methodType {
+	case PaymentMethodBoleto, PaymentMethodPix, PaymentMethodCard:
+	default:
+		return nil, ErrInvalidPaymentMethodType
+	}
+
+	return &SavedPaymentMethod{
+		ID:         uuid.New().String(),
+		AccountID:  accountID,
+		CustomerID: customerID,
+		Type:       methodType,
+		Status:     PaymentMethodStatusActive,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}, nil
+}
+
+func NewSavedCard(accountID, customerID, vaultToken string, card CreditCard) (*SavedPaymentMethod, error) {
+	method, err := NewSavedPaymentMethod(accountID, customerID, PaymentMethodCard)
+	if err != nil {
+		return nil, err
+	}
+
+	method.VaultToken = vaultToken
+	method.Brand = CardBrand(card.Number)
+	method.Last4 = card.Number[len(card.Number)-4:]
+	method.ExpiryMonth = card.ExpiryMonth
+	method.ExpiryYear = card.ExpiryYear
+	method.CardholderName = card.CardholderName
+
+	if method.IsExpiredAt(time.Now()) {
+		return nil, ErrPaymentMethodExpired
+	}
+
+	return method, nil
+}
+
+// IsExpiredAt treats a card as valid through the last day of its expiry month.
+func (method *SavedPaymentMethod) IsExpiredAt(t time.Time) bool {
+	if method.Type != PaymentMethodCard {
+		return false
+	}
+
+	return t.Year() > method.ExpiryYear || (t.Year() == method.ExpiryYear && int(t.Month()) > method.ExpiryMonth)
+}
+
+func (method *SavedPaymentMethod) MarkDefault() {
+	method.IsDefault = true
+	method.UpdatedAt = time.Now()
+}
+
+func (method *SavedPaymentMethod) Expire() {
+	method.Status = PaymentMethodStatusExpired
+	method.IsDefault = false
+	method.UpdatedAt = time.Now()
+}
+
+func (method *SavedPaymentMethod) IsDeleted() bool {
+	return !method.DeletedAt.IsZero()
+}
+
+func (method *SavedPaymentMethod) Delete() error {
+	if method.IsDeleted() {
+		return ErrPaymentMethodNotFound
+	}
+
+	method.IsDefault = false
+	method.DeletedAt = time.Now()
+	method.UpdatedAt = method.DeletedAt
+
+	return nil
+}
+
+// CheckUsable flags a card that has expired since it was last touched, so the
+// caller can persist the new status before refusing the charge.
+func (method *SavedPaymentMethod) CheckUsable(now time.Time) error {
+	if method.IsDeleted() {
+		return ErrPaymentMethodNotFound
+	}
+
+	if method.Status == PaymentMethodStatusActive && method.IsExpiredAt(now) {
+		method.Expire()
+	}
+
+	if method.Status == PaymentMethodStatusExpired {
+		return ErrPaymentMethodExpired
+	}
+
+	return nil
+}
+
+func (method *SavedPaymentMethod) Snapshot() map[string]any {
+	snapshot := map[string]any{
+		"customer_id": method.CustomerID,
+		"type":        method.Type,
+		"brand":       method.Brand,
+		"last4":       method.Last4,
+		"is_default":  method.IsDefault,
+		"status":      method.Status,
+		"deleted_at":  nil,
+	}
+
+	if method.IsDeleted() {
+		snapshot["deleted_at"] = method.DeletedAt
+	}
+
+	return snapshot
+}
+
+var cardBrandPrefixes = []struct {
+	brand    string
+	from, to int
+	digits   int
+}{
+	{"elo", 401178, 401179, 6},
+	{"elo", 431274, 431274, 6},
+	{"elo", 438935, 438935, 6},
+	{"elo", 451416, 451416, 6},
+	{"elo", 457393, 457393, 6},
+	{"elo", 457631, 457632, 6},
+	{"elo", 504175, 504175, 6},
+	{"elo", 506699, 506778, 6},
+	{"elo", 509000, 509999, 6},
+	{"elo", 627780, 627780, 6},
+	{"elo", 636297, 636297, 6},
+	{"elo", 636368, 636368, 6},
+	{"elo", 650031, 650051, 6},
+	{"elo", 650405, 650439, 6},
+	{"elo", 650485, 650538, 6},
+	{"elo", 650541, 650598, 6},
+	{"elo", 650700, 650727, 6},
+	{"elo", 650901, 650978, 6},
+	{"elo", 651652, 651679, 6},
+	{"elo", 655000, 655058, 6},
+	{"hipercard", 606282, 606282, 6},
+	{"hipercard", 3841, 3841, 4},
+	{"amex", 34, 34, 2},
+	{"amex", 37, 37, 2},
+	{"diners", 300, 305, 3},
+	{"diners", 36, 36, 2},
+	{"diners", 38, 38, 2},
+	{"discover", 6011, 6011, 4},
+	{"discover", 65, 65, 2},
+	{"mastercard", 2221, 2720, 4},
+	{"mastercard", 51, 55, 2},
+	{"visa", 4, 4, 1},
+}
+
+// CardBrand resolves the brand from the BIN. Elo and Hipercard ranges overlap
+// with Visa and Discover, so they are checked first.
+func CardBrand(number string) string {
+	if number == "" {
+		return ""
+	}
+
+	for _, prefix := range cardBrandPrefixes {
+		if len(number) < prefix.digits {
+			continue
+		}
+
+		bin, err := strconv.Atoi(number[:prefix.digits])
+		if err != nil {
+			return "unknown"
+		}
+
+		if bin >= prefix.from && bin <= prefix.to {
+			return prefix.brand
+		}
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SettlementStatus string
+
+const (
+	SettlementStatusScheduled SettlementStatus = "scheduled"
+	SettlementStatusReleased  SettlementStatus = "released"
+)
+
+// Settlement tracks an account's net share of a paid invoice while it sits in
+// the pending balance, until it becomes available on AvailableOn. Fee is the
+// part of the invoice fee the share pays; it is credited to FeeAccountID as
+// soon as the settlement is scheduled. Hold is the rolling reserve withheld
+// from it, if the account has one.
+type Settlement struct {
+	ID            string
+	AccountID     string
+	InvoiceID     string
+	PaymentMethod PaymentMethod
+	Amount        float64
+	Fee           float64
+	FeeAccountID  string
+	Status        SettlementStatus
+	AvailableOn   time.Time
+	CreatedAt     time.Time
+	ReleasedAt    time.Time
+	Hold          *BalanceHold
+}
+
+type UpcomingSettlement struct {
+	Date   time.Time
+	Amount float64
+}
+
+// SettlementSchedule is how many days after payment each method's funds
+// become available (D+n).
+type SettlementSchedule map[PaymentMethod]int
+
+func (schedule SettlementSchedule) AvailableOn(method PaymentMethod, paidAt time.Time) time.Time {
+	return dateOf(paidAt).AddDate(0, 0, schedule[method])
+}
+
+// NewSettlements creates a settlement for each account sharing in a paid
+// invoice. The invoice's own account comes first and always gets one, even
+// when the splits take all of the net amount.
+func NewSettlements(invoice *Invoice, schedule SettlementSchedule, feeAccountID string) []*Settlement {
+	paidAt := invoice.PaidAt
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+
+	method := PaymentMethod(invoice.PaymentType)
+
+	var settlements []*Settlement
+	for i, share := range invoice.Shares(invoice.PaidAmount, invoice.FeeAmount) {
+		if i > 0 && share.Net <= 0 && share.Fee <= 0 {
+			continue
+		}
+
+		settlement := &Settlement{
+			ID:            uuid.New().String(),
+			AccountID:     share.AccountID,
+			InvoiceID:     invoice.ID,
+			PaymentMethod: method,
+			Amount:        share.Net,
+			Fee:           share.Fee,
+			FeeAccountID:  feeAccountID,
+			Status:        SettlementStatusScheduled,
+			AvailableOn:   schedule.AvailableOn(method, paidAt),
+			CreatedAt:     time.Now(),
+		}
+
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements
+}
+
+// Withhold takes the rolling reserve out of the settlement before it is
+// scheduled; the held part is released policy.Days after AvailableOn.
+func (settlement *Settlement) Withhold(policy ReservePolicy) {
+	if !policy.IsEnabled() || settlement.Amount <= 0 {
+		return
+	}
+
+	amount := roundCents(settlement.Amount * policy.Percent / 100)
+	if amount <= 0 {
+		return
+	}
+
+	settlement.Amount = roundCents(settlement.Amount - amount)
+	settlement.Hold = &BalanceHold{
+		ID:        uuid.New().String(),
+		AccountID: settlement.AccountID,
+		Kind:      BalanceHoldRollingReserve,
+		Amount:    amount,
+		Reason:    "rolling reserve",
+		InvoiceID: settlement.InvoiceID,
+		Status:    BalanceHoldStatusActive,
+		ReleaseAt: settlement.AvailableOn.AddDate(0, 0, policy.Days),
+		CreatedAt: time.Now(),
+	}
+}
+
+func (settlement *Settlement) IsDueAt(t time.Time) bool {
+	return settlement.Status == SettlementStatusScheduled && !settlement.AvailableOn.After(t)
+}
+
+func (settlement *Settlement) Release() error {
+	if settlement.Status != SettlementStatusScheduled {
+		return ErrInvalidStatus
+	}
+
+	settlement.Status = SettlementStatusReleased
+	settlement.ReleasedAt = time.Now()
+
+	return nil
+}
+
+func (settlement *Settlement) Snapshot() map[string]any {
+	return map[string]any{
+		"invoice_id":   settlement.InvoiceID,
+		"amount":       settlement.Amount,
+		"fee":          settlement.Fee,
+		"status":       settlement.Status,
+		"available_on": settlement.AvailableOn,
+	}
+}
@@ -0,0 +1,101 @@
+package domain
+
+const MaxSplits = 10
+
+// SplitRule sends part of an invoice's proceeds to another account. PaysFee
+// and ChargebackLiable say who bears the platform fee and chargebacks; when
+// no rule claims them, the invoice's own account does.
+type SplitRule struct {
+	RecipientAccountID string         `json:"recipient_account_id"`
+	Type               AdjustmentType `json:"type"`
+	Value              float64        `json:"value"`
+	PaysFee            bool           `json:"pays_fee"`
+	ChargebackLiable   bool           `json:"chargeback_liable"`
+}
+
+// SplitShare is what one account gets out of a payment.
+type SplitShare struct {
+	AccountID        string
+	Gross            float64
+	Fee              float64
+	Net              float64
+	ChargebackLiable bool
+}
+
+// Shares divides a payment of amount, of which fee goes to the platform,
+// between the invoice's account and its split recipients. The invoice's
+// account always comes first and keeps whatever the splits don't take.
+func (invoice *Invoice) Shares(amount, fee float64) []SplitShare {
+	shares := []SplitShare{{AccountID: invoice.AccountID}}
+	remaining := amount
+
+	for _, rule := range invoice.Splits {
+		gross := rule.Value
+		if rule.Type == AdjustmentPercent {
+			gross = roundCents(amount * rule.Value / 100)
+		}
+
+		gross = min(gross, remaining)
+		remaining = roundCents(remaining - gross)
+
+		shares = append(shares, SplitShare{AccountID: rule.RecipientAccountID, Gross: gross, ChargebackLiable: rule.ChargebackLiable})
+	}
+
+	shares[0].Gross = remaining
+
+	payers := []int{}
+	liable := false
+	for i, rule := range invoice.Splits {
+		if rule.PaysFee {
+			payers = append(payers, i+1)
+		}
+
+		liable = liable || rule.ChargebackLiable
+	}
+
+	shares[0].ChargebackLiable = !liable
+
+	if len(payers) == 0 {
+		payers = []int{0}
+	}
+
+	splitAmong(shares, payers, fee)
+
+	for i := range shares {
+		shares[i].Net = roundCents(shares[i].Gross - shares[i].Fee)
+	}
+
+	return shares
+}
+
+// splitAmong charges fee to the payers in proportion to their gross share.
+// Whatever a payer can't cover falls to the other accounts, the invoice's
+// own account first.
+func splitAmong(shares []SplitShare, payers []int, fee float64) {
+	var base float64
+	for _, i := range payers {
+		base += shares[i].Gross
+	}
+
+	left := fee
+	for n, i := range payers {
+		portion := left
+		if n < len(payers)-1 && base > 0 {
+			portion = roundCents(fee * shares[i].Gross / base)
+		}
+
+		portion = min(portion, shares[i].Gross, left)
+		shares[i].Fee = portion
+		left = roundCents(left - portion)
+	}
+
+	for i := range shares {
+		if left <= 0 {
+			return
+		}
+
+		portion := min(shares[i].Gross-shares[i].Fee, left)
+		shares[i].Fee = roundCents(shares[i].Fee + portion)
+		left = roundCents(left - portion)
+	}
+}
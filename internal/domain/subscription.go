@@ -0,0 +1,235 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusTrialing  SubscriptionStatus = "trialing"
+	SubscriptionStatusActive    SubscriptionStatus = "active"
+	SubscriptionStatusPastDue   SubscriptionStatus = "past_due"
+	SubscriptionStatusPaused    SubscriptionStatus = "paused"
+	SubscriptionStatusUnpaid    SubscriptionStatus = "unpaid"
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
+)
+
+type Subscription struct {
+	ID                 string
+	AccountID          string
+	CustomerID         string
+	PaymentMethodID    string
+	PlanID             string
+	Status             SubscriptionStatus
+	CurrentPeriodStart time.Time
+	CurrentPeriodEnd   time.Time
+	NextBillingAt      time.Time
+	TrialEnd           time.Time
+	CancelAtPeriodEnd  bool
+	PendingProration   float64
+	LatestInvoiceID    string
+	DunningStep        DunningStep
+	DunningAttempts    int
+	DunningAmount      float64
+	DunningStartedAt   time.Time
+	NextRetryAt        time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	PausedAt           time.Time
+	CancelledAt        time.Time
+}
+
+type SubscriptionFilter struct {
+	AccountID  string
+	CustomerID string
+	Status     SubscriptionStatus
+	Limit      int
+}
+
+// NewSubscription starts either a trial that bills when it ends or a first
+// period that is billed right away by the next scheduler run.
+func NewSubscription(accountID, customerID, paymentMethodID string, plan *Plan, trialDays int, now time.Time) (*Subscription, error) {
+	if plan.IsArchived() {
+		return nil, ErrPlanArchived
+	}
+
+	subscription := &Subscription{
+		ID:              uuid.New().String(),
+		AccountID:       accountID,
+		CustomerID:      customerID,
+		PaymentMethodID: paymentMethodID,
+		PlanID:          plan.ID,
+		Status:          SubscriptionStatusActive,
+		NextBillingAt:   now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if trialDays > 0 {
+		subscription.Status = SubscriptionStatusTrialing
+		subscription.TrialEnd = now.AddDate(0, 0, trialDays)
+		subscription.CurrentPeriodStart = now
+		subscription.CurrentPeriodEnd = subscription.TrialEnd
+		subscription.NextBillingAt = subscription.TrialEnd
+	}
+
+	return subscription, nil
+}
+
+func (subscription *Subscription) IsBillable() bool {
+	switch subscription.Status {
+	case SubscriptionStatusTrialing, SubscriptionStatusActive, SubscriptionStatusPastDue:
+		return true
+	default:
+		return false
+	}
+}
+
+// StartPeriod moves the subscription into the period beginning at its next
+// billing date and returns the amount to invoice for it, with any pending
+// proration folded in. A non-positive amount means credit covers the period
+// and whatever is left carries over.
+func (subscription *Subscription) StartPeriod(plan *Plan) float64 {
+	start := subscription.NextBillingAt
+	amount := roundCents(plan.Amount + subscription.PendingProration)
+
+	subscription.CurrentPeriodStart = start
+	subscription.CurrentPeriodEnd = plan.PeriodEnd(start)
+	subscription.NextBillingAt = subscription.CurrentPeriodEnd
+	subscription.PendingProration = 0
+	subscription.UpdatedAt = time.Now()
+
+	if amount <= 0 {
+		subscription.PendingProration = amount
+	}
+
+	return amount
+}
+
+// ChangePlan switches plans mid-period. When prorate is set, the unused part
+// of the current period is credited at the old price and charged at the new
+// one; the difference is settled on the next invoice.
+func (subscription *Subscription) ChangePlan(from, to *Plan, prorate bool, now time.Time) error {
+	if subscription.Status == SubscriptionStatusCancelled {
+		return ErrSubscriptionCancelled
+	}
+
+	if to.IsArchived() {
+		return ErrPlanArchived
+	}
+
+	if prorate && subscription.Status != SubscriptionStatusTrialing && now.Before(subscription.CurrentPeriodEnd) {
+		total := subscription.CurrentPeriodEnd.Sub(subscription.CurrentPeriodStart)
+		remaining := subscription.CurrentPeriodEnd.Sub(now)
+
+		if total > 0 {
+			fraction := float64(remaining) / float64(total)
+			subscription.PendingProration = roundCents(subscription.PendingProration + (to.Amount-from.Amount)*fraction)
+		}
+	}
+
+	subscription.PlanID = to.ID
+	subscription.UpdatedAt = now
+
+	return nil
+}
+
+func (subscription *Subscription) Pause(now time.Time) error {
+	if !subscription.IsBillable() {
+		return ErrInvalidSubscriptionStatus
+	}
+
+	subscription.Status = SubscriptionStatusPaused
+	subscription.PausedAt = now
+	subscription.UpdatedAt = now
+
+	return nil
+}
+
+// Resume bills again once the period already paid for is over. A subscription
+// that stopped owing a period starts a fresh one immediately and any amount
+// still owed from dunning is dropped; time spent paused or unpaid is not billed.
+func (subscription *Subscription) Resume(now time.Time) error {
+	if subscription.Status != SubscriptionStatusPaused && subscription.Status != SubscriptionStatusUnpaid {
+		return ErrInvalidSubscriptionStatus
+	}
+
+	paid := subscription.Status == SubscriptionStatusPaused && subscription.DunningStep == ""
+
+	subscription.clearDunning()
+	subscription.Status = SubscriptionStatusActive
+	subscription.PausedAt = time.Time{}
+	subscription.NextBillingAt = now
+	if paid && subscription.CurrentPeriodEnd.After(now) {
+		subscription.NextBillingAt = subscription.CurrentPeriodEnd
+	}
+	subscription.UpdatedAt = now
+
+	return nil
+}
+
+func (subscription *Subscription) Cancel(atPeriodEnd bool, now time.Time) error {
+	if subscription.Status == SubscriptionStatusCancelled {
+		return ErrSubscriptionCancelled
+	}
+
+	subscription.UpdatedAt = now
+
+	if atPeriodEnd && subscription.Status != SubscriptionStatusPaused {
+		subscription.CancelAtPeriodEnd = true
+		return nil
+	}
+
+	subscription.Status = SubscriptionStatusCancelled
+	subscription.CancelledAt = now
+
+	return nil
+}
+
+// FollowInvoice maps the outcome of a period's invoice onto the subscription.
+// Pending invoices (boleto, Pix) leave the status as is until they settle, and
+// a paid period doesn't clear a debt that dunning is still chasing.
+func (subscription *Subscription) FollowInvoice(invoice *Invoice, policy DunningPolicy, now time.Time) {
+	subscription.LatestInvoiceID = invoice.ID
+	subscription.UpdatedAt = now
+
+	if subscription.Status == SubscriptionStatusCancelled || subscription.Status == SubscriptionStatusPaused {
+		return
+	}
+
+	switch invoice.Status {
+	case StatusApproved:
+		if !subscription.InDunning() {
+			subscription.Status = SubscriptionStatusActive
+		}
+	case StatusRejected:
+		subscription.PaymentFailed(policy, invoice.Amount, invoice.DeclineCode, now)
+	case StatusPending:
+		if subscription.Status == SubscriptionStatusTrialing {
+			subscription.Status = SubscriptionStatusActive
+		}
+	}
+}
+
+func (subscription *Subscription) Snapshot() map[string]any {
+	return map[string]any{
+		"customer_id":          subscription.CustomerID,
+		"payment_method_id":    subscription.PaymentMethodID,
+		"plan_id":              subscription.PlanID,
+		"status":               subscription.Status,
+		"current_period_end":   subscription.CurrentPeriodEnd,
+		"cancel_at_period_end": subscription.CancelAtPeriodEnd,
+		"pending_proration":    subscription.PendingProration,
+		"dunning_step":         subscription.DunningStep,
+		"dunning_attempts":     subscription.DunningAttempts,
+		"dunning_amount":       subscription.DunningAmount,
+	}
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
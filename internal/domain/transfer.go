@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransferStatus string
+
+const (
+	TransferStatusSucceeded TransferStatus = "succeeded"
+	TransferStatusReversed  TransferStatus = "reversed"
+)
+
+// Transfer moves available balance from one account on the platform to
+// another. A reversal moves the same amount back.
+type Transfer struct {
+	ID                   string
+	SourceAccountID      string
+	DestinationAccountID string
+	Amount               float64
+	Description          string
+	Metadata             map[string]string
+	IdempotencyKey       string
+	Status               TransferStatus
+	ReversalReason       string
+	CreatedAt            time.Time
+	ReversedAt           time.Time
+}
+
+type TransferFilter struct {
+	AccountID string
+	Status    TransferStatus
+	Limit     int
+}
+
+func NewTransfer(sourceAccountID, destinationAccountID string, amount float64, description string, metadata map[string]string, idempotencyKey string) (*Transfer, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	if sourceAccountID == destinationAccountID {
+		return nil, ErrInvalidTransferAccount
+	}
+
+	return &Transfer{
+		ID:                   uuid.New().String(),
+		SourceAccountID:      sourceAccountID,
+		DestinationAccountID: destinationAccountID,
+		Amount:               amount,
+		Description:          description,
+		Metadata:             metadata,
+		IdempotencyKey:       idempotencyKey,
+		Status:               TransferStatusSucceeded,
+		CreatedAt:            time.Now(),
+	}, nil
+}
+
+// Matches tells whether a retried request with the same idempotency key asks
+// for the same transfer.
+func (transfer *Transfer) Matches(other *Transfer) bool {
+	return transfer.SourceAccountID == other.SourceAccountID &&
+		transfer.DestinationAccountID == other.DestinationAccountID &&
+		transfer.Amount == other.Amount
+}
+
+func (transfer *Transfer) Reverse(reason string) error {
+	if transfer.Status != TransferStatusSucceeded {
+		return ErrInvalidTransferStatus
+	}
+
+	transfer.Status = TransferStatusReversed
+	transfer.ReversalReason = reason
+	transfer.ReversedAt = time.Now()
+
+	return nil
+}
+
+func (transfer *Transfer) Snapshot() map[string]any {
+	return map[string]any{
+		"source_account_id":      transfer.SourceAccountID,
+		"destination_account_id": transfer.DestinationAccountID,
+		"amount":                 transfer.Amount,
+		"description":            transfer.Description,
+		"metadata":               transfer.Metadata,
+		"status":                 transfer.Status,
+		"reversal_reason":        transfer.ReversalReason,
+	}
+}
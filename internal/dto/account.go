@@ -11,29 +11,90 @@ type CreateAccountInput struct {
 	Email string `json:"email"`
 }
 
+type UpdateAccountInput struct {
+	Name             string          `json:"name"`
+	Email            string          `json:"email"`
+	InvoiceGraceDays *int            `json:"invoice_grace_days"`
+	ChargeDefaults   *ChargeDefaults `json:"charge_defaults"`
+}
+
 type AccountOutput struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	Email     string     `json:"email"`
-	Balance   float64    `json:"balance"`
-	APIKey    string     `json:"api_key,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at"`
+	ID                  string                     `json:"id"`
+	Name                string                     `json:"name"`
+	Email               string                     `json:"email"`
+	Balance             float64                    `json:"balance"`
+	Balances            BalancesOutput             `json:"balances"`
+	UpcomingSettlements []UpcomingSettlementOutput `json:"upcoming_settlements,omitempty"`
+	InvoiceGraceDays    int                        `json:"invoice_grace_days"`
+	ChargeDefaults      ChargeDefaults             `json:"charge_defaults"`
+	FeeSchedule         FeeSchedule                `json:"fee_schedule,omitempty"`
+	ReservePolicy       *ReservePolicyOutput       `json:"reserve_policy,omitempty"`
+	APIKey              string                     `json:"api_key,omitempty"`
+	CreatedAt           time.Time                  `json:"created_at"`
+	UpdatedAt           time.Time                  `json:"updated_at"`
+	DeletedAt           *time.Time                 `json:"deleted_at"`
+	FrozenAt            *time.Time                 `json:"frozen_at"`
+}
+
+// BalancesOutput breaks the balance down; Balance on the account keeps
+// reporting the available amount for existing clients.
+type BalancesOutput struct {
+	Available float64 `json:"available"`
+	Pending   float64 `json:"pending"`
+	Reserved  float64 `json:"reserved"`
+	Held      float64 `json:"held"`
 }
 
-func ToAccount(input CreateAccountInput) *domain.Account {
-	return domain.NewAccount(input.Name, input.Email)
+type UpcomingSettlementOutput struct {
+	Date   string  `json:"date"`
+	Amount float64 `json:"amount"`
+}
+
+func ToAccount(input CreateAccountInput) (*domain.Account, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return domain.NewAccount(input.Name, input.Email), nil
 }
 
 func FromAccount(account *domain.Account) AccountOutput {
+	var deletedAt, frozenAt *time.Time
+	if account.IsDeleted() {
+		deletedAt = &account.DeletedAt
+	}
+
+	if account.IsFrozen() {
+		frozenAt = &account.FrozenAt
+	}
+
 	return AccountOutput{
-		ID:        account.ID,
-		Name:      account.Name,
-		Email:     account.Email,
-		Balance:   account.Balance,
-		APIKey:    account.APIKey,
-		CreatedAt: account.CreatedAt,
-		UpdatedAt: account.UpdatedAt,
+		ID:      account.ID,
+		Name:    account.Name,
+		Email:   account.Email,
+		Balance: account.Balance,
+		Balances: BalancesOutput{
+			Available: account.Balance,
+			Pending:   account.PendingBalance,
+			Reserved:  account.ReservedBalance,
+			Held:      account.HeldBalance,
+		},
+		InvoiceGraceDays: account.InvoiceGraceDays,
+		ChargeDefaults:   FromChargeDefaults(account.ChargeDefaults),
+		ReservePolicy:    FromReservePolicy(account.ReservePolicy),
+		APIKey:           account.APIKey,
+		CreatedAt:        account.CreatedAt,
+		UpdatedAt:        account.UpdatedAt,
+		DeletedAt:        deletedAt,
+		FrozenAt:         frozenAt,
 	}
 }
+
+func FromUpcomingSettlements(upcoming []domain.UpcomingSettlement) []UpcomingSettlementOutput {
+	output := make([]UpcomingSettlementOutput, len(upcoming))
+	for i, settlement := range upcoming {
+		output[i] = UpcomingSettlementOutput{Date: settlement.Date.Format(time.DateOnly), Amount: settlement.Amount}
+	}
+
+	return output
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type CreateAPIKeyInput struct {
+	Type   string   `json:"type"`
+	Scopes []string `json:"scopes"`
+}
+
+type APIKeyOutput struct {
+	ID        string     `json:"id"`
+	AccountID string     `json:"account_id"`
+	Key       string     `json:"key,omitempty"`
+	Type      string     `json:"type"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+func ToAPIKey(input CreateAPIKeyInput, accountID string) (*domain.APIKey, error) {
+	scopes := make([]domain.Scope, len(input.Scopes))
+	for i, scope := range input.Scopes {
+		scopes[i] = domain.Scope(scope)
+	}
+
+	return domain.NewAPIKey(accountID, domain.KeyType(input.Type), scopes)
+}
+
+func FromAPIKey(apiKey *domain.APIKey) APIKeyOutput {
+	scopes := make([]string, len(apiKey.Scopes))
+	for i, scope := range apiKey.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	var revokedAt *time.Time
+	if apiKey.IsRevoked() {
+		revokedAt = &apiKey.RevokedAt
+	}
+
+	return APIKeyOutput{
+		ID:        apiKey.ID,
+		AccountID: apiKey.AccountID,
+		Type:      string(apiKey.Type),
+		Scopes:    scopes,
+		CreatedAt: apiKey.CreatedAt,
+		UpdatedAt: apiKey.UpdatedAt,
+		RevokedAt: revokedAt,
+	}
+}
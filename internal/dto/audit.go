@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type AuditEntryOutput struct {
+	ID         string         `json:"id"`
+	Seq        int64          `json:"seq"`
+	ActorType  string         `json:"actor_type"`
+	ActorID    string         `json:"actor_id"`
+	Action     string         `json:"action"`
+	TargetType string         `json:"target_type"`
+	TargetID   string         `json:"target_id"`
+	Before     map[string]any `json:"before"`
+	After      map[string]any `json:"after"`
+	IP         string         `json:"ip"`
+	RequestID  string         `json:"request_id"`
+	Hash       string         `json:"hash"`
+	PrevHash   string         `json:"prev_hash"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type AuditVerificationOutput struct {
+	Valid       bool   `json:"valid"`
+	Checked     int    `json:"checked"`
+	LastHash    string `json:"last_hash,omitempty"`
+	BrokenAtSeq int64  `json:"broken_at_seq,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func FromAuditEntry(entry *domain.AuditEntry) AuditEntryOutput {
+	return AuditEntryOutput{
+		ID:         entry.ID,
+		Seq:        entry.Seq,
+		ActorType:  string(entry.ActorType),
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Before:     entry.Before,
+		After:      entry.After,
+		IP:         entry.IP,
+		RequestID:  entry.RequestID,
+		Hash:       entry.Hash,
+		PrevHash:   entry.PrevHash,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
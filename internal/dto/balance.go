@@ -0,0 +1,112 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	monthLayout = "2006-01"
+)
+
+// BalanceTransactionQuery holds the filters of GET /balance/transactions as
+// they come in the query string. Dates are inclusive and in YYYY-MM-DD.
+type BalanceTransactionQuery struct {
+	Type       string
+	SourceType string
+	SourceID   string
+	From       string
+	To         string
+}
+
+type BalanceTransactionOutput struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	Amount       float64   `json:"amount"`
+	BalanceAfter float64   `json:"balance_after"`
+	SourceType   string    `json:"source_type"`
+	SourceID     string    `json:"source_id,omitempty"`
+	Description  string    `json:"description"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type StatementOutput struct {
+	AccountID      string                     `json:"account_id"`
+	Month          string                     `json:"month"`
+	OpeningBalance float64                    `json:"opening_balance"`
+	ClosingBalance float64                    `json:"closing_balance"`
+	Credits        float64                    `json:"credits"`
+	Debits         float64                    `json:"debits"`
+	Transactions   []BalanceTransactionOutput `json:"transactions"`
+}
+
+func ToBalanceTransactionFilter(query BalanceTransactionQuery, accountID string, limit int) (domain.BalanceTransactionFilter, error) {
+	if err := query.Validate(); err != nil {
+		return domain.BalanceTransactionFilter{}, err
+	}
+
+	filter := domain.BalanceTransactionFilter{
+		AccountID:  accountID,
+		Type:       domain.BalanceTransactionType(query.Type),
+		SourceType: query.SourceType,
+		SourceID:   query.SourceID,
+		Limit:      limit,
+	}
+
+	if query.From != "" {
+		filter.From, _ = time.ParseInLocation(dateLayout, query.From, time.Local)
+	}
+
+	if query.To != "" {
+		to, _ := time.ParseInLocation(dateLayout, query.To, time.Local)
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
+// ParseStatementMonth reads a statement period in YYYY-MM.
+func ParseStatementMonth(month string) (time.Time, error) {
+	start, err := time.ParseInLocation(monthLayout, month, time.Local)
+	if err != nil {
+		return time.Time{}, domain.NewValidationError(domain.NewFieldError("month", "invalid_format", "must be a month in YYYY-MM"))
+	}
+
+	return start, nil
+}
+
+func FromBalanceTransaction(transaction *domain.BalanceTransaction) BalanceTransactionOutput {
+	return BalanceTransactionOutput{
+		ID:           transaction.ID,
+		Type:         string(transaction.Type),
+		Amount:       transaction.Amount,
+		BalanceAfter: transaction.BalanceAfter,
+		SourceType:   transaction.SourceType,
+		SourceID:     transaction.SourceID,
+		Description:  transaction.Description,
+		CreatedAt:    transaction.CreatedAt,
+	}
+}
+
+func FromBalanceTransactions(transactions []*domain.BalanceTransaction) []BalanceTransactionOutput {
+	outputs := make([]BalanceTransactionOutput, 0, len(transactions))
+	for _, transaction := range transactions {
+		outputs = append(outputs, FromBalanceTransaction(transaction))
+	}
+
+	return outputs
+}
+
+func FromStatement(statement *domain.Statement) StatementOutput {
+	return StatementOutput{
+		AccountID:      statement.AccountID,
+		Month:          statement.Month.Format(monthLayout),
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		Credits:        statement.Credits,
+		Debits:         statement.Debits,
+		Transactions:   FromBalanceTransactions(statement.Transactions),
+	}
+}
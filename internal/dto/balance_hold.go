@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+// UpdateReservePolicyInput sets the rolling reserve of an account. Zero
+// percent and days turn it off.
+type UpdateReservePolicyInput struct {
+	Percent float64 `json:"percent"`
+	Days    int     `json:"days"`
+	Reason  string  `json:"reason"`
+}
+
+// CreateBalanceHoldInput holds part of the available balance. Without
+// release_at the hold stays until an operator releases it.
+type CreateBalanceHoldInput struct {
+	Amount    float64    `json:"amount"`
+	Reason    string     `json:"reason"`
+	ReleaseAt *time.Time `json:"release_at"`
+}
+
+type ReleaseBalanceHoldInput struct {
+	Reason string `json:"reason"`
+}
+
+type ReservePolicyOutput struct {
+	Percent float64 `json:"percent"`
+	Days    int     `json:"days"`
+}
+
+type BalanceHoldOutput struct {
+	ID         string     `json:"id"`
+	AccountID  string     `json:"account_id"`
+	Kind       string     `json:"kind"`
+	Amount     float64    `json:"amount"`
+	Reason     string     `json:"reason,omitempty"`
+	InvoiceID  string     `json:"invoice_id,omitempty"`
+	Status     string     `json:"status"`
+	ReleaseAt  *time.Time `json:"release_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReleasedAt *time.Time `json:"released_at"`
+}
+
+func ToBalanceHold(input CreateBalanceHoldInput, accountID string) (*domain.BalanceHold, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	var releaseAt time.Time
+	if input.ReleaseAt != nil {
+		releaseAt = *input.ReleaseAt
+	}
+
+	return domain.NewManualHold(accountID, input.Amount, input.Reason, releaseAt)
+}
+
+func FromReservePolicy(policy domain.ReservePolicy) *ReservePolicyOutput {
+	if !policy.IsEnabled() {
+		return nil
+	}
+
+	return &ReservePolicyOutput{Percent: policy.Percent, Days: policy.Days}
+}
+
+func FromBalanceHold(hold *domain.BalanceHold) BalanceHoldOutput {
+	return BalanceHoldOutput{
+		ID:         hold.ID,
+		AccountID:  hold.AccountID,
+		Kind:       string(hold.Kind),
+		Amount:     hold.Amount,
+		Reason:     hold.Reason,
+		InvoiceID:  hold.InvoiceID,
+		Status:     string(hold.Status),
+		ReleaseAt:  optionalTime(hold.ReleaseAt),
+		CreatedAt:  hold.CreatedAt,
+		ReleasedAt: optionalTime(hold.ReleasedAt),
+	}
+}
+
+func FromBalanceHolds(holds []*domain.BalanceHold) []BalanceHoldOutput {
+	output := make([]BalanceHoldOutput, len(holds))
+	for i, hold := range holds {
+		output[i] = FromBalanceHold(hold)
+	}
+
+	return output
+}
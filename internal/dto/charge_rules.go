@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"sort"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type Adjustment struct {
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+}
+
+type DiscountTier struct {
+	Type  string    `json:"type"`
+	Value float64   `json:"value"`
+	Until time.Time `json:"until"`
+}
+
+type DiscountRule struct {
+	Type          string  `json:"type"`
+	Value         float64 `json:"value"`
+	DaysBeforeDue int     `json:"days_before_due"`
+}
+
+type ChargeDefaults struct {
+	Fine      *Adjustment    `json:"fine"`
+	Interest  *Adjustment    `json:"interest"`
+	Discounts []DiscountRule `json:"discounts"`
+}
+
+func toFine(input *Adjustment) *domain.Fine {
+	if input == nil {
+		return nil
+	}
+
+	return &domain.Fine{Type: domain.AdjustmentType(input.Type), Value: input.Value}
+}
+
+func toInterest(input *Adjustment) *domain.Interest {
+	if input == nil {
+		return nil
+	}
+
+	return &domain.Interest{Type: domain.AdjustmentType(input.Type), Value: input.Value}
+}
+
+func toDiscounts(input []DiscountTier) []domain.Discount {
+	discounts := make([]domain.Discount, len(input))
+	for i, tier := range input {
+		discounts[i] = domain.Discount{Type: domain.AdjustmentType(tier.Type), Value: tier.Value, Until: tier.Until}
+	}
+
+	sort.Slice(discounts, func(i, j int) bool { return discounts[i].Until.Before(discounts[j].Until) })
+
+	return discounts
+}
+
+func ToChargeDefaults(input ChargeDefaults) domain.ChargeDefaults {
+	defaults := domain.ChargeDefaults{
+		Fine:     toFine(input.Fine),
+		Interest: toInterest(input.Interest),
+	}
+
+	for _, rule := range input.Discounts {
+		defaults.Discounts = append(defaults.Discounts, domain.DiscountRule{
+			Type:          domain.AdjustmentType(rule.Type),
+			Value:         rule.Value,
+			DaysBeforeDue: rule.DaysBeforeDue,
+		})
+	}
+
+	sort.Slice(defaults.Discounts, func(i, j int) bool {
+		return defaults.Discounts[i].DaysBeforeDue > defaults.Discounts[j].DaysBeforeDue
+	})
+
+	return defaults
+}
+
+func fromFine(fine *domain.Fine) *Adjustment {
+	if fine == nil {
+		return nil
+	}
+
+	return &Adjustment{Type: string(fine.Type), Value: fine.Value}
+}
+
+func fromInterest(interest *domain.Interest) *Adjustment {
+	if interest == nil {
+		return nil
+	}
+
+	return &Adjustment{Type: string(interest.Type), Value: interest.Value}
+}
+
+func fromDiscounts(discounts []domain.Discount) []DiscountTier {
+	output := make([]DiscountTier, len(discounts))
+	for i, discount := range discounts {
+		output[i] = DiscountTier{Type: string(discount.Type), Value: discount.Value, Until: discount.Until}
+	}
+
+	return output
+}
+
+func FromChargeDefaults(defaults domain.ChargeDefaults) ChargeDefaults {
+	output := ChargeDefaults{
+		Fine:      fromFine(defaults.Fine),
+		Interest:  fromInterest(defaults.Interest),
+		Discounts: make([]DiscountRule, len(defaults.Discounts)),
+	}
+
+	for i, rule := range defaults.Discounts {
+		output.Discounts[i] = DiscountRule{Type: string(rule.Type), Value: rule.Value, DaysBeforeDue: rule.DaysBeforeDue}
+	}
+
+	return output
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+// GenerateRemittanceInput caps how many boletos the remittance registers;
+// zero means as many as allowed.
+type GenerateRemittanceInput struct {
+	Limit int `json:"limit"`
+}
+
+type CNABFileOutput struct {
+	ID        string                `json:"id"`
+	Kind      string                `json:"kind"`
+	Sequence  int                   `json:"sequence"`
+	FileName  string                `json:"file_name"`
+	Items     []domain.CNABFileItem `json:"items,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// FromCNABFile leaves the content out; it is served by the download route.
+func FromCNABFile(file *domain.CNABFile, withItems bool) CNABFileOutput {
+	output := CNABFileOutput{
+		ID:        file.ID,
+		Kind:      string(file.Kind),
+		Sequence:  file.Sequence,
+		FileName:  file.FileName,
+		CreatedAt: file.CreatedAt,
+	}
+
+	if withItems {
+		output.Items = file.Items
+	}
+
+	return output
+}
+
+func FromCNABFiles(files []*domain.CNABFile) []CNABFileOutput {
+	outputs := make([]CNABFileOutput, 0, len(files))
+	for _, file := range files {
+		outputs = append(outputs, FromCNABFile(file, false))
+	}
+
+	return outputs
+}
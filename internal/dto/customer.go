@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/taxid"
+)
+
+type CustomerInput struct {
+	Name     string `json:"name"`
+	TaxID    string `json:"tax_id"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Address  string `json:"address"`
+	Number   string `json:"number"`
+	District string `json:"district"`
+	City     string `json:"city"`
+	State    string `json:"state"`
+	ZipCode  string `json:"zip_code"`
+}
+
+type CustomerTotalsOutput struct {
+	InvoiceCount   int        `json:"invoice_count"`
+	TotalAmount    float64    `json:"total_amount"`
+	ApprovedAmount float64    `json:"approved_amount"`
+	LastInvoiceAt  *time.Time `json:"last_invoice_at"`
+}
+
+type CustomerOutput struct {
+	ID        string                `json:"id"`
+	AccountID string                `json:"account_id"`
+	Name      string                `json:"name"`
+	TaxID     string                `json:"tax_id"`
+	Email     string                `json:"email"`
+	Phone     string                `json:"phone"`
+	Address   string                `json:"address"`
+	Number    string                `json:"number"`
+	District  string                `json:"district"`
+	City      string                `json:"city"`
+	State     string                `json:"state"`
+	ZipCode   string                `json:"zip_code"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	DeletedAt *time.Time            `json:"deleted_at"`
+	Totals    *CustomerTotalsOutput `json:"totals,omitempty"`
+}
+
+func (input CustomerInput) Validate() error {
+	v := &validator{}
+	input.validate(v, "", false)
+
+	return v.err()
+}
+
+func (input CustomerInput) ValidateUpdate() error {
+	v := &validator{}
+	if input == (CustomerInput{}) {
+		v.add("", "empty_update", "at least one field is required")
+	}
+
+	input.validate(v, "", true)
+
+	return v.err()
+}
+
+// ToPayer normalizes the payload the same way whether it arrives on its own
+// or embedded in an invoice, so blind indexes match across both paths.
+func ToPayer(input CustomerInput) domain.Payer {
+	zipCode := ""
+	if input.ZipCode != "" {
+		zipCode = onlyDigits(input.ZipCode)
+	}
+
+	return domain.Payer{
+		Name:     strings.TrimSpace(input.Name),
+		TaxID:    taxid.Normalize(input.TaxID),
+		Email:    strings.ToLower(strings.TrimSpace(input.Email)),
+		Phone:    onlyDigits(input.Phone),
+		Address:  input.Address,
+		Number:   input.Number,
+		District: input.District,
+		City:     input.City,
+		State:    strings.ToUpper(input.State),
+		ZipCode:  zipCode,
+	}
+}
+
+func FromCustomer(payer *domain.Payer, totals *domain.CustomerTotals) CustomerOutput {
+	var deletedAt *time.Time
+	if payer.IsDeleted() {
+		deletedAt = &payer.DeletedAt
+	}
+
+	output := CustomerOutput{
+		ID:        payer.ID,
+		AccountID: payer.AccountID,
+		Name:      payer.Name,
+		TaxID:     payer.TaxID,
+		Email:     payer.Email,
+		Phone:     payer.Phone,
+		Address:   payer.Address,
+		Number:    payer.Number,
+		District:  payer.District,
+		City:      payer.City,
+		State:     payer.State,
+		ZipCode:   payer.ZipCode,
+		CreatedAt: payer.CreatedAt,
+		UpdatedAt: payer.UpdatedAt,
+		DeletedAt: deletedAt,
+	}
+
+	if totals != nil {
+		output.Totals = &CustomerTotalsOutput{
+			InvoiceCount:   totals.InvoiceCount,
+			TotalAmount:    totals.TotalAmount,
+			ApprovedAmount: totals.ApprovedAmount,
+		}
+
+		if !totals.LastInvoiceAt.IsZero() {
+			output.Totals.LastInvoiceAt = &totals.LastInvoiceAt
+		}
+	}
+
+	return output
+}
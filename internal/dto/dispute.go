@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+const (
+	MaxEvidenceFiles      = 5
+	MaxEvidenceFileSize   = 5 << 20
+	maxEvidenceTextLength = 20000
+)
+
+var evidenceContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+}
+
+// SimulateDisputeInput opens a dispute the way the card network would. A
+// zero amount contests everything that was paid and not refunded.
+type SimulateDisputeInput struct {
+	InvoiceID string  `json:"invoice_id"`
+	Reason    string  `json:"reason"`
+	Amount    float64 `json:"amount"`
+}
+
+type ResolveDisputeInput struct {
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason"`
+}
+
+// AddDisputeEvidenceInput is a multipart upload: an optional statement and
+// any number of files up to MaxEvidenceFiles.
+type AddDisputeEvidenceInput struct {
+	Text  string
+	Files []DisputeEvidenceFile
+}
+
+type DisputeEvidenceFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+type DisputeHoldOutput struct {
+	AccountID string  `json:"account_id"`
+	Amount    float64 `json:"amount"`
+}
+
+type DisputeEvidenceOutput struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	Text        string    `json:"text,omitempty"`
+	FileName    string    `json:"file_name,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	Size        int       `json:"size,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type DisputeOutput struct {
+	ID          string                  `json:"id"`
+	InvoiceID   string                  `json:"invoice_id"`
+	Reason      string                  `json:"reason"`
+	Amount      float64                 `json:"amount"`
+	Status      string                  `json:"status"`
+	Holds       []DisputeHoldOutput     `json:"holds"`
+	Evidence    []DisputeEvidenceOutput `json:"evidence,omitempty"`
+	Deadline    time.Time               `json:"deadline"`
+	CreatedAt   time.Time               `json:"created_at"`
+	SubmittedAt *time.Time              `json:"submitted_at"`
+	ResolvedAt  *time.Time              `json:"resolved_at"`
+}
+
+func ToDisputeEvidence(input AddDisputeEvidenceInput, disputeID string) ([]*domain.DisputeEvidence, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	var evidence []*domain.DisputeEvidence
+	if input.Text != "" {
+		evidence = append(evidence, domain.NewDisputeTextEvidence(disputeID, input.Text))
+	}
+
+	for _, file := range input.Files {
+		evidence = append(evidence, domain.NewDisputeFileEvidence(disputeID, file.Name, file.ContentType, file.Content))
+	}
+
+	return evidence, nil
+}
+
+func FromDispute(dispute *domain.Dispute, evidence []*domain.DisputeEvidence) DisputeOutput {
+	holds := make([]DisputeHoldOutput, len(dispute.Holds))
+	for i, hold := range dispute.Holds {
+		holds[i] = DisputeHoldOutput{AccountID: hold.AccountID, Amount: hold.Amount}
+	}
+
+	output := DisputeOutput{
+		ID:          dispute.ID,
+		InvoiceID:   dispute.InvoiceID,
+		Reason:      string(dispute.Reason),
+		Amount:      dispute.Amount,
+		Status:      string(dispute.Status),
+		Holds:       holds,
+		Deadline:    dispute.Deadline,
+		CreatedAt:   dispute.CreatedAt,
+		SubmittedAt: optionalTime(dispute.SubmittedAt),
+		ResolvedAt:  optionalTime(dispute.ResolvedAt),
+	}
+
+	for _, item := range evidence {
+		output.Evidence = append(output.Evidence, DisputeEvidenceOutput{
+			ID:          item.ID,
+			Kind:        string(item.Kind),
+			Text:        item.Text,
+			FileName:    item.FileName,
+			ContentType: item.ContentType,
+			Size:        item.Size,
+			CreatedAt:   item.CreatedAt,
+		})
+	}
+
+	return output
+}
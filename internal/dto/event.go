@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type EventOutput struct {
+	ID           string         `json:"id"`
+	Type         string         `json:"type"`
+	ResourceType string         `json:"resource_type"`
+	ResourceID   string         `json:"resource_id"`
+	Data         map[string]any `json:"data"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+func FromEvent(event *domain.Event) EventOutput {
+	return EventOutput{
+		ID:           event.ID,
+		Type:         event.Type,
+		ResourceType: event.ResourceType,
+		ResourceID:   event.ResourceID,
+		Data:         event.Data,
+		CreatedAt:    event.CreatedAt,
+	}
+}
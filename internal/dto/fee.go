@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type FeeRule struct {
+	Percent            float64 `json:"percent"`
+	Fixed              float64 `json:"fixed"`
+	InstallmentPercent float64 `json:"installment_percent"`
+}
+
+// FeeSchedule is keyed by payment method.
+type FeeSchedule map[string]FeeRule
+
+type UpdateFeeScheduleInput struct {
+	Fees   FeeSchedule `json:"fees"`
+	Reason string      `json:"reason"`
+}
+
+func ToFeeSchedule(input FeeSchedule) domain.FeeSchedule {
+	schedule := domain.FeeSchedule{}
+	for method, rule := range input {
+		schedule[domain.PaymentMethod(method)] = domain.FeeRule{
+			Percent:            rule.Percent,
+			Fixed:              rule.Fixed,
+			InstallmentPercent: rule.InstallmentPercent,
+		}
+	}
+
+	return schedule
+}
+
+func FromFeeSchedule(schedule domain.FeeSchedule) FeeSchedule {
+	output := FeeSchedule{}
+	for method, rule := range schedule {
+		output[string(method)] = FeeRule{
+			Percent:            rule.Percent,
+			Fixed:              rule.Fixed,
+			InstallmentPercent: rule.InstallmentPercent,
+		}
+	}
+
+	return output
+}
@@ -13,29 +13,24 @@ const (
 )
 
 type CreateInvoiceInput struct {
-	APIKey         string
-	Amount         float64   `json:"amount"`
-	Description    string    `json:"description"`
-	PaymentType    string    `json:"payment_type"`
-	CardNumber     string    `json:"card_number"`
-	CVV            string    `json:"cvv"`
-	ExpiryMonth    int       `json:"expiry_month"`
-	ExpiryYear     int       `json:"expiry_year"`
-	CardholderName string    `json:"cardholder_name"`
-	DueDate        time.Time `json:"due_date"`
-	Reference      string    `json:"reference"`
-	Payer          struct {
-		Name     string `json:"name"`
-		TaxID    string `json:"tax_id"`
-		Email    string `json:"email"`
-		Phone    string `json:"phone"`
-		Address  string `json:"address"`
-		Number   string `json:"number"`
-		District string `json:"district"`
-		City     string `json:"city"`
-		State    string `json:"state"`
-		ZipCode  string `json:"zip_code"`
-	} `json:"payer"`
+	Amount          float64        `json:"amount"`
+	Description     string         `json:"description"`
+	PaymentType     string         `json:"payment_type"`
+	CardNumber      string         `json:"card_number"`
+	CVV             string         `json:"cvv"`
+	ExpiryMonth     int            `json:"expiry_month"`
+	ExpiryYear      int            `json:"expiry_year"`
+	CardholderName  string         `json:"cardholder_name"`
+	DueDate         time.Time      `json:"due_date"`
+	Reference       string         `json:"reference"`
+	CustomerID      string         `json:"customer_id"`
+	PaymentMethodID string         `json:"payment_method_id"`
+	Fine            *Adjustment    `json:"fine"`
+	Interest        *Adjustment    `json:"interest"`
+	Discounts       []DiscountTier `json:"discounts"`
+	Installments    int            `json:"installments"`
+	Splits          []SplitRule    `json:"splits"`
+	Payer           CustomerInput  `json:"payer"`
 }
 
 type PayerOutput struct {
@@ -52,53 +47,81 @@ type PayerOutput struct {
 }
 
 type InvoiceOutput struct {
-	ID             string      `json:"id"`
-	AccountID      string      `json:"account_id"`
-	Amount         float64     `json:"amount"`
-	Status         string      `json:"status"`
-	Description    string      `json:"description"`
-	PaymentType    string      `json:"payment_type"`
-	CardLastDigits string      `json:"card_last_digits"`
-	Reference      string      `json:"reference"`
-	DueDate        time.Time   `json:"due_date"`
-	CreatedAt      time.Time   `json:"created_at"`
-	UpdatedAt      time.Time   `json:"updated_at"`
-	DeletedAt      *time.Time  `json:"deleted_at"`
-	Payer          PayerOutput `json:"payer"`
+	ID              string         `json:"id"`
+	AccountID       string         `json:"account_id"`
+	CustomerID      string         `json:"customer_id"`
+	PaymentMethodID string         `json:"payment_method_id,omitempty"`
+	SubscriptionID  string         `json:"subscription_id,omitempty"`
+	Amount          float64        `json:"amount"`
+	Status          string         `json:"status"`
+	Description     string         `json:"description"`
+	PaymentType     string         `json:"payment_type"`
+	CardLastDigits  string         `json:"card_last_digits"`
+	DeclineCode     string         `json:"decline_code,omitempty"`
+	Reference       string         `json:"reference"`
+	DueDate         time.Time      `json:"due_date"`
+	GraceDays       int            `json:"grace_days"`
+	ExpiresAt       time.Time      `json:"expires_at"`
+	Fine            *Adjustment    `json:"fine,omitempty"`
+	Interest        *Adjustment    `json:"interest,omitempty"`
+	Discounts       []DiscountTier `json:"discounts,omitempty"`
+	Installments    int            `json:"installments"`
+	Splits          []SplitRule    `json:"splits,omitempty"`
+	FeeAmount       float64        `json:"fee_amount"`
+	NetAmount       float64        `json:"net_amount"`
+	PaidAmount      float64        `json:"paid_amount"`
+	RefundedAmount  float64        `json:"refunded_amount"`
+	PaidAt          *time.Time     `json:"paid_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       *time.Time     `json:"deleted_at"`
+	Payer           PayerOutput    `json:"payer"`
 }
 
 func ToInvoice(input CreateInvoiceInput, accountID string) (*domain.Invoice, error) {
-	card := domain.CreditCard{
-		Number:         input.CardNumber,
-		CVV:            input.CVV,
-		ExpiryMonth:    input.ExpiryMonth,
-		ExpiryYear:     input.ExpiryYear,
-		CardholderName: input.CardholderName,
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	payer := domain.Payer{
-		Name:     input.Payer.Name,
-		TaxID:    input.Payer.TaxID,
-		Email:    input.Payer.Email,
-		Phone:    input.Payer.Phone,
-		Address:  input.Payer.Address,
-		Number:   input.Payer.Number,
-		District: input.Payer.District,
-		City:     input.Payer.City,
-		State:    input.Payer.State,
-		ZipCode:  input.Payer.ZipCode,
-	}
+	payer := ToPayer(input.Payer)
+	payer.ID = input.CustomerID
 
-	return domain.NewInvoice(
+	invoice, err := domain.NewInvoice(
 		accountID,
 		input.Amount,
 		input.Description,
 		input.PaymentType,
 		input.DueDate,
 		input.Reference,
-		card,
+		input.card(),
 		payer,
 	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Fine = toFine(input.Fine)
+	invoice.Interest = toInterest(input.Interest)
+	invoice.Discounts = toDiscounts(input.Discounts)
+
+	invoice.Splits = toSplits(input.Splits)
+
+	if input.Installments > 0 {
+		invoice.Installments = input.Installments
+	}
+
+	return invoice, nil
+}
+
+func (input CreateInvoiceInput) card() domain.CreditCard {
+	return domain.CreditCard{
+		Number:         input.CardNumber,
+		CVV:            input.CVV,
+		ExpiryMonth:    input.ExpiryMonth,
+		ExpiryYear:     input.ExpiryYear,
+		CardholderName: input.CardholderName,
+	}
 }
 
 func FromInvoice(invoice *domain.Invoice) *InvoiceOutput {
@@ -116,17 +139,33 @@ func FromInvoice(invoice *domain.Invoice) *InvoiceOutput {
 	}
 
 	return &InvoiceOutput{
-		ID:             invoice.ID,
-		AccountID:      invoice.AccountID,
-		Amount:         invoice.Amount,
-		Status:         string(invoice.Status),
-		Description:    invoice.Description,
-		PaymentType:    invoice.PaymentType,
-		CardLastDigits: invoice.CardLastDigits,
-		DueDate:        invoice.DueDate,
-		Payer:          Payer,
-		Reference:      invoice.Reference,
-		CreatedAt:      invoice.CreatedAt,
-		UpdatedAt:      invoice.UpdatedAt,
+		ID:              invoice.ID,
+		AccountID:       invoice.AccountID,
+		CustomerID:      invoice.CustomerID,
+		PaymentMethodID: invoice.PaymentMethodID,
+		SubscriptionID:  invoice.SubscriptionID,
+		Amount:          invoice.Amount,
+		Status:          string(invoice.Status),
+		Description:     invoice.Description,
+		PaymentType:     invoice.PaymentType,
+		CardLastDigits:  invoice.CardLastDigits,
+		DeclineCode:     string(invoice.DeclineCode),
+		DueDate:         invoice.DueDate,
+		GraceDays:       invoice.GraceDays,
+		ExpiresAt:       invoice.ExpiresAt(),
+		Fine:            fromFine(invoice.Fine),
+		Interest:        fromInterest(invoice.Interest),
+		Discounts:       fromDiscounts(invoice.Discounts),
+		Installments:    invoice.Installments,
+		Splits:          fromSplits(invoice.Splits),
+		FeeAmount:       invoice.FeeAmount,
+		NetAmount:       invoice.NetAmount,
+		PaidAmount:      invoice.PaidAmount,
+		RefundedAmount:  invoice.RefundedAmount,
+		PaidAt:          optionalTime(invoice.PaidAt),
+		Payer:           Payer,
+		Reference:       invoice.Reference,
+		CreatedAt:       invoice.CreatedAt,
+		UpdatedAt:       invoice.UpdatedAt,
 	}
 }
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type LoginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	TOTPCode string `json:"totp_code"`
+}
+
+type LoginOutput struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type CreateOperatorInput struct {
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	Role       string `json:"role"`
+	EnableTOTP bool   `json:"enable_totp"`
+}
+
+type OperatorOutput struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	TOTPURL   string    `json:"totp_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type BalanceAdjustmentInput struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+type FreezeAccountInput struct {
+	Reason string `json:"reason"`
+}
+
+func FromOperator(operator *domain.Operator) OperatorOutput {
+	return OperatorOutput{
+		ID:        operator.ID,
+		Name:      operator.Name,
+		Email:     operator.Email,
+		Role:      string(operator.Role),
+		CreatedAt: operator.CreatedAt,
+		UpdatedAt: operator.UpdatedAt,
+	}
+}
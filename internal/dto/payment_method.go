@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type CreatePaymentMethodInput struct {
+	Type           string `json:"type"`
+	CardNumber     string `json:"card_number"`
+	CVV            string `json:"cvv"`
+	ExpiryMonth    int    `json:"expiry_month"`
+	ExpiryYear     int    `json:"expiry_year"`
+	CardholderName string `json:"cardholder_name"`
+	IsDefault      bool   `json:"is_default"`
+}
+
+type PaymentMethodOutput struct {
+	ID             string    `json:"id"`
+	CustomerID     string    `json:"customer_id"`
+	Type           string    `json:"type"`
+	Brand          string    `json:"brand,omitempty"`
+	Last4          string    `json:"last4,omitempty"`
+	ExpiryMonth    int       `json:"expiry_month,omitempty"`
+	ExpiryYear     int       `json:"expiry_year,omitempty"`
+	CardholderName string    `json:"cardholder_name,omitempty"`
+	IsDefault      bool      `json:"is_default"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (input CreatePaymentMethodInput) card() domain.CreditCard {
+	return domain.CreditCard{
+		Number:         input.CardNumber,
+		CVV:            input.CVV,
+		ExpiryMonth:    input.ExpiryMonth,
+		ExpiryYear:     input.ExpiryYear,
+		CardholderName: input.CardholderName,
+	}
+}
+
+func FromPaymentMethod(method *domain.SavedPaymentMethod) PaymentMethodOutput {
+	return PaymentMethodOutput{
+		ID:             method.ID,
+		CustomerID:     method.CustomerID,
+		Type:           string(method.Type),
+		Brand:          method.Brand,
+		Last4:          method.Last4,
+		ExpiryMonth:    method.ExpiryMonth,
+		ExpiryYear:     method.ExpiryYear,
+		CardholderName: method.CardholderName,
+		IsDefault:      method.IsDefault,
+		Status:         string(method.Status),
+		CreatedAt:      method.CreatedAt,
+		UpdatedAt:      method.UpdatedAt,
+	}
+}
@@ -0,0 +1,146 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/taxid"
+)
+
+type CreatePayoutDestinationInput struct {
+	Type          string `json:"type"`
+	HolderName    string `json:"holder_name"`
+	HolderTaxID   string `json:"holder_tax_id"`
+	PixKeyType    string `json:"pix_key_type"`
+	PixKey        string `json:"pix_key"`
+	BankISPB      string `json:"bank_ispb"`
+	Branch        string `json:"branch"`
+	AccountNumber string `json:"account_number"`
+	AccountType   string `json:"account_type"`
+}
+
+type PayoutDestinationOutput struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	HolderName    string    `json:"holder_name"`
+	PixKeyType    string    `json:"pix_key_type,omitempty"`
+	PixKey        string    `json:"pix_key,omitempty"`
+	BankISPB      string    `json:"bank_ispb,omitempty"`
+	Branch        string    `json:"branch,omitempty"`
+	AccountNumber string    `json:"account_number,omitempty"`
+	AccountType   string    `json:"account_type,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type CreatePayoutInput struct {
+	DestinationID string  `json:"destination_id"`
+	Amount        float64 `json:"amount"`
+	Description   string  `json:"description"`
+}
+
+type PayoutOutput struct {
+	ID                 string     `json:"id"`
+	DestinationID      string     `json:"destination_id"`
+	Amount             float64    `json:"amount"`
+	Description        string     `json:"description"`
+	Status             string     `json:"status"`
+	ProviderTransferID string     `json:"provider_transfer_id,omitempty"`
+	FailureReason      string     `json:"failure_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	PaidAt             *time.Time `json:"paid_at"`
+	FailedAt           *time.Time `json:"failed_at"`
+}
+
+// pixKey normalizes the key the way the DICT stores it: digits only for
+// CPF/CNPJ, lower case for e-mails and random keys.
+func (input CreatePayoutDestinationInput) pixKey() string {
+	key := strings.TrimSpace(input.PixKey)
+
+	switch domain.PixKeyType(input.PixKeyType) {
+	case domain.PixKeyCPF, domain.PixKeyCNPJ:
+		return taxid.Normalize(key)
+	case domain.PixKeyEmail, domain.PixKeyRandom:
+		return strings.ToLower(key)
+	default:
+		return key
+	}
+}
+
+func ToPayoutDestination(input CreatePayoutDestinationInput, accountID string) (*domain.PayoutDestination, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	holderTaxID := taxid.Normalize(input.HolderTaxID)
+
+	if domain.PayoutDestinationType(input.Type) == domain.PayoutDestinationPixKey {
+		return domain.NewPixKeyDestination(accountID, input.HolderName, holderTaxID, domain.PixKeyType(input.PixKeyType), input.pixKey()), nil
+	}
+
+	return domain.NewBankAccountDestination(
+		accountID,
+		input.HolderName,
+		holderTaxID,
+		input.BankISPB,
+		input.Branch,
+		onlyDigits(input.AccountNumber),
+		domain.BankAccountType(input.AccountType),
+	), nil
+}
+
+// mask keeps only the last four characters, enough for the merchant to tell
+// destinations apart.
+func mask(value string) string {
+	if len(value) <= 4 {
+		return value
+	}
+
+	return strings.Repeat("*", len(value)-4) + value[len(value)-4:]
+}
+
+func FromPayoutDestination(destination *domain.PayoutDestination) PayoutDestinationOutput {
+	output := PayoutDestinationOutput{
+		ID:            destination.ID,
+		Type:          string(destination.Type),
+		HolderName:    destination.HolderName,
+		PixKeyType:    string(destination.PixKeyType),
+		PixKey:        destination.PixKey,
+		BankISPB:      destination.BankISPB,
+		Branch:        destination.Branch,
+		AccountNumber: mask(destination.AccountNumber),
+		AccountType:   string(destination.AccountType),
+		CreatedAt:     destination.CreatedAt,
+	}
+
+	if destination.PixKeyType == domain.PixKeyCPF || destination.PixKeyType == domain.PixKeyCNPJ {
+		output.PixKey = mask(destination.PixKey)
+	}
+
+	return output
+}
+
+func ToPayout(input CreatePayoutInput, accountID string) (*domain.Payout, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return domain.NewPayout(accountID, input.DestinationID, input.Amount, input.Description)
+}
+
+func FromPayout(payout *domain.Payout) PayoutOutput {
+	return PayoutOutput{
+		ID:                 payout.ID,
+		DestinationID:      payout.DestinationID,
+		Amount:             payout.Amount,
+		Description:        payout.Description,
+		Status:             string(payout.Status),
+		ProviderTransferID: payout.ProviderTransferID,
+		FailureReason:      payout.FailureReason,
+		CreatedAt:          payout.CreatedAt,
+		UpdatedAt:          payout.UpdatedAt,
+		PaidAt:             optionalTime(payout.PaidAt),
+		FailedAt:           optionalTime(payout.FailedAt),
+	}
+}
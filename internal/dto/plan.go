@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type CreatePlanInput struct {
+	Name          string  `json:"name"`
+	Amount        float64 `json:"amount"`
+	Interval      string  `json:"interval"`
+	IntervalCount int     `json:"interval_count"`
+	TrialDays     int     `json:"trial_days"`
+}
+
+type PlanOutput struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Amount        float64    `json:"amount"`
+	Interval      string     `json:"interval"`
+	IntervalCount int        `json:"interval_count"`
+	TrialDays     int        `json:"trial_days"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	ArchivedAt    *time.Time `json:"archived_at"`
+}
+
+func ToPlan(input CreatePlanInput, accountID string) (*domain.Plan, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return domain.NewPlan(accountID, input.Name, input.Amount, domain.PlanInterval(input.Interval), input.IntervalCount, input.TrialDays)
+}
+
+func FromPlan(plan *domain.Plan) PlanOutput {
+	output := PlanOutput{
+		ID:            plan.ID,
+		Name:          plan.Name,
+		Amount:        plan.Amount,
+		Interval:      string(plan.Interval),
+		IntervalCount: plan.IntervalCount,
+		TrialDays:     plan.TrialDays,
+		CreatedAt:     plan.CreatedAt,
+		UpdatedAt:     plan.UpdatedAt,
+	}
+
+	if plan.IsArchived() {
+		output.ArchivedAt = &plan.ArchivedAt
+	}
+
+	return output
+}
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+// RunReconciliationInput is an inclusive period in YYYY-MM-DD.
+type RunReconciliationInput struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type ReconciliationItemOutput struct {
+	Status      string     `json:"status"`
+	EntryID     string     `json:"entry_id,omitempty"`
+	EntryDate   *time.Time `json:"entry_date,omitempty"`
+	SeuNumero   string     `json:"seu_numero,omitempty"`
+	NossoNumero string     `json:"nosso_numero,omitempty"`
+	InvoiceID   string     `json:"invoice_id,omitempty"`
+	AccountID   string     `json:"account_id,omitempty"`
+	Expected    float64    `json:"expected"`
+	Received    float64    `json:"received"`
+	Note        string     `json:"note,omitempty"`
+}
+
+type ReconciliationOutput struct {
+	ID        string                       `json:"id"`
+	From      string                       `json:"from"`
+	To        string                       `json:"to"`
+	Summary   domain.ReconciliationSummary `json:"summary"`
+	Items     []ReconciliationItemOutput   `json:"items,omitempty"`
+	CreatedAt time.Time                    `json:"created_at"`
+}
+
+func ToReconciliationPeriod(input RunReconciliationInput) (time.Time, time.Time, error) {
+	if err := input.Validate(); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	from, _ := time.ParseInLocation(dateLayout, input.From, time.Local)
+	to, _ := time.ParseInLocation(dateLayout, input.To, time.Local)
+
+	return from, to, nil
+}
+
+// FromReconciliation leaves the items out unless withItems is set, so
+// listings stay small.
+func FromReconciliation(reconciliation *domain.Reconciliation, withItems bool) ReconciliationOutput {
+	output := ReconciliationOutput{
+		ID:        reconciliation.ID,
+		From:      reconciliation.From.Format(dateLayout),
+		To:        reconciliation.To.Format(dateLayout),
+		Summary:   reconciliation.Summary,
+		CreatedAt: reconciliation.CreatedAt,
+	}
+
+	if !withItems {
+		return output
+	}
+
+	output.Items = make([]ReconciliationItemOutput, len(reconciliation.Items))
+	for i, item := range reconciliation.Items {
+		output.Items[i] = ReconciliationItemOutput{
+			Status:      string(item.Status),
+			EntryID:     item.EntryID,
+			EntryDate:   optionalTime(item.EntryDate),
+			SeuNumero:   item.SeuNumero,
+			NossoNumero: item.NossoNumero,
+			InvoiceID:   item.InvoiceID,
+			AccountID:   item.AccountID,
+			Expected:    item.Expected,
+			Received:    item.Received,
+			Note:        item.Note,
+		}
+	}
+
+	return output
+}
+
+func WriteReconciliationCSV(w io.Writer, reconciliation *domain.Reconciliation) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{{"status", "entry_id", "entry_date", "seu_numero", "nosso_numero", "invoice_id", "account_id", "expected", "received", "difference", "note"}}
+	for _, item := range reconciliation.Items {
+		entryDate := ""
+		if !item.EntryDate.IsZero() {
+			entryDate = item.EntryDate.Format(dateLayout)
+		}
+
+		rows = append(rows, []string{
+			string(item.Status),
+			item.EntryID,
+			entryDate,
+			item.SeuNumero,
+			item.NossoNumero,
+			item.InvoiceID,
+			item.AccountID,
+			money(item.Expected),
+			money(item.Received),
+			money(item.Received - item.Expected),
+			item.Note,
+		})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+func FromReconciliations(reconciliations []*domain.Reconciliation) []ReconciliationOutput {
+	output := make([]ReconciliationOutput, len(reconciliations))
+	for i, reconciliation := range reconciliations {
+		output[i] = FromReconciliation(reconciliation, false)
+	}
+
+	return output
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type SplitRule struct {
+	RecipientAccountID string  `json:"recipient_account_id"`
+	Type               string  `json:"type"`
+	Value              float64 `json:"value"`
+	PaysFee            bool    `json:"pays_fee"`
+	ChargebackLiable   bool    `json:"chargeback_liable"`
+}
+
+type CreateRefundInput struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+type SplitReversalOutput struct {
+	AccountID string  `json:"account_id"`
+	Amount    float64 `json:"amount"`
+}
+
+type RefundOutput struct {
+	ID        string                `json:"id"`
+	InvoiceID string                `json:"invoice_id"`
+	Amount    float64               `json:"amount"`
+	Reason    string                `json:"reason"`
+	Reversals []SplitReversalOutput `json:"reversals"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+func toSplits(input []SplitRule) []domain.SplitRule {
+	splits := make([]domain.SplitRule, len(input))
+	for i, rule := range input {
+		splits[i] = domain.SplitRule{
+			RecipientAccountID: rule.RecipientAccountID,
+			Type:               domain.AdjustmentType(rule.Type),
+			Value:              rule.Value,
+			PaysFee:            rule.PaysFee,
+			ChargebackLiable:   rule.ChargebackLiable,
+		}
+	}
+
+	return splits
+}
+
+func fromSplits(splits []domain.SplitRule) []SplitRule {
+	output := make([]SplitRule, len(splits))
+	for i, rule := range splits {
+		output[i] = SplitRule{
+			RecipientAccountID: rule.RecipientAccountID,
+			Type:               string(rule.Type),
+			Value:              rule.Value,
+			PaysFee:            rule.PaysFee,
+			ChargebackLiable:   rule.ChargebackLiable,
+		}
+	}
+
+	return output
+}
+
+func FromRefund(refund *domain.Refund) RefundOutput {
+	reversals := make([]SplitReversalOutput, len(refund.Reversals))
+	for i, reversal := range refund.Reversals {
+		reversals[i] = SplitReversalOutput{AccountID: reversal.AccountID, Amount: reversal.Amount}
+	}
+
+	return RefundOutput{
+		ID:        refund.ID,
+		InvoiceID: refund.InvoiceID,
+		Amount:    refund.Amount,
+		Reason:    refund.Reason,
+		Reversals: reversals,
+		CreatedAt: refund.CreatedAt,
+	}
+}
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/pdf"
+)
+
+const statementTimeLayout = "2006-01-02 15:04"
+
+func WriteStatementCSV(w io.Writer, statement *domain.Statement) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{{"date", "type", "description", "source_type", "source_id", "amount", "balance_after"}}
+	rows = append(rows, []string{statement.Month.Format(dateLayout), "opening_balance", "", "", "", "", money(statement.OpeningBalance)})
+
+	for _, transaction := range statement.Transactions {
+		rows = append(rows, []string{
+			transaction.CreatedAt.Format(statementTimeLayout),
+			string(transaction.Type),
+			transaction.Description,
+			transaction.SourceType,
+			transaction.SourceID,
+			money(transaction.Amount),
+			money(transaction.BalanceAfter),
+		})
+	}
+
+	end := statement.Month.AddDate(0, 1, -1)
+	rows = append(rows, []string{end.Format(dateLayout), "closing_balance", "", "", "", "", money(statement.ClosingBalance)})
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+func WriteStatementPDF(w io.Writer, statement *domain.Statement) error {
+	document := pdf.New()
+
+	document.Heading("Statement " + statement.Month.Format(monthLayout))
+	document.Line(statement.AccountName + " (" + statement.AccountID + ")")
+	document.Blank()
+	document.Line(fmt.Sprintf("%-20s %14s", "Opening balance", money(statement.OpeningBalance)))
+	document.Line(fmt.Sprintf("%-20s %14s", "Credits", money(statement.Credits)))
+	document.Line(fmt.Sprintf("%-20s %14s", "Debits", money(-statement.Debits)))
+	document.Line(fmt.Sprintf("%-20s %14s", "Closing balance", money(statement.ClosingBalance)))
+	document.Blank()
+
+	row := "%-16s  %-14s  %-30s  %13s  %13s"
+	document.Line(fmt.Sprintf(row, "Date", "Type", "Description", "Amount", "Balance"))
+
+	for _, transaction := range statement.Transactions {
+		document.Line(fmt.Sprintf(row,
+			transaction.CreatedAt.Format(statementTimeLayout),
+			transaction.Type,
+			truncate(transaction.Description, 30),
+			money(transaction.Amount),
+			money(transaction.BalanceAfter),
+		))
+	}
+
+	if len(statement.Transactions) == 0 {
+		document.Line("No balance transactions in this period.")
+	}
+
+	_, err := document.WriteTo(w)
+
+	return err
+}
+
+func money(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length-1]) + "~"
+}
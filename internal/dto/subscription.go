@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type CreateSubscriptionInput struct {
+	CustomerID      string `json:"customer_id"`
+	PlanID          string `json:"plan_id"`
+	PaymentMethodID string `json:"payment_method_id"`
+	TrialDays       *int   `json:"trial_days"`
+}
+
+type ChangePlanInput struct {
+	PlanID  string `json:"plan_id"`
+	Prorate *bool  `json:"prorate"`
+}
+
+type CancelSubscriptionInput struct {
+	AtPeriodEnd bool `json:"at_period_end"`
+}
+
+type SubscriptionOutput struct {
+	ID                 string     `json:"id"`
+	CustomerID         string     `json:"customer_id"`
+	PlanID             string     `json:"plan_id"`
+	PaymentMethodID    string     `json:"payment_method_id,omitempty"`
+	Status             string     `json:"status"`
+	CurrentPeriodStart *time.Time `json:"current_period_start"`
+	CurrentPeriodEnd   *time.Time `json:"current_period_end"`
+	NextBillingAt      *time.Time `json:"next_billing_at"`
+	TrialEnd           *time.Time `json:"trial_end"`
+	CancelAtPeriodEnd  bool       `json:"cancel_at_period_end"`
+	PendingProration   float64    `json:"pending_proration"`
+	LatestInvoiceID    string     `json:"latest_invoice_id,omitempty"`
+	AmountDue          float64    `json:"amount_due,omitempty"`
+	DunningAttempts    int        `json:"dunning_attempts,omitempty"`
+	NextRetryAt        *time.Time `json:"next_retry_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	PausedAt           *time.Time `json:"paused_at"`
+	CancelledAt        *time.Time `json:"cancelled_at"`
+}
+
+func FromSubscription(subscription *domain.Subscription) SubscriptionOutput {
+	output := SubscriptionOutput{
+		ID:                 subscription.ID,
+		CustomerID:         subscription.CustomerID,
+		PlanID:             subscription.PlanID,
+		PaymentMethodID:    subscription.PaymentMethodID,
+		Status:             string(subscription.Status),
+		CurrentPeriodStart: optionalTime(subscription.CurrentPeriodStart),
+		CurrentPeriodEnd:   optionalTime(subscription.CurrentPeriodEnd),
+		TrialEnd:           optionalTime(subscription.TrialEnd),
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+		PendingProration:   subscription.PendingProration,
+		LatestInvoiceID:    subscription.LatestInvoiceID,
+		CreatedAt:          subscription.CreatedAt,
+		UpdatedAt:          subscription.UpdatedAt,
+		PausedAt:           optionalTime(subscription.PausedAt),
+		CancelledAt:        optionalTime(subscription.CancelledAt),
+	}
+
+	if subscription.InDunning() {
+		output.AmountDue = subscription.DunningAmount
+		output.DunningAttempts = subscription.DunningAttempts
+		output.NextRetryAt = optionalTime(subscription.NextRetryAt)
+	}
+
+	if subscription.IsBillable() {
+		output.NextBillingAt = optionalTime(subscription.NextBillingAt)
+	}
+
+	return output
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+const (
+	maxMetadataKeys         = 20
+	maxMetadataKeyLength    = 40
+	maxMetadataValueLength  = 500
+	maxIdempotencyKeyLength = 255
+)
+
+type CreateTransferInput struct {
+	DestinationAccountID string            `json:"destination_account_id"`
+	Amount               float64           `json:"amount"`
+	Description          string            `json:"description"`
+	Metadata             map[string]string `json:"metadata"`
+}
+
+// AdminTransferInput lets an operator move money out of any account, such as
+// the platform's own.
+type AdminTransferInput struct {
+	SourceAccountID string `json:"source_account_id"`
+	CreateTransferInput
+}
+
+type ReverseTransferInput struct {
+	Reason string `json:"reason"`
+}
+
+type TransferOutput struct {
+	ID                   string            `json:"id"`
+	SourceAccountID      string            `json:"source_account_id"`
+	DestinationAccountID string            `json:"destination_account_id"`
+	Amount               float64           `json:"amount"`
+	Description          string            `json:"description"`
+	Metadata             map[string]string `json:"metadata"`
+	Status               string            `json:"status"`
+	ReversalReason       string            `json:"reversal_reason,omitempty"`
+	CreatedAt            time.Time         `json:"created_at"`
+	ReversedAt           *time.Time        `json:"reversed_at"`
+}
+
+func ToTransfer(input CreateTransferInput, sourceAccountID, idempotencyKey string) (*domain.Transfer, error) {
+	if err := input.Validate(idempotencyKey); err != nil {
+		return nil, err
+	}
+
+	metadata := input.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	return domain.NewTransfer(sourceAccountID, input.DestinationAccountID, input.Amount, input.Description, metadata, idempotencyKey)
+}
+
+func FromTransfer(transfer *domain.Transfer) TransferOutput {
+	return TransferOutput{
+		ID:                   transfer.ID,
+		SourceAccountID:      transfer.SourceAccountID,
+		DestinationAccountID: transfer.DestinationAccountID,
+		Amount:               transfer.Amount,
+		Description:          transfer.Description,
+		Metadata:             transfer.Metadata,
+		Status:               string(transfer.Status),
+		ReversalReason:       transfer.ReversalReason,
+		CreatedAt:            transfer.CreatedAt,
+		ReversedAt:           optionalTime(transfer.ReversedAt),
+	}
+}
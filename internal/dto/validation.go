@@ -0,0 +1,834 @@
+package dto
+
+import (
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/taxid"
+	"github.com/google/uuid"
+)
+
+var brazilianStates = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+type validator struct {
+	errors []*domain.FieldError
+}
+
+func (v *validator) add(field, code, message string) {
+	v.errors = append(v.errors, domain.NewFieldError(field, code, message))
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "required", "is required")
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) maxLength(field, value string, max int) {
+	if len([]rune(value)) > max {
+		v.add(field, "too_long", "must have at most "+strconv.Itoa(max)+" characters")
+	}
+}
+
+func (v *validator) email(field, value string) {
+	if !v.required(field, value) {
+		return
+	}
+
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value {
+		v.add(field, "invalid_email", "must be a valid email address")
+	}
+}
+
+func (v *validator) digits(field, value string, min, max int) bool {
+	if len(value) < min || len(value) > max || strings.IndexFunc(value, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+		if min == max {
+			v.add(field, "invalid_format", "must have exactly "+strconv.Itoa(min)+" digits")
+		} else {
+			v.add(field, "invalid_format", "must have between "+strconv.Itoa(min)+" and "+strconv.Itoa(max)+" digits")
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return domain.NewValidationError(v.errors...)
+}
+
+func onlyDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+
+		return -1
+	}, value)
+}
+
+func (input CreateAccountInput) Validate() error {
+	v := &validator{}
+	v.required("name", input.Name)
+	v.maxLength("name", input.Name, 255)
+	v.email("email", input.Email)
+
+	return v.err()
+}
+
+func (input UpdateAccountInput) Validate() error {
+	v := &validator{}
+	if input.Name == "" && input.Email == "" && input.InvoiceGraceDays == nil && input.ChargeDefaults == nil {
+		v.add("", "empty_update", "at least one of name, email, invoice_grace_days or charge_defaults is required")
+	}
+
+	v.maxLength("name", input.Name, 255)
+	if input.Email != "" {
+		v.email("email", input.Email)
+	}
+
+	if input.InvoiceGraceDays != nil && (*input.InvoiceGraceDays < 0 || *input.InvoiceGraceDays > domain.MaxInvoiceGraceDays) {
+		v.add("invoice_grace_days", "out_of_range", "must be between 0 and "+strconv.Itoa(domain.MaxInvoiceGraceDays))
+	}
+
+	if input.ChargeDefaults != nil {
+		input.ChargeDefaults.validate(v)
+	}
+
+	return v.err()
+}
+
+func (input UpdateFeeScheduleInput) Validate() error {
+	v := &validator{}
+	v.required("reason", input.Reason)
+
+	for method, rule := range input.Fees {
+		field := "fees." + method
+
+		switch domain.PaymentMethod(method) {
+		case domain.PaymentMethodBoleto, domain.PaymentMethodCard, domain.PaymentMethodPix:
+		default:
+			v.add(field, "invalid_payment_type", "must be one of boleto, card or pix")
+			continue
+		}
+
+		if rule.Percent < 0 || rule.Percent > 100 {
+			v.add(field+".percent", "out_of_range", "must be between 0 and 100")
+		}
+
+		if rule.Fixed < 0 {
+			v.add(field+".fixed", "out_of_range", "must not be negative")
+		}
+
+		if rule.InstallmentPercent < 0 || rule.InstallmentPercent > 100 {
+			v.add(field+".installment_percent", "out_of_range", "must be between 0 and 100")
+		} else if rule.InstallmentPercent > 0 && domain.PaymentMethod(method) != domain.PaymentMethodCard {
+			v.add(field+".installment_percent", "installments_not_allowed", "only applies to card payments")
+		}
+	}
+
+	return v.err()
+}
+
+func (input CreateRefundInput) Validate() error {
+	v := &validator{}
+
+	if input.Amount <= 0 {
+		v.add("amount", "invalid_amount", "must be greater than zero")
+	}
+
+	v.required("reason", input.Reason)
+	v.maxLength("reason", input.Reason, 255)
+
+	return v.err()
+}
+
+func (input CreateInvoiceInput) Validate() error {
+	v := &validator{}
+
+	if input.Amount <= 0 {
+		v.add("amount", "invalid_amount", "must be greater than zero")
+	}
+
+	v.required("description", input.Description)
+	v.maxLength("description", input.Description, 255)
+	v.maxLength("reference", input.Reference, 15)
+
+	if input.DueDate.IsZero() {
+		v.add("due_date", "required", "is required")
+	} else if input.DueDate.Before(today()) {
+		v.add("due_date", "in_past", "must not be in the past")
+	}
+
+	if input.PaymentMethodID != "" || (input.PaymentType == "" && input.CustomerID != "") {
+		input.validateSavedMethod(v)
+	} else {
+		switch domain.PaymentMethod(input.PaymentType) {
+		case domain.PaymentMethodBoleto, domain.PaymentMethodPix:
+		case domain.PaymentMethodCard:
+			validateCard(v, input.card())
+		case "":
+			v.add("payment_type", "required", "is required")
+		default:
+			v.add("payment_type", "invalid_payment_type", "must be one of boleto, card or pix")
+		}
+	}
+
+	if input.CustomerID == "" {
+		input.Payer.validate(v, "payer.", false)
+	}
+
+	if input.Installments < 0 || input.Installments > domain.MaxInstallments {
+		v.add("installments", "out_of_range", "must be between 1 and "+strconv.Itoa(domain.MaxInstallments))
+	} else if input.Installments > 1 && input.PaymentType != "" && domain.PaymentMethod(input.PaymentType) != domain.PaymentMethodCard {
+		v.add("installments", "installments_not_allowed", "are only available for card payments")
+	}
+
+	validateAdjustment(v, "fine", input.Fine)
+	validateAdjustment(v, "interest", input.Interest)
+	input.validateDiscounts(v)
+	input.validateSplits(v)
+
+	return v.err()
+}
+
+func (input CreateInvoiceInput) validateSplits(v *validator) {
+	if len(input.Splits) > domain.MaxSplits {
+		v.add("splits", "too_many", "must have at most "+strconv.Itoa(domain.MaxSplits)+" recipients")
+		return
+	}
+
+	seen := map[string]bool{}
+	var total float64
+	for i, rule := range input.Splits {
+		field := "splits[" + strconv.Itoa(i) + "]"
+
+		if v.required(field+".recipient_account_id", rule.RecipientAccountID) {
+			if _, err := uuid.Parse(rule.RecipientAccountID); err != nil {
+				v.add(field+".recipient_account_id", "invalid_format", "must be an account id")
+			} else if seen[rule.RecipientAccountID] {
+				v.add(field+".recipient_account_id", "duplicate", "must be unique across splits")
+			}
+
+			seen[rule.RecipientAccountID] = true
+		}
+
+		if rule.Value <= 0 {
+			v.add(field+".value", "out_of_range", "must be greater than zero")
+		}
+
+		switch domain.AdjustmentType(rule.Type) {
+		case domain.AdjustmentFixed:
+			total += rule.Value
+		case domain.AdjustmentPercent:
+			total += input.Amount * rule.Value / 100
+		case "":
+			v.add(field+".type", "required", "is required")
+		default:
+			v.add(field+".type", "invalid_type", "must be fixed or percent")
+		}
+	}
+
+	if input.Amount > 0 && total > input.Amount {
+		v.add("splits", "out_of_range", "must not add up to more than the invoice amount")
+	}
+}
+
+func validateAdjustment(v *validator, field string, adjustment *Adjustment) {
+	if adjustment == nil {
+		return
+	}
+
+	validateAdjustmentValue(v, field, adjustment.Type, adjustment.Value, 100)
+}
+
+// validateAdjustmentValue checks type and value of a fine, interest or
+// discount. Percentages are capped at limit; fixed values only need to be
+// positive here and are compared with the amount by the caller.
+func validateAdjustmentValue(v *validator, field, adjustmentType string, value, limit float64) {
+	switch domain.AdjustmentType(adjustmentType) {
+	case domain.AdjustmentFixed:
+	case domain.AdjustmentPercent:
+		if value > limit {
+			v.add(field+".value", "out_of_range", "must be at most "+strconv.FormatFloat(limit, 'f', -1, 64)+" percent")
+		}
+	case "":
+		v.add(field+".type", "required", "is required")
+	default:
+		v.add(field+".type", "invalid_adjustment_type", "must be one of fixed or percent")
+	}
+
+	if value <= 0 {
+		v.add(field+".value", "invalid_amount", "must be greater than zero")
+	}
+}
+
+func (input CreateInvoiceInput) validateDiscounts(v *validator) {
+	if len(input.Discounts) > domain.MaxDiscountTiers {
+		v.add("discounts", "too_many", "must have at most "+strconv.Itoa(domain.MaxDiscountTiers)+" tiers")
+		return
+	}
+
+	seen := map[string]bool{}
+	for i, tier := range input.Discounts {
+		field := "discounts[" + strconv.Itoa(i) + "]"
+		validateAdjustmentValue(v, field, tier.Type, tier.Value, 99.99)
+
+		if domain.AdjustmentType(tier.Type) == domain.AdjustmentFixed && input.Amount > 0 && tier.Value >= input.Amount {
+			v.add(field+".value", "out_of_range", "must be lower than the invoice amount")
+		}
+
+		switch {
+		case tier.Until.IsZero():
+			v.add(field+".until", "required", "is required")
+		case tier.Until.Before(today()):
+			v.add(field+".until", "in_past", "must not be in the past")
+		case !input.DueDate.IsZero() && tier.Until.After(input.DueDate):
+			v.add(field+".until", "after_due_date", "must not be after the due date")
+		case seen[tier.Until.Format(time.DateOnly)]:
+			v.add(field+".until", "duplicate", "must be unique across tiers")
+		}
+
+		seen[tier.Until.Format(time.DateOnly)] = true
+	}
+}
+
+func (input ChargeDefaults) validate(v *validator) {
+	validateAdjustment(v, "charge_defaults.fine", input.Fine)
+	validateAdjustment(v, "charge_defaults.interest", input.Interest)
+
+	if len(input.Discounts) > domain.MaxDiscountTiers {
+		v.add("charge_defaults.discounts", "too_many", "must have at most "+strconv.Itoa(domain.MaxDiscountTiers)+" tiers")
+		return
+	}
+
+	seen := map[int]bool{}
+	for i, rule := range input.Discounts {
+		field := "charge_defaults.discounts[" + strconv.Itoa(i) + "]"
+		validateAdjustmentValue(v, field, rule.Type, rule.Value, 99.99)
+
+		if rule.DaysBeforeDue < 0 || rule.DaysBeforeDue > domain.MaxInvoiceGraceDays {
+			v.add(field+".days_before_due", "out_of_range", "must be between 0 and "+strconv.Itoa(domain.MaxInvoiceGraceDays))
+		} else if seen[rule.DaysBeforeDue] {
+			v.add(field+".days_before_due", "duplicate", "must be unique across tiers")
+		}
+
+		seen[rule.DaysBeforeDue] = true
+	}
+}
+
+// validateSavedMethod covers invoices charged to a stored payment method,
+// either named explicitly or the customer's default. Raw card data is refused
+// so a saved card can't be silently swapped for a different one.
+func (input CreateInvoiceInput) validateSavedMethod(v *validator) {
+	if input.PaymentMethodID != "" && input.CustomerID == "" {
+		v.add("customer_id", "required", "is required when payment_method_id is set")
+	}
+
+	switch domain.PaymentMethod(input.PaymentType) {
+	case "", domain.PaymentMethodBoleto, domain.PaymentMethodPix, domain.PaymentMethodCard:
+	default:
+		v.add("payment_type", "invalid_payment_type", "must be one of boleto, card or pix")
+	}
+
+	if input.CardNumber != "" || input.CVV != "" {
+		v.add("card_number", "not_allowed", "must not be sent when charging a saved payment method")
+	}
+}
+
+func validateCard(v *validator, card domain.CreditCard) {
+	if v.required("card_number", card.Number) && v.digits("card_number", card.Number, 13, 19) && !luhn(card.Number) {
+		v.add("card_number", "invalid_card_number", "is not a valid card number")
+	}
+
+	if v.required("cvv", card.CVV) {
+		v.digits("cvv", card.CVV, 3, 4)
+	}
+
+	v.required("cardholder_name", card.CardholderName)
+
+	if card.ExpiryMonth < 1 || card.ExpiryMonth > 12 {
+		v.add("expiry_month", "invalid_month", "must be between 1 and 12")
+		return
+	}
+
+	now := time.Now()
+	if card.ExpiryYear < now.Year() || (card.ExpiryYear == now.Year() && card.ExpiryMonth < int(now.Month())) {
+		v.add("expiry_year", "card_expired", "card is expired")
+	}
+}
+
+func (input CreatePaymentMethodInput) Validate() error {
+	v := &validator{}
+
+	switch domain.PaymentMethod(input.Type) {
+	case domain.PaymentMethodBoleto, domain.PaymentMethodPix:
+	case domain.PaymentMethodCard:
+		validateCard(v, input.card())
+	case "":
+		v.add("type", "required", "is required")
+	default:
+		v.add("type", "invalid_payment_type", "must be one of boleto, card or pix")
+	}
+
+	return v.err()
+}
+
+// validate checks a payer payload; partial skips the required checks so an
+// update only has to carry the fields it changes.
+func (input CustomerInput) validate(v *validator, prefix string, partial bool) {
+	check := func(field, value string) bool {
+		if partial && value == "" {
+			return false
+		}
+
+		return v.required(prefix+field, value)
+	}
+
+	check("name", input.Name)
+	v.maxLength(prefix+"name", input.Name, 255)
+
+	if check("tax_id", input.TaxID) {
+		if _, err := taxid.Validate(input.TaxID); err != nil {
+			v.add(prefix+"tax_id", "invalid_tax_id", err.Error())
+		}
+	}
+
+	if input.Email != "" {
+		v.email(prefix+"email", input.Email)
+	}
+
+	if input.Phone != "" {
+		v.digits(prefix+"phone", onlyDigits(input.Phone), 10, 11)
+	}
+
+	check("address", input.Address)
+	check("number", input.Number)
+	check("district", input.District)
+	check("city", input.City)
+
+	if check("state", input.State) && !brazilianStates[strings.ToUpper(input.State)] {
+		v.add(prefix+"state", "invalid_state", "must be a valid UF")
+	}
+
+	if check("zip_code", input.ZipCode) {
+		v.digits(prefix+"zip_code", onlyDigits(input.ZipCode), 8, 8)
+	}
+}
+
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+func (input CreatePlanInput) Validate() error {
+	v := &validator{}
+	v.required("name", input.Name)
+	v.maxLength("name", input.Name, 100)
+
+	if input.Amount <= 0 {
+		v.add("amount", "invalid_amount", "must be greater than zero")
+	}
+
+	switch domain.PlanInterval(input.Interval) {
+	case domain.PlanIntervalDay, domain.PlanIntervalWeek, domain.PlanIntervalMonth, domain.PlanIntervalYear:
+	case "":
+		v.add("interval", "required", "is required")
+	default:
+		v.add("interval", "invalid_interval", "must be one of day, week, month or year")
+	}
+
+	if input.IntervalCount < 0 || input.IntervalCount > 12 {
+		v.add("interval_count", "out_of_range", "must be between 1 and 12")
+	}
+
+	if input.TrialDays < 0 || input.TrialDays > 365 {
+		v.add("trial_days", "out_of_range", "must be between 0 and 365")
+	}
+
+	return v.err()
+}
+
+func (input CreateSubscriptionInput) Validate() error {
+	v := &validator{}
+	v.required("customer_id", input.CustomerID)
+	v.required("plan_id", input.PlanID)
+
+	if input.TrialDays != nil && (*input.TrialDays < 0 || *input.TrialDays > 365) {
+		v.add("trial_days", "out_of_range", "must be between 0 and 365")
+	}
+
+	return v.err()
+}
+
+func (input ChangePlanInput) Validate() error {
+	v := &validator{}
+	v.required("plan_id", input.PlanID)
+
+	return v.err()
+}
+
+func (input CreatePayoutDestinationInput) Validate() error {
+	v := &validator{}
+
+	v.required("holder_name", input.HolderName)
+	v.maxLength("holder_name", input.HolderName, 100)
+
+	if v.required("holder_tax_id", input.HolderTaxID) && !taxid.IsValid(input.HolderTaxID) {
+		v.add("holder_tax_id", "invalid_tax_id", "must be a valid CPF or CNPJ")
+	}
+
+	switch domain.PayoutDestinationType(input.Type) {
+	case domain.PayoutDestinationPixKey:
+		input.validatePixKey(v)
+	case domain.PayoutDestinationBankAccount:
+		v.digits("bank_ispb", input.BankISPB, 8, 8)
+		v.digits("branch", input.Branch, 1, 5)
+		v.digits("account_number", onlyDigits(input.AccountNumber), 1, 20)
+
+		switch domain.BankAccountType(input.AccountType) {
+		case domain.BankAccountChecking, domain.BankAccountSavings, domain.BankAccountPayment:
+		case "":
+			v.add("account_type", "required", "is required")
+		default:
+			v.add("account_type", "invalid_account_type", "must be one of checking, savings or payment")
+		}
+	case "":
+		v.add("type", "required", "is required")
+	default:
+		v.add("type", "invalid_destination_type", "must be one of bank_account or pix_key")
+	}
+
+	return v.err()
+}
+
+func (input CreatePayoutDestinationInput) validatePixKey(v *validator) {
+	key := input.pixKey()
+
+	switch domain.PixKeyType(input.PixKeyType) {
+	case domain.PixKeyCPF, domain.PixKeyCNPJ:
+		kind, err := taxid.Validate(key)
+		if err != nil || (kind == taxid.KindCPF) != (domain.PixKeyType(input.PixKeyType) == domain.PixKeyCPF) {
+			v.add("pix_key", "invalid_pix_key", "must be a valid "+strings.ToUpper(input.PixKeyType))
+		}
+	case domain.PixKeyEmail:
+		v.email("pix_key", key)
+		v.maxLength("pix_key", key, 77)
+	case domain.PixKeyPhone:
+		if strings.HasPrefix(key, "+55") {
+			v.digits("pix_key", key[3:], 10, 11)
+		} else {
+			v.add("pix_key", "invalid_pix_key", "must be a phone number in the +55DDNNNNNNNNN format")
+		}
+	case domain.PixKeyRandom:
+		if _, err := uuid.Parse(key); err != nil || len(key) != 36 {
+			v.add("pix_key", "invalid_pix_key", "must be a random key (UUID)")
+		}
+	case "":
+		v.add("pix_key_type", "required", "is required")
+	default:
+		v.add("pix_key_type", "invalid_pix_key_type", "must be one of cpf, cnpj, email, phone or random")
+	}
+}
+
+func (input CreatePayoutInput) Validate() error {
+	v := &validator{}
+
+	v.required("destination_id", input.DestinationID)
+
+	if input.Amount <= 0 {
+		v.add("amount", "invalid_amount", "must be greater than zero")
+	}
+
+	v.maxLength("description", input.Description, 140)
+
+	return v.err()
+}
+
+func (input CreateTransferInput) Validate(idempotencyKey string) error {
+	v := &validator{}
+
+	if v.required("destination_account_id", input.DestinationAccountID) {
+		if _, err := uuid.Parse(input.DestinationAccountID); err != nil {
+			v.add("destination_account_id", "invalid_format", "must be an account id")
+		}
+	}
+
+	if input.Amount <= 0 {
+		v.add("amount", "invalid_amount", "must be greater than zero")
+	}
+
+	v.maxLength("description", input.Description, 255)
+	v.maxLength("idempotency_key", idempotencyKey, maxIdempotencyKeyLength)
+
+	if len(input.Metadata) > maxMetadataKeys {
+		v.add("metadata", "too_many", "must have at most "+strconv.Itoa(maxMetadataKeys)+" keys")
+	}
+
+	for key, value := range input.Metadata {
+		if strings.TrimSpace(key) == "" {
+			v.add("metadata", "invalid_key", "keys must not be empty")
+		}
+
+		v.maxLength("metadata."+key, key, maxMetadataKeyLength)
+		v.maxLength("metadata."+key, value, maxMetadataValueLength)
+	}
+
+	return v.err()
+}
+
+func (input AdminTransferInput) Validate() error {
+	v := &validator{}
+
+	if v.required("source_account_id", input.SourceAccountID) {
+		if _, err := uuid.Parse(input.SourceAccountID); err != nil {
+			v.add("source_account_id", "invalid_format", "must be an account id")
+		}
+	}
+
+	return v.err()
+}
+
+func (input ReverseTransferInput) Validate() error {
+	v := &validator{}
+	v.required("reason", input.Reason)
+	v.maxLength("reason", input.Reason, 255)
+
+	return v.err()
+}
+
+func (query BalanceTransactionQuery) Validate() error {
+	v := &validator{}
+
+	if query.Type != "" && !domain.IsValidBalanceTransactionType(domain.BalanceTransactionType(query.Type)) {
+		v.add("type", "invalid_transaction_type", "is not a balance transaction type")
+	}
+
+	if query.SourceID != "" {
+		if _, err := uuid.Parse(query.SourceID); err != nil {
+			v.add("source_id", "invalid_format", "must be an id")
+		}
+	}
+
+	var from, to time.Time
+	for _, param := range []struct {
+		field string
+		value string
+		date  *time.Time
+	}{
+		{"from", query.From, &from},
+		{"to", query.To, &to},
+	} {
+		if param.value == "" {
+			continue
+		}
+
+		date, err := time.Parse(dateLayout, param.value)
+		if err != nil {
+			v.add(param.field, "invalid_format", "must be a date in YYYY-MM-DD")
+			continue
+		}
+
+		*param.date = date
+	}
+
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		v.add("to", "out_of_range", "must be on or after from")
+	}
+
+	return v.err()
+}
+
+func (input RunReconciliationInput) Validate() error {
+	v := &validator{}
+
+	var from, to time.Time
+	for _, param := range []struct {
+		field string
+		value string
+		date  *time.Time
+	}{
+		{"from", input.From, &from},
+		{"to", input.To, &to},
+	} {
+		if !v.required(param.field, param.value) {
+			continue
+		}
+
+		date, err := time.ParseInLocation(dateLayout, param.value, time.Local)
+		if err != nil {
+			v.add(param.field, "invalid_format", "must be a date in YYYY-MM-DD")
+			continue
+		}
+
+		*param.date = date
+	}
+
+	if from.IsZero() || to.IsZero() {
+		return v.err()
+	}
+
+	if to.Before(from) {
+		v.add("to", "out_of_range", "must be on or after from")
+	} else if to.Sub(from) >= domain.MaxReconciliationDays*24*time.Hour {
+		v.add("to", "out_of_range", "period must be at most "+strconv.Itoa(domain.MaxReconciliationDays)+" days")
+	}
+
+	if to.After(today()) {
+		v.add("to", "in_future", "must not be in the future")
+	}
+
+	return v.err()
+}
+
+func (input GenerateRemittanceInput) Validate() error {
+	v := &validator{}
+
+	if input.Limit < 0 || input.Limit > domain.MaxRemittanceTitles {
+		v.add("limit", "out_of_range", "must be between 0 and "+strconv.Itoa(domain.MaxRemittanceTitles))
+	}
+
+	return v.err()
+}
+
+func (input SimulateDisputeInput) Validate() error {
+	v := &validator{}
+
+	if v.required("invoice_id", input.InvoiceID) {
+		if _, err := uuid.Parse(input.InvoiceID); err != nil {
+			v.add("invoice_id", "invalid_format", "must be an invoice id")
+		}
+	}
+
+	if v.required("reason", input.Reason) && !domain.IsValidDisputeReason(domain.DisputeReason(input.Reason)) {
+		v.add("reason", "invalid_dispute_reason", "is not a dispute reason code")
+	}
+
+	if input.Amount < 0 {
+		v.add("amount", "invalid_amount", "must not be negative")
+	}
+
+	return v.err()
+}
+
+func (input ResolveDisputeInput) Validate() error {
+	v := &validator{}
+
+	if v.required("outcome", input.Outcome) {
+		outcome := domain.DisputeStatus(input.Outcome)
+		if outcome != domain.DisputeStatusWon && outcome != domain.DisputeStatusLost {
+			v.add("outcome", "invalid_outcome", "must be won or lost")
+		}
+	}
+
+	v.maxLength("reason", input.Reason, 255)
+
+	return v.err()
+}
+
+func (input AddDisputeEvidenceInput) Validate() error {
+	v := &validator{}
+
+	if strings.TrimSpace(input.Text) == "" && len(input.Files) == 0 {
+		v.add("", "required", "send a text statement or at least one file")
+	}
+
+	v.maxLength("text", input.Text, maxEvidenceTextLength)
+
+	if len(input.Files) > MaxEvidenceFiles {
+		v.add("files", "too_many", "must have at most "+strconv.Itoa(MaxEvidenceFiles)+" files")
+	}
+
+	for i, file := range input.Files {
+		field := "files[" + strconv.Itoa(i) + "]"
+
+		v.maxLength(field+".name", file.Name, 255)
+
+		if !evidenceContentTypes[file.ContentType] {
+			v.add(field, "invalid_content_type", "must be a PDF, PNG or JPEG file")
+		}
+
+		if len(file.Content) == 0 || len(file.Content) > MaxEvidenceFileSize {
+			v.add(field, "invalid_size", "must have between 1 byte and "+strconv.Itoa(MaxEvidenceFileSize>>20)+" MB")
+		}
+	}
+
+	return v.err()
+}
+
+func (input UpdateReservePolicyInput) Validate() error {
+	v := &validator{}
+	v.required("reason", input.Reason)
+
+	if input.Percent != 0 || input.Days != 0 {
+		if input.Percent <= 0 || input.Percent > 100 {
+			v.add("percent", "out_of_range", "must be greater than 0 and up to 100")
+		}
+
+		if input.Days <= 0 || input.Days > domain.MaxReserveDays {
+			v.add("days", "out_of_range", "must be between 1 and "+strconv.Itoa(domain.MaxReserveDays))
+		}
+	}
+
+	return v.err()
+}
+
+func (input CreateBalanceHoldInput) Validate() error {
+	v := &validator{}
+	v.required("reason", input.Reason)
+	v.maxLength("reason", input.Reason, 255)
+
+	if input.Amount <= 0 {
+		v.add("amount", "invalid_amount", "must be greater than zero")
+	}
+
+	if input.ReleaseAt != nil && !input.ReleaseAt.After(time.Now()) {
+		v.add("release_at", "in_past", "must be in the future")
+	}
+
+	return v.err()
+}
+
+func (input ReleaseBalanceHoldInput) Validate() error {
+	v := &validator{}
+	v.required("reason", input.Reason)
+
+	return v.err()
+}
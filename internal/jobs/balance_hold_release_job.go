@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type BalanceHoldReleaseJob struct {
+	balanceHoldService *service.BalanceHoldService
+}
+
+func NewBalanceHoldReleaseJob(balanceHoldService *service.BalanceHoldService) *BalanceHoldReleaseJob {
+	return &BalanceHoldReleaseJob{
+		balanceHoldService: balanceHoldService,
+	}
+}
+
+func (job *BalanceHoldReleaseJob) Name() string {
+	return "balance-hold-release"
+}
+
+func (job *BalanceHoldReleaseJob) Run(ctx context.Context) error {
+	_, err := job.balanceHoldService.ReleaseDue(ctx)
+
+	return err
+}
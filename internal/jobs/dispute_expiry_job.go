@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type DisputeExpiryJob struct {
+	disputeService *service.DisputeService
+}
+
+func NewDisputeExpiryJob(disputeService *service.DisputeService) *DisputeExpiryJob {
+	return &DisputeExpiryJob{
+		disputeService: disputeService,
+	}
+}
+
+func (job *DisputeExpiryJob) Name() string {
+	return "dispute-expiry"
+}
+
+func (job *DisputeExpiryJob) Run(ctx context.Context) error {
+	_, err := job.disputeService.ExpireOverdue(ctx)
+
+	return err
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type DunningJob struct {
+	subscriptionService *service.SubscriptionService
+}
+
+func NewDunningJob(subscriptionService *service.SubscriptionService) *DunningJob {
+	return &DunningJob{
+		subscriptionService: subscriptionService,
+	}
+}
+
+func (job *DunningJob) Name() string {
+	return "dunning"
+}
+
+func (job *DunningJob) Run(ctx context.Context) error {
+	_, err := job.subscriptionService.RetryDue(ctx)
+
+	return err
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type InvoiceExpiryJob struct {
+	invoiceService *service.InvoiceService
+}
+
+func NewInvoiceExpiryJob(invoiceService *service.InvoiceService) *InvoiceExpiryJob {
+	return &InvoiceExpiryJob{
+		invoiceService: invoiceService,
+	}
+}
+
+func (job *InvoiceExpiryJob) Name() string {
+	return "invoice-expiry"
+}
+
+func (job *InvoiceExpiryJob) Run(ctx context.Context) error {
+	_, err := job.invoiceService.ExpireOverdue(ctx)
+
+	return err
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type PayerReencryptionJob struct {
+	payerService *service.PayerService
+}
+
+func NewPayerReencryptionJob(payerService *service.PayerService) *PayerReencryptionJob {
+	return &PayerReencryptionJob{
+		payerService: payerService,
+	}
+}
+
+func (job *PayerReencryptionJob) Name() string {
+	return "payer-reencryption"
+}
+
+func (job *PayerReencryptionJob) Run(ctx context.Context) error {
+	_, err := job.payerService.Reencrypt(ctx)
+
+	return err
+}
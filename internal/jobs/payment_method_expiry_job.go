@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type PaymentMethodExpiryJob struct {
+	paymentMethodService *service.PaymentMethodService
+}
+
+func NewPaymentMethodExpiryJob(paymentMethodService *service.PaymentMethodService) *PaymentMethodExpiryJob {
+	return &PaymentMethodExpiryJob{
+		paymentMethodService: paymentMethodService,
+	}
+}
+
+func (job *PaymentMethodExpiryJob) Name() string {
+	return "payment-method-expiry"
+}
+
+func (job *PaymentMethodExpiryJob) Run(ctx context.Context) error {
+	_, err := job.paymentMethodService.ExpireCards(ctx)
+
+	return err
+}
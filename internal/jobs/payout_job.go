@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type PayoutJob struct {
+	payoutService *service.PayoutService
+}
+
+func NewPayoutJob(payoutService *service.PayoutService) *PayoutJob {
+	return &PayoutJob{
+		payoutService: payoutService,
+	}
+}
+
+func (job *PayoutJob) Name() string {
+	return "payouts"
+}
+
+func (job *PayoutJob) Run(ctx context.Context) error {
+	_, err := job.payoutService.ProcessPending(ctx)
+
+	return err
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type ReconciliationJob struct {
+	reconciliationService *service.ReconciliationService
+}
+
+func NewReconciliationJob(reconciliationService *service.ReconciliationService) *ReconciliationJob {
+	return &ReconciliationJob{
+		reconciliationService: reconciliationService,
+	}
+}
+
+func (job *ReconciliationJob) Name() string {
+	return "reconciliation"
+}
+
+func (job *ReconciliationJob) Run(ctx context.Context) error {
+	_, err := job.reconciliationService.RunPreviousDay(ctx)
+
+	return err
+}
@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	job      Job
+	interval time.Duration
+}
+
+type Scheduler struct {
+	jobs []scheduledJob
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+func (s *Scheduler) Every(interval time.Duration, job Job) {
+	s.jobs = append(s.jobs, scheduledJob{job: job, interval: interval})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, scheduled := range s.jobs {
+		s.wg.Add(1)
+
+		go func(scheduled scheduledJob) {
+			defer s.wg.Done()
+
+			log.Printf("[Scheduler] Job %s scheduled every %s", scheduled.job.Name(), scheduled.interval)
+
+			ticker := time.NewTicker(scheduled.interval)
+			defer ticker.Stop()
+
+			for {
+				s.run(ctx, scheduled.job)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(scheduled)
+	}
+}
+
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Scheduler] Job %s panicked: %v", job.Name(), r)
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		log.Printf("[Scheduler] Job %s failed: %v", job.Name(), err)
+	}
+}
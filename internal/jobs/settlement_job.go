@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type SettlementJob struct {
+	settlementService *service.SettlementService
+}
+
+func NewSettlementJob(settlementService *service.SettlementService) *SettlementJob {
+	return &SettlementJob{
+		settlementService: settlementService,
+	}
+}
+
+func (job *SettlementJob) Name() string {
+	return "settlement"
+}
+
+func (job *SettlementJob) Run(ctx context.Context) error {
+	_, err := job.settlementService.ReleaseDue(ctx)
+
+	return err
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+)
+
+type SubscriptionBillingJob struct {
+	subscriptionService *service.SubscriptionService
+}
+
+func NewSubscriptionBillingJob(subscriptionService *service.SubscriptionService) *SubscriptionBillingJob {
+	return &SubscriptionBillingJob{
+		subscriptionService: subscriptionService,
+	}
+}
+
+func (job *SubscriptionBillingJob) Name() string {
+	return "subscription-billing"
+}
+
+func (job *SubscriptionBillingJob) Run(ctx context.Context) error {
+	_, err := job.subscriptionService.BillDue(ctx)
+
+	return err
+}
@@ -1,11 +1,15 @@
 package account_repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"log"
 	"time"
 
 	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	balance_transaction_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_transaction"
+	"github.com/lib/pq"
 )
 
 type AccountRepository struct {
@@ -20,10 +24,20 @@ func NewAccountRepository(db *sql.DB) *AccountRepository {
 	return accountRepository
 }
 
-func (repository *AccountRepository) Save(account *domain.Account) error {
-	statement, err := repository.db.Prepare(`
-		INSERT INTO accounts (id, name, email, api_key, balance, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6 ,$7)
+func (repository *AccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	chargeDefaults, err := json.Marshal(account.ChargeDefaults)
+	if err != nil {
+		return err
+	}
+
+	feeSchedule, err := json.Marshal(account.FeeSchedule)
+	if err != nil {
+		return err
+	}
+
+	statement, err := repository.db.PrepareContext(ctx, `
+		INSERT INTO accounts (id, name, email, api_key, balance, invoice_grace_days, charge_defaults, fee_schedule, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`)
 
 	if err != nil {
@@ -32,12 +46,15 @@ func (repository *AccountRepository) Save(account *domain.Account) error {
 
 	defer statement.Close()
 
-	_, err = statement.Exec(
+	_, err = statement.ExecContext(ctx,
 		account.ID,
 		account.Name,
 		account.Email,
 		account.APIKey,
 		account.Balance,
+		account.InvoiceGraceDays,
+		string(chargeDefaults),
+		string(feeSchedule),
 		account.CreatedAt,
 		account.UpdatedAt,
 	)
@@ -49,65 +66,37 @@ func (repository *AccountRepository) Save(account *domain.Account) error {
 	return nil
 }
 
-func (repository *AccountRepository) FindByAPIKey(apiKey string) (*domain.Account, error) {
-	log.Printf("Finding account by API key: %s", apiKey)
-
-	var account domain.Account
-	var createdAt, updatedAt time.Time
-
-	err := repository.db.QueryRow(`
-		SELECT id, name, email, api_key, balance, created_at, updated_at
-		FROM accounts
-		WHERE api_key = $1
-			AND deleted_at IS NULL
-	`, apiKey).Scan(
-		&account.ID,
-		&account.Name,
-		&account.Email,
-		&account.APIKey,
-		&account.Balance,
-		&createdAt,
-		&updatedAt,
-	)
+func (repository *AccountRepository) FindByAPIKey(ctx context.Context, apiKey string) (*domain.Account, error) {
+	account, err := scanAccount(repository.db.QueryRowContext(ctx, `
+		SELECT a.id, a.name, a.email, a.api_key, a.balance, a.pending_balance, a.reserved_balance, a.held_balance, a.invoice_grace_days, a.charge_defaults, a.fee_schedule, a.reserve_policy, a.created_at, a.updated_at, a.deleted_at, a.frozen_at
+		FROM accounts a
+		JOIN api_keys k ON k.account_id = a.id
+		WHERE k.key = $1
+			AND k.revoked_at IS NULL
+			AND a.deleted_at IS NULL
+	`, apiKey))
 
 	if err == sql.ErrNoRows {
-		log.Printf("Account not found with API key: %s", apiKey)
 		return nil, domain.ErrAccountNotFound
 	}
 
 	if err != nil {
-		log.Printf("Error finding account with API key %s: %v", apiKey, err)
+		log.Printf("Error finding account by API key: %v", err)
 		return nil, err
 	}
 
-	account.CreatedAt = createdAt
-	account.UpdatedAt = updatedAt
-
-	log.Printf("Account found: ID=%s, Name=%s, Email=%s, APIKey=%s, Balance=%.2f",
-		account.ID, account.Name, account.Email, account.APIKey, account.Balance)
-	return &account, nil
+	return account, nil
 }
 
-func (repository *AccountRepository) FindByID(id string) (*domain.Account, error) {
+func (repository *AccountRepository) FindByID(ctx context.Context, id string) (*domain.Account, error) {
 	log.Printf("Finding account by ID: %s", id)
 
-	var account domain.Account
-	var createdAt, updatedAt time.Time
-
-	err := repository.db.QueryRow(`
-		SELECT id, name, email, api_key, balance, created_at, updated_at
+	account, err := scanAccount(repository.db.QueryRowContext(ctx, `
+		SELECT id, name, email, api_key, balance, pending_balance, reserved_balance, held_balance, invoice_grace_days, charge_defaults, fee_schedule, reserve_policy, created_at, updated_at, deleted_at, frozen_at
 		FROM accounts
 		WHERE id = $1
 			AND deleted_at IS NULL
-	`, id).Scan(
-		&account.ID,
-		&account.Name,
-		&account.Email,
-		&account.APIKey,
-		&account.Balance,
-		&createdAt,
-		&updatedAt,
-	)
+	`, id))
 
 	if err == sql.ErrNoRows {
 		log.Printf("Account not found with ID: %s", id)
@@ -119,19 +108,18 @@ func (repository *AccountRepository) FindByID(id string) (*domain.Account, error
 		return nil, err
 	}
 
-	account.CreatedAt = createdAt
-	account.UpdatedAt = updatedAt
-
-	log.Printf("Account found: ID=%s, Name=%s, Email=%s, APIKey=%s, Balance=%.2f",
-		account.ID, account.Name, account.Email, account.APIKey, account.Balance)
-	return &account, nil
+	return account, nil
 }
 
-func (repository *AccountRepository) UpdateBalance(account *domain.Account) error {
-	log.Printf("Updating balance for account ID %s to %.2f", account.ID, account.Balance)
-
-	tx, err := repository.db.Begin()
+// ApplyBalanceChange moves money between the available, pending and
+// reserved balances in a single statement. Any bucket the change takes money
+// out of must cover it, so concurrent debits can never overdraw an account.
+// When the available balance moves, transaction is recorded with it.
+func (repository *AccountRepository) ApplyBalanceChange(ctx context.Context, account *domain.Account, change domain.BalanceChange, transaction *domain.BalanceTransaction) error {
+	log.Printf("Applying balance change to account %s: available %.2f, pending %.2f, reserved %.2f",
+		account.ID, change.Available, change.Pending, change.Reserved)
 
+	tx, err := repository.db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Printf("Error starting transaction for account %s: %v", account.ID, err)
 		return err
@@ -139,41 +127,215 @@ func (repository *AccountRepository) UpdateBalance(account *domain.Account) erro
 
 	defer tx.Rollback()
 
-	var currentBalance float64
-
-	err = tx.QueryRow(`
-		SELECT balance
-		FROM accounts
-		WHERE id = $1
-		FOR UPDATE
-	`, account.ID).Scan(&currentBalance)
+	err = tx.QueryRowContext(ctx, `
+		UPDATE accounts
+		SET balance = balance + $1, pending_balance = pending_balance + $2, reserved_balance = reserved_balance + $3,
+			updated_at = $4
+		WHERE id = $5
+			AND ($1 >= 0 OR balance + $1 >= 0)
+			AND ($2 >= 0 OR pending_balance + $2 >= 0)
+			AND ($3 >= 0 OR reserved_balance + $3 >= 0)
+		RETURNING balance, pending_balance, reserved_balance, updated_at
+	`, change.Available, change.Pending, change.Reserved, time.Now(), account.ID).Scan(
+		&account.Balance,
+		&account.PendingBalance,
+		&account.ReservedBalance,
+		&account.UpdatedAt,
+	)
 
 	if err == sql.ErrNoRows {
-		log.Printf("Account not found for balance update: %s", account.ID)
-		return domain.ErrAccountNotFound
+		return domain.ErrInsufficientBalance
 	}
 
 	if err != nil {
-		log.Printf("Error querying current balance for account %s: %v", account.ID, err)
+		log.Printf("Error applying balance change to account %s: %v", account.ID, err)
 		return err
 	}
 
-	_, err = tx.Exec(`
+	if transaction != nil && change.Available != 0 {
+		transaction.AccountID = account.ID
+		transaction.Amount = change.Available
+		transaction.BalanceAfter = account.Balance
+
+		if err := balance_transaction_repository.Record(ctx, tx, transaction); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repository *AccountRepository) Update(ctx context.Context, account *domain.Account) error {
+	log.Printf("Updating account %s", account.ID)
+
+	chargeDefaults, err := json.Marshal(account.ChargeDefaults)
+	if err != nil {
+		return err
+	}
+
+	feeSchedule, err := json.Marshal(account.FeeSchedule)
+	if err != nil {
+		return err
+	}
+
+	reservePolicy, err := json.Marshal(account.ReservePolicy)
+	if err != nil {
+		return err
+	}
+
+	result, err := repository.db.ExecContext(ctx, `
 		UPDATE accounts
-		SET balance = $1, updated_at = $2
-		WHERE id = $3
-	`, account.Balance, time.Now(), account.ID)
+		SET name = $1, email = $2, api_key = $3, deleted_at = $4, frozen_at = $5, updated_at = $6, invoice_grace_days = $7,
+			charge_defaults = $8, fee_schedule = $9, reserve_policy = $10
+		WHERE id = $11
+	`,
+		account.Name,
+		account.Email,
+		account.APIKey,
+		nullTime(account.DeletedAt),
+		nullTime(account.FrozenAt),
+		account.UpdatedAt,
+		account.InvoiceGraceDays,
+		string(chargeDefaults),
+		string(feeSchedule),
+		string(reservePolicy),
+		account.ID,
+	)
+
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		log.Printf("Duplicated email updating account %s", account.ID)
+		return domain.ErrDuplicatedEmail
+	}
 
 	if err != nil {
-		log.Printf("Error updating balance for account %s: %v", account.ID, err)
+		log.Printf("Error updating account %s: %v", account.ID, err)
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
-		log.Printf("Error committing transaction for account %s: %v", account.ID, err)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
 		return err
 	}
 
-	log.Printf("Balance updated successfully for account %s", account.ID)
+	if rowsAffected == 0 {
+		return domain.ErrAccountNotFound
+	}
+
+	log.Printf("Account updated successfully: %s", account.ID)
 	return nil
 }
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func scanAccount(row interface{ Scan(dest ...any) error }) (*domain.Account, error) {
+	var account domain.Account
+	var deletedAt, frozenAt sql.NullTime
+	var chargeDefaults, feeSchedule, reservePolicy []byte
+
+	err := row.Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.APIKey,
+		&account.Balance,
+		&account.PendingBalance,
+		&account.ReservedBalance,
+		&account.HeldBalance,
+		&account.InvoiceGraceDays,
+		&chargeDefaults,
+		&feeSchedule,
+		&reservePolicy,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+		&deletedAt,
+		&frozenAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deletedAt.Valid {
+		account.DeletedAt = deletedAt.Time
+	}
+
+	if frozenAt.Valid {
+		account.FrozenAt = frozenAt.Time
+	}
+
+	if err := json.Unmarshal(chargeDefaults, &account.ChargeDefaults); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(feeSchedule, &account.FeeSchedule); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(reservePolicy, &account.ReservePolicy); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (repository *AccountRepository) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Account, error) {
+	account, err := scanAccount(repository.db.QueryRowContext(ctx, `
+		SELECT id, name, email, api_key, balance, pending_balance, reserved_balance, held_balance, invoice_grace_days, charge_defaults, fee_schedule, reserve_policy, created_at, updated_at, deleted_at, frozen_at
+		FROM accounts
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		log.Printf("Account not found with ID: %s", id)
+		return nil, domain.ErrAccountNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding account with ID %s: %v", id, err)
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (repository *AccountRepository) Search(ctx context.Context, filter domain.AccountFilter) ([]*domain.Account, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, `
+		SELECT id, name, email, api_key, balance, pending_balance, reserved_balance, held_balance, invoice_grace_days, charge_defaults, fee_schedule, reserve_policy, created_at, updated_at, deleted_at, frozen_at
+		FROM accounts
+		WHERE ($1 OR deleted_at IS NULL)
+			AND ($2 = '' OR id::text = $2 OR name ILIKE '%' || $2 || '%' OR email ILIKE '%' || $2 || '%')
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, filter.IncludeDeleted, filter.Query, limit)
+
+	if err != nil {
+		log.Printf("Error listing accounts: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var accounts []*domain.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			log.Printf("Error scanning account: %v", err)
+			return nil, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
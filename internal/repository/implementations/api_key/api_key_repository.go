@@ -0,0 +1,221 @@
+package api_key_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/lib/pq"
+)
+
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+func toScopes(values []string) []domain.Scope {
+	scopes := make([]domain.Scope, len(values))
+	for i, value := range values {
+		scopes[i] = domain.Scope(value)
+	}
+
+	return scopes
+}
+
+func fromScopes(scopes []domain.Scope) []string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+
+	return values
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row scanner, extra ...any) (*domain.APIKey, error) {
+	var apiKey domain.APIKey
+	var scopes []string
+	var revokedAt sql.NullTime
+
+	dest := []any{
+		&apiKey.ID,
+		&apiKey.AccountID,
+		&apiKey.Key,
+		&apiKey.Type,
+		pq.Array(&scopes),
+		&apiKey.CreatedAt,
+		&apiKey.UpdatedAt,
+		&revokedAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.Scopes = toScopes(scopes)
+	if revokedAt.Valid {
+		apiKey.RevokedAt = revokedAt.Time
+	}
+
+	return &apiKey, nil
+}
+
+func (repository *APIKeyRepository) Save(ctx context.Context, apiKey *domain.APIKey) error {
+	log.Printf("Saving api key %s for account %s", apiKey.ID, apiKey.AccountID)
+
+	_, err := repository.db.ExecContext(ctx, `
+		INSERT INTO api_keys (id, account_id, key, type, scopes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		apiKey.ID,
+		apiKey.AccountID,
+		apiKey.Key,
+		apiKey.Type,
+		pq.Array(fromScopes(apiKey.Scopes)),
+		apiKey.CreatedAt,
+		apiKey.UpdatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving api key %s: %v", apiKey.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (repository *APIKeyRepository) FindByKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	var accountFrozen bool
+
+	apiKey, err := scanAPIKey(repository.db.QueryRowContext(ctx, `
+		SELECT k.id, k.account_id, k.key, k.type, k.scopes, k.created_at, k.updated_at, k.revoked_at, a.frozen_at IS NOT NULL
+		FROM api_keys k
+		JOIN accounts a ON a.id = k.account_id
+		WHERE k.key = $1
+			AND k.revoked_at IS NULL
+			AND a.deleted_at IS NULL
+	`, key), &accountFrozen)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding api key: %v", err)
+		return nil, err
+	}
+
+	apiKey.AccountFrozen = accountFrozen
+
+	return apiKey, nil
+}
+
+func (repository *APIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	apiKey, err := scanAPIKey(repository.db.QueryRowContext(ctx, `
+		SELECT id, account_id, key, type, scopes, created_at, updated_at, revoked_at
+		FROM api_keys
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		log.Printf("Api key not found: %s", id)
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding api key %s: %v", id, err)
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (repository *APIKeyRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.APIKey, error) {
+	rows, err := repository.db.QueryContext(ctx, `
+		SELECT id, account_id, key, type, scopes, created_at, updated_at, revoked_at
+		FROM api_keys
+		WHERE account_id = $1
+		ORDER BY created_at
+	`, accountID)
+
+	if err != nil {
+		log.Printf("Error listing api keys for account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var apiKeys []*domain.APIKey
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows)
+		if err != nil {
+			log.Printf("Error scanning api key for account %s: %v", accountID, err)
+			return nil, err
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+func (repository *APIKeyRepository) Revoke(ctx context.Context, apiKey *domain.APIKey) error {
+	log.Printf("Revoking api key %s", apiKey.ID)
+
+	result, err := repository.db.ExecContext(ctx, `
+		UPDATE api_keys
+		SET revoked_at = $1, updated_at = $2
+		WHERE id = $3
+			AND revoked_at IS NULL
+	`, apiKey.RevokedAt, apiKey.UpdatedAt, apiKey.ID)
+
+	if err != nil {
+		log.Printf("Error revoking api key %s: %v", apiKey.ID, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (repository *APIKeyRepository) RevokeByAccountID(ctx context.Context, accountID string) error {
+	log.Printf("Revoking all api keys for account %s", accountID)
+
+	_, err := repository.db.ExecContext(ctx, `
+		UPDATE api_keys
+		SET revoked_at = $1, updated_at = $1
+		WHERE account_id = $2
+			AND revoked_at IS NULL
+	`, time.Now(), accountID)
+
+	if err != nil {
+		log.Printf("Error revoking api keys for account %s: %v", accountID, err)
+		return err
+	}
+
+	return nil
+}
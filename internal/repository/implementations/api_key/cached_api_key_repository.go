@@ -0,0 +1,98 @@
+package api_key_repository
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+type cachedAPIKey struct {
+	apiKey    domain.APIKey
+	expiresAt time.Time
+}
+
+// CachedAPIKeyRepository keeps authenticated keys in memory for a short TTL so
+// a single request doesn't hit the database on every layer. Revocations made
+// through this instance are evicted immediately; other instances catch up
+// when the TTL expires.
+type CachedAPIKeyRepository struct {
+	repository repository.APIKeyRepository
+	ttl        time.Duration
+	mu         sync.RWMutex
+	entries    map[string]cachedAPIKey
+}
+
+func NewCachedAPIKeyRepository(repository repository.APIKeyRepository, ttl time.Duration) *CachedAPIKeyRepository {
+	return &CachedAPIKeyRepository{
+		repository: repository,
+		ttl:        ttl,
+		entries:    make(map[string]cachedAPIKey),
+	}
+}
+
+func (r *CachedAPIKeyRepository) Save(ctx context.Context, apiKey *domain.APIKey) error {
+	return r.repository.Save(ctx, apiKey)
+}
+
+func (r *CachedAPIKeyRepository) FindByKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	entry, ok := r.entries[key]
+	r.mu.RUnlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		apiKey := entry.apiKey
+		return &apiKey, nil
+	}
+
+	apiKey, err := r.repository.FindByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.entries[key] = cachedAPIKey{apiKey: *apiKey, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return apiKey, nil
+}
+
+func (r *CachedAPIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	return r.repository.FindByID(ctx, id)
+}
+
+func (r *CachedAPIKeyRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.APIKey, error) {
+	return r.repository.FindByAccountID(ctx, accountID)
+}
+
+func (r *CachedAPIKeyRepository) Revoke(ctx context.Context, apiKey *domain.APIKey) error {
+	err := r.repository.Revoke(ctx, apiKey)
+	r.Invalidate(apiKey.Key)
+
+	return err
+}
+
+func (r *CachedAPIKeyRepository) RevokeByAccountID(ctx context.Context, accountID string) error {
+	err := r.repository.RevokeByAccountID(ctx, accountID)
+
+	r.mu.Lock()
+	for key, entry := range r.entries {
+		if entry.apiKey.AccountID == accountID {
+			delete(r.entries, key)
+		}
+	}
+	r.mu.Unlock()
+
+	return err
+}
+
+func (r *CachedAPIKeyRepository) Invalidate(key string) {
+	r.mu.Lock()
+	delete(r.entries, key)
+	r.mu.Unlock()
+
+	log.Printf("[CachedAPIKeyRepository] Evicted api key from cache")
+}
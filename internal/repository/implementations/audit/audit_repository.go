@@ -0,0 +1,206 @@
+package audit_repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+const auditChainLock = 7305003
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func marshalSnapshot(snapshot map[string]any) (sql.NullString, error) {
+	if snapshot == nil {
+		return sql.NullString{}, nil
+	}
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+func unmarshalSnapshot(value sql.NullString) (map[string]any, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+
+	var snapshot map[string]any
+	if err := json.Unmarshal([]byte(value.String), &snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Save serializes writers with an advisory lock so every entry links to the
+// hash of the one inserted right before it.
+func (repository *AuditRepository) Save(ctx context.Context, entry *domain.AuditEntry) error {
+	before, err := marshalSnapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := marshalSnapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLock); err != nil {
+		return err
+	}
+
+	var prevHash sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT hash
+		FROM audit_log
+		WHERE hash IS NOT NULL
+		ORDER BY seq DESC
+		LIMIT 1
+	`).Scan(&prevHash)
+
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	entry.PrevHash = prevHash.String
+	entry.Hash = entry.ComputeHash()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO audit_log (id, actor_type, actor_id, action, target_type, target_id, before, after, ip, request_id, prev_hash, hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING seq
+	`,
+		entry.ID,
+		entry.ActorType,
+		entry.ActorID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		before,
+		after,
+		entry.IP,
+		entry.RequestID,
+		entry.PrevHash,
+		entry.Hash,
+		entry.CreatedAt,
+	).Scan(&entry.Seq)
+
+	if err != nil {
+		log.Printf("Error saving audit entry %s for %s %s: %v", entry.Action, entry.TargetType, entry.TargetID, err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const selectAuditEntry = `
+	SELECT id, seq, actor_type, actor_id, action, target_type, target_id, before, after,
+		COALESCE(ip, ''), COALESCE(request_id, ''), COALESCE(prev_hash, ''), COALESCE(hash, ''), created_at
+	FROM audit_log
+`
+
+func (repository *AuditRepository) query(ctx context.Context, query string, args ...any) ([]*domain.AuditEntry, error) {
+	rows, err := repository.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var before, after sql.NullString
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Seq,
+			&entry.ActorType,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetID,
+			&before,
+			&after,
+			&entry.IP,
+			&entry.RequestID,
+			&entry.PrevHash,
+			&entry.Hash,
+			&entry.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Before, err = unmarshalSnapshot(before); err != nil {
+			return nil, err
+		}
+
+		if entry.After, err = unmarshalSnapshot(after); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (repository *AuditRepository) Search(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	entries, err := repository.query(ctx, selectAuditEntry+`
+		WHERE ($1 = '' OR target_type = $1)
+			AND ($2 = '' OR target_id = $2)
+			AND ($3 = '' OR actor_type = $3)
+			AND ($4 = '' OR actor_id = $4)
+		ORDER BY seq DESC
+		LIMIT $5
+	`, filter.TargetType, filter.TargetID, string(filter.ActorType), filter.ActorID, limit)
+
+	if err != nil {
+		log.Printf("Error searching audit log: %v", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (repository *AuditRepository) ListAfter(ctx context.Context, seq int64, limit int) ([]*domain.AuditEntry, error) {
+	return repository.query(ctx, selectAuditEntry+`
+		WHERE seq > $1
+			AND hash IS NOT NULL
+		ORDER BY seq
+		LIMIT $2
+	`, seq, limit)
+}
@@ -0,0 +1,263 @@
+package balance_hold_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	balance_transaction_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_transaction"
+)
+
+type BalanceHoldRepository struct {
+	db *sql.DB
+}
+
+func NewBalanceHoldRepository(db *sql.DB) *BalanceHoldRepository {
+	return &BalanceHoldRepository{
+		db: db,
+	}
+}
+
+// Insert writes a hold row within the caller's transaction. Moving the money
+// into the held balance is up to the caller.
+func Insert(ctx context.Context, tx *sql.Tx, hold *domain.BalanceHold) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO balance_holds (id, account_id, kind, amount, reason, invoice_id, status, release_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		hold.ID,
+		hold.AccountID,
+		hold.Kind,
+		hold.Amount,
+		hold.Reason,
+		nullString(hold.InvoiceID),
+		hold.Status,
+		nullTime(hold.ReleaseAt),
+		hold.CreatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving balance hold %s: %v", hold.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+// Place records a manual hold and moves its amount from the account's
+// available to its held balance in one transaction. The available balance
+// must cover it.
+func (repository *BalanceHoldRepository) Place(ctx context.Context, hold *domain.BalanceHold) error {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for balance hold %s: %v", hold.ID, err)
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var balance float64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE accounts
+		SET balance = balance - $1, held_balance = held_balance + $1, updated_at = $2
+		WHERE id = $3 AND balance >= $1
+		RETURNING balance
+	`, hold.Amount, time.Now(), hold.AccountID).Scan(&balance)
+
+	if err == sql.ErrNoRows {
+		return domain.ErrInsufficientBalance
+	}
+
+	if err != nil {
+		log.Printf("Error holding %.2f for account %s: %v", hold.Amount, hold.AccountID, err)
+		return err
+	}
+
+	if err := Insert(ctx, tx, hold); err != nil {
+		return err
+	}
+
+	transaction := domain.NewBalanceTransaction(domain.BalanceTransactionHold, "balance_hold", hold.ID, hold.Reason)
+	transaction.AccountID = hold.AccountID
+	transaction.Amount = -hold.Amount
+	transaction.BalanceAfter = balance
+
+	if err := balance_transaction_repository.Record(ctx, tx, transaction); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Release moves an active hold back to the available balance. It reports
+// false when the hold was already released.
+func (repository *BalanceHoldRepository) Release(ctx context.Context, hold *domain.BalanceHold) (bool, error) {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for balance hold %s: %v", hold.ID, err)
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE balance_holds
+		SET status = $1, released_at = $2
+		WHERE id = $3 AND status = $4
+	`, hold.Status, hold.ReleasedAt, hold.ID, domain.BalanceHoldStatusActive)
+
+	if err != nil {
+		log.Printf("Error releasing balance hold %s: %v", hold.ID, err)
+		return false, err
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	var balance float64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE accounts
+		SET balance = balance + $1, held_balance = held_balance - $1, updated_at = $2
+		WHERE id = $3
+		RETURNING balance
+	`, hold.Amount, time.Now(), hold.AccountID).Scan(&balance)
+
+	if err == sql.ErrNoRows {
+		return false, domain.ErrAccountNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error releasing held balance for account %s: %v", hold.AccountID, err)
+		return false, err
+	}
+
+	transaction := domain.NewBalanceTransaction(domain.BalanceTransactionHoldRelease, "balance_hold", hold.ID, hold.Reason)
+	transaction.AccountID = hold.AccountID
+	transaction.Amount = hold.Amount
+	transaction.BalanceAfter = balance
+
+	if err := balance_transaction_repository.Record(ctx, tx, transaction); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+const selectBalanceHold = `
+	SELECT id, account_id, kind, amount, reason, COALESCE(invoice_id::text, ''), status, release_at, created_at, released_at
+	FROM balance_holds
+`
+
+func scanBalanceHold(row interface{ Scan(dest ...any) error }) (*domain.BalanceHold, error) {
+	var hold domain.BalanceHold
+	var releaseAt, releasedAt sql.NullTime
+
+	err := row.Scan(
+		&hold.ID,
+		&hold.AccountID,
+		&hold.Kind,
+		&hold.Amount,
+		&hold.Reason,
+		&hold.InvoiceID,
+		&hold.Status,
+		&releaseAt,
+		&hold.CreatedAt,
+		&releasedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hold.ReleaseAt = releaseAt.Time
+	hold.ReleasedAt = releasedAt.Time
+
+	return &hold, nil
+}
+
+func (repository *BalanceHoldRepository) FindByID(ctx context.Context, id string) (*domain.BalanceHold, error) {
+	hold, err := scanBalanceHold(repository.db.QueryRowContext(ctx, selectBalanceHold+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrBalanceHoldNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding balance hold %s: %v", id, err)
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (repository *BalanceHoldRepository) Search(ctx context.Context, filter domain.BalanceHoldFilter) ([]*domain.BalanceHold, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, selectBalanceHold+`
+		WHERE ($1 = '' OR account_id::text = $1)
+			AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, filter.AccountID, string(filter.Status), limit)
+
+	if err != nil {
+		log.Printf("Error searching balance holds: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanBalanceHolds(rows)
+}
+
+// FindDue lists active holds whose release date has passed.
+func (repository *BalanceHoldRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.BalanceHold, error) {
+	rows, err := repository.db.QueryContext(ctx, selectBalanceHold+`
+		WHERE status = $1 AND release_at <= $2
+		ORDER BY release_at
+		LIMIT $3
+	`, domain.BalanceHoldStatusActive, now, limit)
+
+	if err != nil {
+		log.Printf("Error finding due balance holds: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanBalanceHolds(rows)
+}
+
+func scanBalanceHolds(rows *sql.Rows) ([]*domain.BalanceHold, error) {
+	var holds []*domain.BalanceHold
+	for rows.Next() {
+		hold, err := scanBalanceHold(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
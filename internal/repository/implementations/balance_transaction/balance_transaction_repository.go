@@ -0,0 +1,166 @@
+package balance_transaction_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type BalanceTransactionRepository struct {
+	db *sql.DB
+}
+
+func NewBalanceTransactionRepository(db *sql.DB) *BalanceTransactionRepository {
+	return &BalanceTransactionRepository{
+		db: db,
+	}
+}
+
+// Record writes an entry inside the transaction that moved the balance, so
+// the ledger can never disagree with the account. Callers hold the account's
+// row lock, which keeps the entries of an account in balance order.
+func Record(ctx context.Context, tx *sql.Tx, transaction *domain.BalanceTransaction) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO balance_transactions (id, account_id, type, amount, balance_after, source_type, source_id, description, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		transaction.ID,
+		transaction.AccountID,
+		transaction.Type,
+		transaction.Amount,
+		transaction.BalanceAfter,
+		transaction.SourceType,
+		nullString(transaction.SourceID),
+		transaction.Description,
+		transaction.CreatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error recording balance transaction for account %s: %v", transaction.AccountID, err)
+		return err
+	}
+
+	return nil
+}
+
+const selectBalanceTransaction = `
+	SELECT id, account_id, type, amount, balance_after, source_type, COALESCE(source_id::text, ''), description, created_at
+	FROM balance_transactions
+`
+
+func scanBalanceTransaction(row interface{ Scan(dest ...any) error }) (*domain.BalanceTransaction, error) {
+	var transaction domain.BalanceTransaction
+
+	err := row.Scan(
+		&transaction.ID,
+		&transaction.AccountID,
+		&transaction.Type,
+		&transaction.Amount,
+		&transaction.BalanceAfter,
+		&transaction.SourceType,
+		&transaction.SourceID,
+		&transaction.Description,
+		&transaction.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// Search lists an account's entries, newest first. From is inclusive and To
+// exclusive.
+func (repository *BalanceTransactionRepository) Search(ctx context.Context, filter domain.BalanceTransactionFilter) ([]*domain.BalanceTransaction, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, selectBalanceTransaction+`
+		WHERE account_id = $1
+			AND ($2 = '' OR type = $2)
+			AND ($3 = '' OR source_type = $3)
+			AND ($4 = '' OR source_id::text = $4)
+			AND ($5::timestamp IS NULL OR created_at >= $5)
+			AND ($6::timestamp IS NULL OR created_at < $6)
+		ORDER BY seq DESC
+		LIMIT $7
+	`, filter.AccountID, string(filter.Type), filter.SourceType, filter.SourceID, nullTime(filter.From), nullTime(filter.To), limit)
+
+	if err != nil {
+		log.Printf("Error searching balance transactions of account %s: %v", filter.AccountID, err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanBalanceTransactions(rows)
+}
+
+// ListPeriod returns every entry of an account in [from, to), oldest first.
+func (repository *BalanceTransactionRepository) ListPeriod(ctx context.Context, accountID string, from, to time.Time) ([]*domain.BalanceTransaction, error) {
+	rows, err := repository.db.QueryContext(ctx, selectBalanceTransaction+`
+		WHERE account_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY seq
+	`, accountID, from, to)
+
+	if err != nil {
+		log.Printf("Error listing balance transactions of account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanBalanceTransactions(rows)
+}
+
+// BalanceAt is the available balance of an account right before t. Accounts
+// that held money before the ledger existed have no entry to read it from,
+// so it falls back to working back from the first later entry, and to the
+// current balance when there is none.
+func (repository *BalanceTransactionRepository) BalanceAt(ctx context.Context, accountID string, t time.Time) (float64, error) {
+	var balance float64
+
+	err := repository.db.QueryRowContext(ctx, `
+		SELECT COALESCE(
+			(SELECT balance_after FROM balance_transactions WHERE account_id = $1 AND created_at < $2 ORDER BY seq DESC LIMIT 1),
+			(SELECT balance_after - amount FROM balance_transactions WHERE account_id = $1 AND created_at >= $2 ORDER BY seq LIMIT 1),
+			(SELECT balance FROM accounts WHERE id = $1),
+			0
+		)
+	`, accountID, t).Scan(&balance)
+
+	if err != nil {
+		log.Printf("Error reading balance of account %s at %s: %v", accountID, t, err)
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func scanBalanceTransactions(rows *sql.Rows) ([]*domain.BalanceTransaction, error) {
+	var transactions []*domain.BalanceTransaction
+	for rows.Next() {
+		transaction, err := scanBalanceTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
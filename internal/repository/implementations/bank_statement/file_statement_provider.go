@@ -0,0 +1,53 @@
+package bank_statement_repository
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+// FileStatementProvider stands in for the bank outside production. It reads
+// a JSON file in the format of Inter's extrato API and returns the entries
+// that fall in the requested period.
+type FileStatementProvider struct {
+	path string
+}
+
+func NewFileStatementProvider(path string) *FileStatementProvider {
+	return &FileStatementProvider{
+		path: path,
+	}
+}
+
+func (provider *FileStatementProvider) Statement(ctx context.Context, from, to time.Time) ([]domain.BankStatementEntry, error) {
+	content, err := os.ReadFile(provider.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var statement interStatement
+	if err := json.Unmarshal(content, &statement); err != nil {
+		return nil, err
+	}
+
+	entries, err := statement.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var inPeriod []domain.BankStatementEntry
+	for _, entry := range entries {
+		if !entry.Date.Before(from) && !entry.Date.After(to) {
+			inPeriod = append(inPeriod, entry)
+		}
+	}
+
+	return inPeriod, nil
+}
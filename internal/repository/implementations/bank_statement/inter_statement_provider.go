@@ -0,0 +1,128 @@
+package bank_statement_repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/inter"
+)
+
+const interStatementPageSize = 1000
+
+// InterStatementProvider reads the account statement from Inter's extrato
+// API, page by page.
+type InterStatementProvider struct {
+	client *inter.Client
+}
+
+func NewInterStatementProvider(client *inter.Client) *InterStatementProvider {
+	return &InterStatementProvider{
+		client: client,
+	}
+}
+
+// interStatement is the body of GET /banking/v2/extrato/completo; the file
+// stand-in reads the same format.
+type interStatement struct {
+	UltimaPagina bool                  `json:"ultimaPagina"`
+	Transacoes   []interStatementEntry `json:"transacoes"`
+}
+
+type interStatementEntry struct {
+	IDTransacao   string `json:"idTransacao"`
+	DataTransacao string `json:"dataTransacao"`
+	TipoTransacao string `json:"tipoTransacao"`
+	TipoOperacao  string `json:"tipoOperacao"`
+	Valor         string `json:"valor"`
+	Titulo        string `json:"titulo"`
+	Descricao     string `json:"descricao"`
+	Detalhes      struct {
+		SeuNumero         string `json:"seuNumero"`
+		NossoNumero       string `json:"nossoNumero"`
+		CodigoSolicitacao string `json:"codigoSolicitacao"`
+	} `json:"detalhes"`
+}
+
+func (provider *InterStatementProvider) Statement(ctx context.Context, from, to time.Time) ([]domain.BankStatementEntry, error) {
+	var entries []domain.BankStatementEntry
+
+	for page := 0; ; page++ {
+		query := url.Values{}
+		query.Set("dataInicio", from.Format(time.DateOnly))
+		query.Set("dataFim", to.Format(time.DateOnly))
+		query.Set("pagina", strconv.Itoa(page))
+		query.Set("tamanhoPagina", strconv.Itoa(interStatementPageSize))
+
+		status, body, err := provider.client.Do(ctx, "GET", "/banking/v2/extrato/completo?"+query.Encode(), nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrProviderUnavailable, err)
+		}
+
+		if status >= 500 {
+			return nil, fmt.Errorf("%w: status %d", domain.ErrProviderUnavailable, status)
+		}
+
+		if status >= 400 {
+			return nil, fmt.Errorf("statement request refused with status %d: %s", status, string(body))
+		}
+
+		var statement interStatement
+		if err := json.Unmarshal(body, &statement); err != nil {
+			return nil, err
+		}
+
+		parsed, err := statement.entries()
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, parsed...)
+
+		if statement.UltimaPagina || len(statement.Transacoes) == 0 {
+			return entries, nil
+		}
+	}
+}
+
+// entries converts the statement lines, making debits negative.
+func (statement interStatement) entries() ([]domain.BankStatementEntry, error) {
+	entries := make([]domain.BankStatementEntry, 0, len(statement.Transacoes))
+	for _, transaction := range statement.Transacoes {
+		amount, err := strconv.ParseFloat(transaction.Valor, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %q in statement entry %s", transaction.Valor, transaction.IDTransacao)
+		}
+
+		date, err := time.ParseInLocation(time.DateOnly, transaction.DataTransacao, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q in statement entry %s", transaction.DataTransacao, transaction.IDTransacao)
+		}
+
+		if transaction.TipoOperacao == "D" {
+			amount = -amount
+		}
+
+		description := transaction.Titulo
+		if transaction.Descricao != "" {
+			description += " " + transaction.Descricao
+		}
+
+		entries = append(entries, domain.BankStatementEntry{
+			ID:          transaction.IDTransacao,
+			Date:        date,
+			Amount:      amount,
+			Type:        transaction.TipoTransacao,
+			Description: description,
+			SeuNumero:   transaction.Detalhes.SeuNumero,
+			NossoNumero: transaction.Detalhes.NossoNumero,
+			ChargeID:    transaction.Detalhes.CodigoSolicitacao,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,74 @@
+package card_vault_repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/encryption"
+)
+
+// CardVaultRepository keeps card numbers sealed under the field cipher and
+// hands out opaque tokens in exchange. CVVs are never stored.
+type CardVaultRepository struct {
+	db     *sql.DB
+	cipher *encryption.FieldCipher
+}
+
+func NewCardVaultRepository(db *sql.DB, cipher *encryption.FieldCipher) *CardVaultRepository {
+	return &CardVaultRepository{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+func generateToken() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+
+	return "tok_" + hex.EncodeToString(b)
+}
+
+func (repository *CardVaultRepository) Store(ctx context.Context, accountID string, card domain.CreditCard) (string, error) {
+	token := generateToken()
+
+	envelope, err := repository.cipher.NewEnvelope(token)
+	if err != nil {
+		return "", err
+	}
+
+	number, err := envelope.Encrypt("number", card.Number)
+	if err != nil {
+		return "", err
+	}
+
+	cardholderName, err := envelope.Encrypt("cardholder_name", card.CardholderName)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = repository.db.ExecContext(ctx, `
+		INSERT INTO card_vault (token, account_id, number, cardholder_name, key_id, wrapped_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, token, accountID, number, cardholderName, envelope.KeyID, envelope.WrappedKey, time.Now())
+
+	if err != nil {
+		log.Printf("Error storing card in vault for account %s: %v", accountID, err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (repository *CardVaultRepository) Delete(ctx context.Context, token string) error {
+	_, err := repository.db.ExecContext(ctx, "DELETE FROM card_vault WHERE token = $1", token)
+	if err != nil {
+		log.Printf("Error deleting vault token: %v", err)
+	}
+
+	return err
+}
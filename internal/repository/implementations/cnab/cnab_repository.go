@@ -0,0 +1,197 @@
+package cnab_repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type CNABRepository struct {
+	db *sql.DB
+}
+
+func NewCNABRepository(db *sql.DB) *CNABRepository {
+	return &CNABRepository{
+		db: db,
+	}
+}
+
+// NextRemittanceSequence returns the number (NSA) of the next remittance.
+func (repository *CNABRepository) NextRemittanceSequence(ctx context.Context) (int, error) {
+	var sequence int
+	if err := repository.db.QueryRowContext(ctx, `SELECT nextval('cnab_remittance_seq')`).Scan(&sequence); err != nil {
+		log.Printf("Error reading next CNAB remittance sequence: %v", err)
+		return 0, err
+	}
+
+	return sequence, nil
+}
+
+// NextOurNumbers reserves count nosso números for new titles.
+func (repository *CNABRepository) NextOurNumbers(ctx context.Context, count int) ([]int64, error) {
+	rows, err := repository.db.QueryContext(ctx, `SELECT nextval('cnab_our_number_seq') FROM generate_series(1, $1)`, count)
+	if err != nil {
+		log.Printf("Error reserving CNAB our numbers: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var numbers []int64
+	for rows.Next() {
+		var number int64
+		if err := rows.Scan(&number); err != nil {
+			return nil, err
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	return numbers, rows.Err()
+}
+
+// SaveRemittance stores the file and records each title's nosso número as
+// the provider charge id of its invoice, in one transaction. It fails with
+// ErrCNABInvoiceChanged if an invoice was paid, expired or registered
+// elsewhere in the meantime.
+func (repository *CNABRepository) SaveRemittance(ctx context.Context, file *domain.CNABFile) error {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for CNAB file %s: %v", file.ID, err)
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, item := range file.Items {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE invoices
+			SET provider_charge_id = $1, updated_at = $2
+			WHERE id = $3 AND status = $4 AND provider_charge_id IS NULL
+		`, item.OurNumber, time.Now(), item.InvoiceID, domain.StatusPending)
+
+		if err != nil {
+			log.Printf("Error registering invoice %s in CNAB file %s: %v", item.InvoiceID, file.ID, err)
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrCNABInvoiceChanged
+		}
+	}
+
+	if err := insert(ctx, tx, file); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repository *CNABRepository) SaveReturn(ctx context.Context, file *domain.CNABFile) error {
+	return insert(ctx, repository.db, file)
+}
+
+func insert(ctx context.Context, db interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}, file *domain.CNABFile) error {
+	items, err := json.Marshal(file.Items)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO cnab_files (id, kind, sequence, file_name, content, items, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		file.ID,
+		file.Kind,
+		file.Sequence,
+		file.FileName,
+		file.Content,
+		string(items),
+		file.CreatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving CNAB file %s: %v", file.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (repository *CNABRepository) FindByID(ctx context.Context, id string) (*domain.CNABFile, error) {
+	var file domain.CNABFile
+	var items []byte
+
+	err := repository.db.QueryRowContext(ctx, `
+		SELECT id, kind, sequence, file_name, content, items, created_at
+		FROM cnab_files
+		WHERE id = $1
+	`, id).Scan(&file.ID, &file.Kind, &file.Sequence, &file.FileName, &file.Content, &items, &file.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCNABFileNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding CNAB file %s: %v", id, err)
+		return nil, err
+	}
+
+	if err := json.Unmarshal(items, &file.Items); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Search lists files newest first, without their content.
+func (repository *CNABRepository) Search(ctx context.Context, filter domain.CNABFileFilter) ([]*domain.CNABFile, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, `
+		SELECT id, kind, sequence, file_name, items, created_at
+		FROM cnab_files
+		WHERE ($1 = '' OR kind = $1)
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, string(filter.Kind), limit)
+
+	if err != nil {
+		log.Printf("Error searching CNAB files: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var files []*domain.CNABFile
+	for rows.Next() {
+		var file domain.CNABFile
+		var items []byte
+
+		if err := rows.Scan(&file.ID, &file.Kind, &file.Sequence, &file.FileName, &items, &file.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(items, &file.Items); err != nil {
+			return nil, err
+		}
+
+		files = append(files, &file)
+	}
+
+	return files, rows.Err()
+}
@@ -0,0 +1,484 @@
+package dispute_repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"math"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	balance_transaction_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_transaction"
+	"github.com/lib/pq"
+)
+
+type DisputeRepository struct {
+	db *sql.DB
+}
+
+func NewDisputeRepository(db *sql.DB) *DisputeRepository {
+	return &DisputeRepository{
+		db: db,
+	}
+}
+
+// Save records a new dispute and moves each hold into the account's reserved
+// balance in one transaction. The card network takes the money whether the
+// account has it or not, so the available balance may go negative.
+func (repository *DisputeRepository) Save(ctx context.Context, dispute *domain.Dispute) error {
+	holds, err := json.Marshal(dispute.Holds)
+	if err != nil {
+		return err
+	}
+
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for dispute %s: %v", dispute.ID, err)
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// Refunds lock the invoice too, so the amount left to contest can't
+	// shrink between this check and the insert. Money lost to an earlier
+	// dispute can't be contested again.
+	var contestable float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT paid_amount - refunded_amount
+		FROM invoices
+		WHERE id = $1
+		FOR UPDATE
+	`, dispute.InvoiceID).Scan(&contestable)
+
+	if err == sql.ErrNoRows {
+		return domain.ErrInvoiceNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error locking invoice %s for dispute %s: %v", dispute.InvoiceID, dispute.ID, err)
+		return err
+	}
+
+	var lost float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM disputes
+		WHERE invoice_id = $1 AND status = $2
+	`, dispute.InvoiceID, domain.DisputeStatusLost).Scan(&lost)
+
+	if err != nil {
+		log.Printf("Error summing lost disputes of invoice %s: %v", dispute.InvoiceID, err)
+		return err
+	}
+
+	if math.Round(dispute.Amount*100) > math.Round((contestable-lost)*100) {
+		return domain.ErrInvalidDisputeAmount
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO disputes (id, invoice_id, account_id, reason, amount, status, holds, deadline, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		dispute.ID,
+		dispute.InvoiceID,
+		dispute.AccountID,
+		dispute.Reason,
+		dispute.Amount,
+		dispute.Status,
+		string(holds),
+		dispute.Deadline,
+		dispute.CreatedAt,
+	)
+
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return domain.ErrDisputeAlreadyOpen
+	}
+
+	if err != nil {
+		log.Printf("Error saving dispute %s: %v", dispute.ID, err)
+		return err
+	}
+
+	for _, hold := range dispute.Holds {
+		if err := placeHold(ctx, tx, dispute, hold); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// placeHold reserves hold the same way a refund takes its reversal: money
+// still waiting to settle for the invoice goes first, the rest comes out of
+// the available balance.
+func placeHold(ctx context.Context, tx *sql.Tx, dispute *domain.Dispute, hold domain.DisputeHold) error {
+	if hold.Amount <= 0 {
+		return nil
+	}
+
+	var settlementID string
+	var scheduled float64
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, amount
+		FROM settlements
+		WHERE invoice_id = $1 AND account_id = $2 AND status = $3
+		FOR UPDATE
+	`, dispute.InvoiceID, hold.AccountID, domain.SettlementStatusScheduled).Scan(&settlementID, &scheduled)
+
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error locking settlement of invoice %s for account %s: %v", dispute.InvoiceID, hold.AccountID, err)
+		return err
+	}
+
+	fromPending := min(hold.Amount, scheduled)
+	if fromPending > 0 {
+		if _, err := tx.ExecContext(ctx, `UPDATE settlements SET amount = amount - $1 WHERE id = $2`, fromPending, settlementID); err != nil {
+			log.Printf("Error reducing settlement %s: %v", settlementID, err)
+			return err
+		}
+	}
+
+	fromAvailable := hold.Amount - fromPending
+
+	var balance float64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE accounts
+		SET pending_balance = pending_balance - $1, balance = balance - $2, reserved_balance = reserved_balance + $3,
+			updated_at = $4
+		WHERE id = $5
+		RETURNING balance
+	`, fromPending, fromAvailable, hold.Amount, time.Now(), hold.AccountID).Scan(&balance)
+
+	if err == sql.ErrNoRows {
+		return domain.ErrAccountNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error placing dispute hold of %.2f for account %s: %v", hold.Amount, hold.AccountID, err)
+		return err
+	}
+
+	// As with refunds, the part held before it settled is entered as a charge
+	// so the statement shows the whole hold and the running balance adds up.
+	var transactions []*domain.BalanceTransaction
+	if fromPending > 0 {
+		charge := domain.NewBalanceTransaction(domain.BalanceTransactionCharge, "invoice", dispute.InvoiceID, "Invoice payment disputed before settlement")
+		charge.Amount = fromPending
+		charge.BalanceAfter = balance + hold.Amount
+
+		transactions = append(transactions, charge)
+	}
+
+	debit := domain.NewBalanceTransaction(domain.BalanceTransactionDispute, "dispute", dispute.ID, string(dispute.Reason))
+	debit.Amount = -hold.Amount
+	debit.BalanceAfter = balance
+
+	for _, transaction := range append(transactions, debit) {
+		transaction.AccountID = hold.AccountID
+
+		if err := balance_transaction_repository.Record(ctx, tx, transaction); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Submit moves a dispute under review. It reports false when the dispute no
+// longer needs a response.
+func (repository *DisputeRepository) Submit(ctx context.Context, dispute *domain.Dispute) (bool, error) {
+	result, err := repository.db.ExecContext(ctx, `
+		UPDATE disputes
+		SET status = $1, submitted_at = $2
+		WHERE id = $3 AND status = $4
+	`, dispute.Status, dispute.SubmittedAt, dispute.ID, domain.DisputeStatusNeedsResponse)
+
+	if err != nil {
+		log.Printf("Error submitting dispute %s: %v", dispute.ID, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Resolve closes an open dispute and settles its holds: a won dispute gives
+// the money back to the available balance, a lost one lets it go. It reports
+// false when the dispute was already closed.
+func (repository *DisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) (bool, error) {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for dispute %s: %v", dispute.ID, err)
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE disputes
+		SET status = $1, resolved_at = $2
+		WHERE id = $3 AND status IN ($4, $5)
+	`, dispute.Status, dispute.ResolvedAt, dispute.ID, domain.DisputeStatusNeedsResponse, domain.DisputeStatusUnderReview)
+
+	if err != nil {
+		log.Printf("Error resolving dispute %s: %v", dispute.ID, err)
+		return false, err
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	for _, hold := range dispute.Holds {
+		if dispute.Status == domain.DisputeStatusWon {
+			transaction := domain.NewBalanceTransaction(domain.BalanceTransactionDisputeWon, "dispute", dispute.ID, string(dispute.Reason))
+			err = moveHold(ctx, tx, hold, hold.Amount, -hold.Amount, transaction)
+		} else {
+			err = moveHold(ctx, tx, hold, 0, -hold.Amount, nil)
+		}
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func moveHold(ctx context.Context, tx *sql.Tx, hold domain.DisputeHold, available, reserved float64, transaction *domain.BalanceTransaction) error {
+	if hold.Amount <= 0 {
+		return nil
+	}
+
+	var balance float64
+	err := tx.QueryRowContext(ctx, `
+		UPDATE accounts
+		SET balance = balance + $1, reserved_balance = reserved_balance + $2, updated_at = $3
+		WHERE id = $4
+		RETURNING balance
+	`, available, reserved, time.Now(), hold.AccountID).Scan(&balance)
+
+	if err == sql.ErrNoRows {
+		return domain.ErrAccountNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error moving dispute hold of %.2f for account %s: %v", hold.Amount, hold.AccountID, err)
+		return err
+	}
+
+	if transaction == nil {
+		return nil
+	}
+
+	transaction.AccountID = hold.AccountID
+	transaction.Amount = available
+	transaction.BalanceAfter = balance
+
+	return balance_transaction_repository.Record(ctx, tx, transaction)
+}
+
+const selectDispute = `
+	SELECT id, invoice_id, account_id, reason, amount, status, holds, deadline, created_at, submitted_at, resolved_at
+	FROM disputes
+`
+
+func scanDispute(row interface{ Scan(dest ...any) error }) (*domain.Dispute, error) {
+	var dispute domain.Dispute
+	var holds []byte
+	var submittedAt, resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&dispute.ID,
+		&dispute.InvoiceID,
+		&dispute.AccountID,
+		&dispute.Reason,
+		&dispute.Amount,
+		&dispute.Status,
+		&holds,
+		&dispute.Deadline,
+		&dispute.CreatedAt,
+		&submittedAt,
+		&resolvedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dispute.SubmittedAt = submittedAt.Time
+	dispute.ResolvedAt = resolvedAt.Time
+
+	if err := json.Unmarshal(holds, &dispute.Holds); err != nil {
+		return nil, err
+	}
+
+	return &dispute, nil
+}
+
+func (repository *DisputeRepository) FindByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	dispute, err := scanDispute(repository.db.QueryRowContext(ctx, selectDispute+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrDisputeNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding dispute %s: %v", id, err)
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (repository *DisputeRepository) Search(ctx context.Context, filter domain.DisputeFilter) ([]*domain.Dispute, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, selectDispute+`
+		WHERE ($1 = '' OR account_id::text = $1)
+			AND ($2 = '' OR invoice_id::text = $2)
+			AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`, filter.AccountID, filter.InvoiceID, string(filter.Status), limit)
+
+	if err != nil {
+		log.Printf("Error searching disputes: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanDisputes(rows)
+}
+
+// FindOverdue lists disputes still waiting for evidence after their deadline.
+func (repository *DisputeRepository) FindOverdue(ctx context.Context, now time.Time, limit int) ([]*domain.Dispute, error) {
+	rows, err := repository.db.QueryContext(ctx, selectDispute+`
+		WHERE status = $1 AND deadline < $2
+		ORDER BY deadline
+		LIMIT $3
+	`, domain.DisputeStatusNeedsResponse, now, limit)
+
+	if err != nil {
+		log.Printf("Error finding overdue disputes: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanDisputes(rows)
+}
+
+func scanDisputes(rows *sql.Rows) ([]*domain.Dispute, error) {
+	var disputes []*domain.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		disputes = append(disputes, dispute)
+	}
+
+	return disputes, rows.Err()
+}
+
+func (repository *DisputeRepository) SaveEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	_, err := repository.db.ExecContext(ctx, `
+		INSERT INTO dispute_evidence (id, dispute_id, kind, text, file_name, content_type, size, content, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		evidence.ID,
+		evidence.DisputeID,
+		evidence.Kind,
+		evidence.Text,
+		evidence.FileName,
+		evidence.ContentType,
+		evidence.Size,
+		evidence.Content,
+		evidence.CreatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving evidence for dispute %s: %v", evidence.DisputeID, err)
+		return err
+	}
+
+	return nil
+}
+
+// ListEvidence returns a dispute's evidence without the file contents.
+func (repository *DisputeRepository) ListEvidence(ctx context.Context, disputeID string) ([]*domain.DisputeEvidence, error) {
+	rows, err := repository.db.QueryContext(ctx, `
+		SELECT id, dispute_id, kind, text, file_name, content_type, size, created_at
+		FROM dispute_evidence
+		WHERE dispute_id = $1
+		ORDER BY created_at
+	`, disputeID)
+
+	if err != nil {
+		log.Printf("Error listing evidence of dispute %s: %v", disputeID, err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var evidence []*domain.DisputeEvidence
+	for rows.Next() {
+		var item domain.DisputeEvidence
+		if err := rows.Scan(&item.ID, &item.DisputeID, &item.Kind, &item.Text, &item.FileName, &item.ContentType, &item.Size, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		evidence = append(evidence, &item)
+	}
+
+	return evidence, rows.Err()
+}
+
+func (repository *DisputeRepository) FindEvidence(ctx context.Context, disputeID, id string) (*domain.DisputeEvidence, error) {
+	var evidence domain.DisputeEvidence
+
+	err := repository.db.QueryRowContext(ctx, `
+		SELECT id, dispute_id, kind, text, file_name, content_type, size, content, created_at
+		FROM dispute_evidence
+		WHERE dispute_id = $1 AND id = $2
+	`, disputeID, id).Scan(
+		&evidence.ID,
+		&evidence.DisputeID,
+		&evidence.Kind,
+		&evidence.Text,
+		&evidence.FileName,
+		&evidence.ContentType,
+		&evidence.Size,
+		&evidence.Content,
+		&evidence.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrDisputeEvidenceNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding evidence %s of dispute %s: %v", id, disputeID, err)
+		return nil, err
+	}
+
+	return &evidence, nil
+}
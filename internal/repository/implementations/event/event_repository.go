@@ -0,0 +1,102 @@
+package event_repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type EventRepository struct {
+	db *sql.DB
+}
+
+func NewEventRepository(db *sql.DB) *EventRepository {
+	return &EventRepository{
+		db: db,
+	}
+}
+
+func (repository *EventRepository) Save(ctx context.Context, event *domain.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.db.ExecContext(ctx, `
+		INSERT INTO events (id, account_id, type, resource_type, resource_id, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		event.ID,
+		event.AccountID,
+		event.Type,
+		event.ResourceType,
+		event.ResourceID,
+		string(data),
+		event.CreatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving event %s: %v", event.ID, err)
+	}
+
+	return err
+}
+
+func (repository *EventRepository) Search(ctx context.Context, filter domain.EventFilter) ([]*domain.Event, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, `
+		SELECT id, account_id, type, resource_type, resource_id, data, created_at
+		FROM events
+		WHERE account_id = $1
+			AND ($2 = '' OR type = $2)
+			AND ($3 = '' OR resource_id = $3)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`, filter.AccountID, filter.Type, filter.ResourceID, limit)
+
+	if err != nil {
+		log.Printf("Error searching events: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		var event domain.Event
+		var data []byte
+
+		err := rows.Scan(
+			&event.ID,
+			&event.AccountID,
+			&event.Type,
+			&event.ResourceType,
+			&event.ResourceID,
+			&data,
+			&event.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &event.Data); err != nil {
+			return nil, err
+		}
+
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -1,132 +1,48 @@
 package invoice_repository
 
 import (
-	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"net/url"
-	"strings"
+	"time"
 
 	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
-	"github.com/NewLeonardooliv/gateway-payment/internal/shared"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/inter"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/taxid"
 )
 
 type InterInvoiceRepository struct {
-	clientID     string
-	clientSecret string
-	certPath     string
-	keyPath      string
-	apiUrl       string
+	client *inter.Client
 }
 
-type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int    `json:"expires_in"`
+type createChargeResponse struct {
+	CodigoSolicitacao string `json:"codigoSolicitacao"`
 }
 
-func NewInterInvoiceRepository(clientID, clientSecret string) *InterInvoiceRepository {
-	apiUrl := "https://cdpj-sandbox.partners.uatinter.co"
-	if shared.GetEnv("ENV", "dev") == "prod" {
-		apiUrl = "https://cdpj.partners.bancointer.com.br"
-	}
-
-	tlsPath := shared.GetEnv("INTERBANK_TLS_PATH", "cert/")
-
-	certPath := tlsPath + "Sandbox_InterAPI_Certificado.crt"
-	keyPath := tlsPath + "Sandbox_InterAPI_Chave.key"
-
-	return &InterInvoiceRepository{clientID, clientSecret, certPath, keyPath, apiUrl}
+func NewInterInvoiceRepository(client *inter.Client) *InterInvoiceRepository {
+	return &InterInvoiceRepository{client: client}
 }
 
-func (r *InterInvoiceRepository) getAccessToken() (*TokenResponse, error) {
-	log.Printf("[InterInvoiceRepository] Starting access token request")
-
-	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
-	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error loading certificate: %v", err)
-		return nil, err
-	}
-	log.Printf("[InterInvoiceRepository] Certificate loaded successfully")
-
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-	}
-
-	transport := &http.Transport{TLSClientConfig: tlsConfig}
-	client := &http.Client{Transport: transport}
-
-	data := url.Values{}
-	data.Set("grant_type", "client_credentials")
-	data.Set("client_id", r.clientID)
-	data.Set("client_secret", r.clientSecret)
-	data.Set("scope", "cob.write cob.read cobv.write cobv.read lotecobv.write lotecobv.read pix.write pix.read webhook.write webhook.read payloadlocation.write payloadlocation.read boleto-cobranca.read boleto-cobranca.write extrato.read pagamento-pix.write pagamento-pix.read extrato-usend.read pagamento-boleto.read pagamento-boleto.write pagamento-darf.write pagamento-lote.write pagamento-lote.read webhook-banking.read webhook-banking.write")
-
-	log.Printf("[InterInvoiceRepository] Sending token request to %s/oauth/v2/token", r.apiUrl)
-
-	req, err := http.NewRequest("POST", r.apiUrl+"/oauth/v2/token", strings.NewReader(data.Encode()))
-	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error creating token request: %v", err)
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error sending token request: %v", err)
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error reading token response: %v", err)
-		return nil, err
-	}
-
-	log.Printf("[InterInvoiceRepository] Token response status: %d, body: %s", resp.StatusCode, string(body))
-
-	if resp.StatusCode >= 400 {
-		log.Printf("[InterInvoiceRepository] Token request failed with status: %d", resp.StatusCode)
-		return nil, fmt.Errorf("token request failed with status: %d", resp.StatusCode)
-	}
-
-	var tokenResp TokenResponse
-	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		log.Printf("[InterInvoiceRepository] Error decoding token response: %v", err)
-		return nil, err
-	}
-
-	log.Printf("[InterInvoiceRepository] Access token obtained successfully")
-
-	return &tokenResp, nil
-}
-
-func (r *InterInvoiceRepository) Save(invoice *domain.Invoice) error {
+func (r *InterInvoiceRepository) Save(ctx context.Context, invoice *domain.Invoice) error {
 	log.Printf("[InterInvoiceRepository] Starting boleto creation for invoice: %s", invoice.Reference)
 
-	token, err := r.getAccessToken()
+	taxIDKind, err := taxid.Validate(invoice.Payer.TaxID)
 	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error obtaining access token: %v", err)
+		log.Printf("[InterInvoiceRepository] Invalid payer tax id for invoice %s: %v", invoice.Reference, err)
 
 		return err
 	}
 
-	log.Printf("[InterInvoiceRepository] Access token obtained successfully")
-
 	payload := map[string]interface{}{
 		"seuNumero":      invoice.Reference,
 		"valorNominal":   fmt.Sprintf("%.2f", invoice.Amount),
 		"dataEmissao":    invoice.CreatedAt.Format("2006-01-02"),
 		"dataVencimento": invoice.DueDate.Format("2006-01-02"),
 		"pagador": map[string]interface{}{
-			"cpfCnpj":    invoice.Payer.TaxID,
-			"tipoPessoa": "FISICA",
+			"cpfCnpj":    taxid.Normalize(invoice.Payer.TaxID),
+			"tipoPessoa": taxIDKind.PersonType(),
 			"nome":       invoice.Payer.Name,
 			"endereco":   invoice.Payer.Address,
 			"cidade":     invoice.Payer.City,
@@ -134,77 +50,153 @@ func (r *InterInvoiceRepository) Save(invoice *domain.Invoice) error {
 			"cep":        invoice.Payer.ZipCode,
 			"bairro":     invoice.Payer.District,
 		},
-		"numDiasAgenda": 60,
+		"numDiasAgenda": invoice.GraceDays,
 	}
 
-	payloadBytes, err := json.Marshal(payload)
+	addChargeRules(payload, invoice)
+
+	status, body, err := r.client.Do(ctx, "POST", "/cobranca/v3/cobrancas", payload, nil)
 	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error serializing payload for invoice %s: %v", invoice.Reference, err)
+		log.Printf("[InterInvoiceRepository] Error sending request for invoice %s: %v", invoice.Reference, err)
 
-		return err
+		return fmt.Errorf("%w: %v", domain.ErrProviderUnavailable, err)
 	}
 
-	log.Printf("[InterInvoiceRepository] Payload created successfully for invoice %s: %s", invoice.Reference, string(payloadBytes))
+	if status >= 500 {
+		log.Printf("[InterInvoiceRepository] Inter unavailable creating boleto for invoice %s. Status: %d, Body: %s", invoice.Reference, status, string(body))
 
-	req, err := http.NewRequest("POST", r.apiUrl+"/cobranca/v3/cobrancas", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error creating request for invoice %s: %v", invoice.Reference, err)
+		return fmt.Errorf("%w: status %d", domain.ErrProviderUnavailable, status)
+	}
 
-		return err
+	if status >= 400 {
+		log.Printf("[InterInvoiceRepository] Error creating boleto for invoice %s. Status: %d, Body: %s", invoice.Reference, status, string(body))
+
+		return fmt.Errorf("error creating Invoice: %s, %d, %s", invoice.Reference, status, string(body))
 	}
 
-	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
-	req.Header.Set("Content-Type", "application/json")
+	var charge createChargeResponse
+	if err := json.Unmarshal(body, &charge); err != nil {
+		log.Printf("[InterInvoiceRepository] Error decoding charge response for invoice %s: %v", invoice.Reference, err)
 
-	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
-	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error loading certificate for invoice %s: %v", invoice.Reference, err)
+		return fmt.Errorf("error decoding charge response for invoice %s: %w", invoice.Reference, err)
+	}
 
-		return err
+	if charge.CodigoSolicitacao == "" {
+		log.Printf("[InterInvoiceRepository] Charge response for invoice %s has no codigoSolicitacao", invoice.Reference)
+
+		return fmt.Errorf("charge response for invoice %s has no codigoSolicitacao", invoice.Reference)
 	}
 
-	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
-	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	invoice.ProviderChargeID = charge.CodigoSolicitacao
 
-	log.Printf("[InterInvoiceRepository] Sending request to create boleto for invoice %s", invoice.Reference)
+	log.Printf("[InterInvoiceRepository] Invoice successfully created for invoice %s", invoice.Reference)
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error sending request for invoice %s: %v", invoice.Reference, err)
+	return nil
+}
 
-		return err
-	}
+// Cancel withdraws the boleto and its Pix QR code so the payer can no longer
+// pay them.
+func (r *InterInvoiceRepository) Cancel(ctx context.Context, invoice *domain.Invoice) error {
+	log.Printf("[InterInvoiceRepository] Cancelling charge %s for invoice %s", invoice.ProviderChargeID, invoice.ID)
 
-	defer resp.Body.Close()
+	status, body, err := r.client.Do(ctx, "POST", "/cobranca/v3/cobrancas/"+url.PathEscape(invoice.ProviderChargeID)+"/cancelar", map[string]interface{}{
+		"motivoCancelamento": "Cobrança expirada",
+	}, nil)
 
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("[InterInvoiceRepository] Error reading API response for invoice %s: %v", invoice.Reference, err)
+		log.Printf("[InterInvoiceRepository] Error sending cancel request for invoice %s: %v", invoice.ID, err)
 
-		return err
+		return fmt.Errorf("%w: %v", domain.ErrProviderUnavailable, err)
 	}
 
-	log.Printf("[InterInvoiceRepository] Inter API response for invoice %s (status %d): %s", invoice.Reference, resp.StatusCode, string(body))
+	if status >= 500 {
+		log.Printf("[InterInvoiceRepository] Inter unavailable cancelling charge for invoice %s. Status: %d, Body: %s", invoice.ID, status, string(body))
 
-	if resp.StatusCode >= 400 {
-		log.Printf("[InterInvoiceRepository] Error creating boleto for invoice %s. Status: %d, Body: %s", invoice.Reference, resp.StatusCode, string(body))
+		return fmt.Errorf("%w: status %d", domain.ErrProviderUnavailable, status)
+	}
 
-		return fmt.Errorf("error creating Invoice: %s, %d, %s", invoice.Reference, resp.StatusCode, string(body))
+	if status >= 400 {
+		log.Printf("[InterInvoiceRepository] Error cancelling charge for invoice %s. Status: %d, Body: %s", invoice.ID, status, string(body))
+
+		return fmt.Errorf("error cancelling charge: %s, %d, %s", invoice.ID, status, string(body))
 	}
 
-	log.Printf("[InterInvoiceRepository] Invoice successfully created for invoice %s", invoice.Reference)
+	log.Printf("[InterInvoiceRepository] Charge cancelled for invoice %s", invoice.ID)
 
 	return nil
 }
 
-func (r *InterInvoiceRepository) FindByID(id string) (*domain.Invoice, error) {
+func (r *InterInvoiceRepository) FindByID(ctx context.Context, id string) (*domain.Invoice, error) {
 	return nil, domain.ErrMethodNotImplemented
 }
 
-func (r *InterInvoiceRepository) FindByAccountID(accountID string) ([]*domain.Invoice, error) {
+func (r *InterInvoiceRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.Invoice, error) {
 	return nil, domain.ErrMethodNotImplemented
 }
 
-func (r *InterInvoiceRepository) UpdateStatus(invoice *domain.Invoice) error {
+func (r *InterInvoiceRepository) UpdateStatus(ctx context.Context, invoice *domain.Invoice) error {
 	return domain.ErrMethodNotImplemented
 }
+
+func (r *InterInvoiceRepository) Search(ctx context.Context, filter domain.InvoiceFilter) ([]*domain.Invoice, error) {
+	return nil, domain.ErrMethodNotImplemented
+}
+
+func (r *InterInvoiceRepository) Create(ctx context.Context, invoice *domain.Invoice, settlements []*domain.Settlement) error {
+	return domain.ErrMethodNotImplemented
+}
+
+func (r *InterInvoiceRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Invoice, error) {
+	return nil, domain.ErrMethodNotImplemented
+}
+
+func (r *InterInvoiceRepository) FindByProviderChargeID(ctx context.Context, chargeID string) (*domain.Invoice, error) {
+	return nil, domain.ErrMethodNotImplemented
+}
+
+func (r *InterInvoiceRepository) Settle(ctx context.Context, invoice *domain.Invoice) error {
+	return domain.ErrMethodNotImplemented
+}
+
+func (r *InterInvoiceRepository) FindUnregistered(ctx context.Context, paymentType domain.PaymentMethod, limit int) ([]*domain.Invoice, error) {
+	return nil, domain.ErrMethodNotImplemented
+}
+
+func (r *InterInvoiceRepository) FindOpenForPeriod(ctx context.Context, subscriptionID string, periodStart time.Time) (*domain.Invoice, error) {
+	return nil, domain.ErrMethodNotImplemented
+}
+
+// addChargeRules maps fine, interest and discounts onto the cobrança payload.
+// Inter takes a single discount, so only the earliest tier is registered with
+// the bank; the gateway still settles against every tier.
+func addChargeRules(payload map[string]interface{}, invoice *domain.Invoice) {
+	if invoice.Fine != nil {
+		fine := map[string]interface{}{"codigo": "VALORFIXO", "valor": fmt.Sprintf("%.2f", invoice.Fine.Value)}
+		if invoice.Fine.Type == domain.AdjustmentPercent {
+			fine = map[string]interface{}{"codigo": "PERCENTUAL", "taxa": fmt.Sprintf("%.2f", invoice.Fine.Value)}
+		}
+
+		payload["multa"] = fine
+	}
+
+	if invoice.Interest != nil {
+		interest := map[string]interface{}{"codigo": "VALORDIA", "valor": fmt.Sprintf("%.2f", invoice.Interest.Value)}
+		if invoice.Interest.Type == domain.AdjustmentPercent {
+			interest = map[string]interface{}{"codigo": "TAXAMENSAL", "taxa": fmt.Sprintf("%.2f", invoice.Interest.Value)}
+		}
+
+		payload["mora"] = interest
+	}
+
+	if len(invoice.Discounts) > 0 {
+		discount := invoice.Discounts[0]
+		days := int(invoice.DueDate.Sub(discount.Until).Hours() / 24)
+
+		rule := map[string]interface{}{"codigo": "VALORFIXODATAINFORMADA", "valor": fmt.Sprintf("%.2f", discount.Value), "quantidadeDias": days}
+		if discount.Type == domain.AdjustmentPercent {
+			rule = map[string]interface{}{"codigo": "PERCENTUALDATAINFORMADA", "taxa": fmt.Sprintf("%.2f", discount.Value), "quantidadeDias": days}
+		}
+
+		payload["desconto"] = rule
+	}
+}
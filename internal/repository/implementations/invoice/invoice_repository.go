@@ -1,124 +1,238 @@
 package invoice_repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"log"
+	"time"
 
 	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	settlement_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/settlement"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/encryption"
+	"github.com/lib/pq"
 )
 
 type PostgresInvoiceRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	cipher *encryption.FieldCipher
 }
 
-func NewPostgresInvoiceRepository(db *sql.DB) *PostgresInvoiceRepository {
+func NewPostgresInvoiceRepository(db *sql.DB, cipher *encryption.FieldCipher) *PostgresInvoiceRepository {
 	return &PostgresInvoiceRepository{
-		db: db,
+		db:     db,
+		cipher: cipher,
 	}
 }
 
-func (repository *PostgresInvoiceRepository) Save(invoice *domain.Invoice) error {
-	log.Printf("Saving invoice: %+v", invoice)
+type chargeRules struct {
+	Fine      *domain.Fine      `json:"fine,omitempty"`
+	Interest  *domain.Interest  `json:"interest,omitempty"`
+	Discounts []domain.Discount `json:"discounts,omitempty"`
+}
+
+func (repository *PostgresInvoiceRepository) Save(ctx context.Context, invoice *domain.Invoice) error {
+	return repository.Create(ctx, invoice, nil)
+}
+
+// Create saves a new invoice together with the settlements of an invoice
+// already paid, in one transaction, so a merchant is never credited for an
+// invoice that was not stored or the other way round.
+func (repository *PostgresInvoiceRepository) Create(ctx context.Context, invoice *domain.Invoice, settlements []*domain.Settlement) error {
+	log.Printf("Saving invoice %s for account %s", invoice.ID, invoice.AccountID)
+
+	rules, err := json.Marshal(chargeRules{
+		Fine:      invoice.Fine,
+		Interest:  invoice.Interest,
+		Discounts: invoice.Discounts,
+	})
+	if err != nil {
+		return err
+	}
+
+	feeRule, err := json.Marshal(invoice.FeeRule)
+	if err != nil {
+		return err
+	}
+
+	splits, err := json.Marshal(invoice.Splits)
+	if err != nil {
+		return err
+	}
+
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for invoice %s: %v", invoice.ID, err)
+
+		return err
+	}
+
+	defer tx.Rollback()
 
-	_, err := repository.db.Exec(
-		"INSERT INTO invoices (id, account_id, amount, status, description, payment_type, card_last_digits, due_date, reference, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO invoices (id, account_id, customer_id, payment_method_id, subscription_id, amount, status, description, payment_type, card_last_digits, decline_code, due_date, grace_days, charge_rules, splits, installments, fee_rule, fee_amount, net_amount, paid_amount, paid_at, reference, provider_charge_id, period_start, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26)",
 		invoice.ID,
 		invoice.AccountID,
+		invoice.CustomerID,
+		nullString(invoice.PaymentMethodID),
+		nullString(invoice.SubscriptionID),
 		invoice.Amount,
 		invoice.Status,
 		invoice.Description,
 		invoice.PaymentType,
 		invoice.CardLastDigits,
+		nullString(string(invoice.DeclineCode)),
 		invoice.DueDate,
+		invoice.GraceDays,
+		string(rules),
+		string(splits),
+		invoice.Installments,
+		string(feeRule),
+		invoice.FeeAmount,
+		invoice.NetAmount,
+		invoice.PaidAmount,
+		nullTime(invoice.PaidAt),
 		invoice.Reference,
+		nullString(invoice.ProviderChargeID),
+		nullDate(invoice.PeriodStart),
 		invoice.CreatedAt,
 		invoice.UpdatedAt,
 	)
 
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Constraint == "idx_invoice_subscription_period" {
+		return domain.ErrPeriodAlreadyInvoiced
+	}
+
 	if err != nil {
 		log.Printf("Error saving invoice %s: %v", invoice.ID, err)
 
 		return err
 	}
 
-	log.Printf("Invoice saved successfully: %s", invoice.ID)
+	for _, settlement := range settlements {
+		if err := settlement_repository.Insert(ctx, tx, settlement); err != nil {
+			return err
+		}
+	}
 
-	_, err = repository.db.Exec(
-		"INSERT INTO payers (id, invoice_id, name, tax_id, email, phone, address, number, district, city, state, zip_code, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)",
-		invoice.Payer.ID,
-		invoice.ID,
-		invoice.Payer.Name,
-		invoice.Payer.TaxID,
-		invoice.Payer.Email,
-		invoice.Payer.Phone,
-		invoice.Payer.Address,
-		invoice.Payer.Number,
-		invoice.Payer.District,
-		invoice.Payer.City,
-		invoice.Payer.State,
-		invoice.Payer.ZipCode,
-		invoice.Payer.CreatedAt,
-		invoice.Payer.UpdatedAt,
-	)
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing invoice %s: %v", invoice.ID, err)
 
-	if err != nil {
-		log.Printf("Error saving payer %s: %v", invoice.Payer.ID, err)
 		return err
 	}
 
-	log.Printf("Payer saved successfully: %s", invoice.Payer.ID)
+	log.Printf("Invoice saved successfully: %s", invoice.ID)
 
 	return nil
 }
 
-func (r *PostgresInvoiceRepository) FindByID(id string) (*domain.Invoice, error) {
-	log.Printf("Finding invoice by ID: %s", id)
-
+const selectInvoice = `
+	SELECT i.id, i.account_id, COALESCE(i.customer_id::text, ''), COALESCE(i.payment_method_id::text, ''), COALESCE(i.subscription_id::text, ''), i.amount, i.status, i.description, i.payment_type, COALESCE(i.card_last_digits, ''),
+		COALESCE(i.decline_code, ''), COALESCE(i.reference, ''), i.due_date, i.grace_days, COALESCE(i.provider_charge_id, ''),
+		COALESCE(i.charge_rules, '{}'), i.splits, i.installments, i.fee_rule, i.fee_amount, i.net_amount, i.paid_amount, i.refunded_amount, i.paid_at, i.created_at, i.updated_at,
+		COALESCE(p.id::text, ''), COALESCE(p.name, ''), COALESCE(p.tax_id, ''), COALESCE(p.email, ''), COALESCE(p.phone, ''),
+		COALESCE(p.address, ''), COALESCE(p.number, ''), COALESCE(p.district, ''), COALESCE(p.city, ''),
+		COALESCE(p.state, ''), COALESCE(p.zip_code, ''), COALESCE(p.key_id, ''), COALESCE(p.wrapped_key, '')
+	FROM invoices i
+	LEFT JOIN payers p ON p.id = i.customer_id
+`
+
+func (r *PostgresInvoiceRepository) scanInvoice(row interface{ Scan(dest ...any) error }) (*domain.Invoice, error) {
 	var invoice domain.Invoice
-	err := r.db.QueryRow(`
-		SELECT id, account_id, amount, status, description, payment_type, card_last_digits, created_at, updated_at
-		FROM invoices
-		WHERE id = $1
-	`, id).Scan(
+	var dueDate, paidAt sql.NullTime
+	var keyID, wrappedKey string
+	var rules, splits, feeRule []byte
+
+	err := row.Scan(
 		&invoice.ID,
 		&invoice.AccountID,
+		&invoice.CustomerID,
+		&invoice.PaymentMethodID,
+		&invoice.SubscriptionID,
 		&invoice.Amount,
 		&invoice.Status,
 		&invoice.Description,
 		&invoice.PaymentType,
 		&invoice.CardLastDigits,
+		&invoice.DeclineCode,
+		&invoice.Reference,
+		&dueDate,
+		&invoice.GraceDays,
+		&invoice.ProviderChargeID,
+		&rules,
+		&splits,
+		&invoice.Installments,
+		&feeRule,
+		&invoice.FeeAmount,
+		&invoice.NetAmount,
+		&invoice.PaidAmount,
+		&invoice.RefundedAmount,
+		&paidAt,
 		&invoice.CreatedAt,
 		&invoice.UpdatedAt,
+		&invoice.Payer.ID,
+		&invoice.Payer.Name,
+		&invoice.Payer.TaxID,
+		&invoice.Payer.Email,
+		&invoice.Payer.Phone,
+		&invoice.Payer.Address,
+		&invoice.Payer.Number,
+		&invoice.Payer.District,
+		&invoice.Payer.City,
+		&invoice.Payer.State,
+		&invoice.Payer.ZipCode,
+		&keyID,
+		&wrappedKey,
 	)
 
-	if err == sql.ErrNoRows {
-		log.Printf("Invoice not found: %s", id)
-		return nil, domain.ErrInvoiceNotFound
+	if err != nil {
+		return nil, err
 	}
 
-	if err != nil {
-		log.Printf("Error finding invoice %s: %v", id, err)
+	if dueDate.Valid {
+		invoice.DueDate = dueDate.Time
+	}
+
+	invoice.PaidAt = paidAt.Time
 
+	var charge chargeRules
+	if err := json.Unmarshal(rules, &charge); err != nil {
 		return nil, err
 	}
 
-	log.Printf("Invoice found: %+v", invoice)
+	invoice.Fine = charge.Fine
+	invoice.Interest = charge.Interest
+	invoice.Discounts = charge.Discounts
 
-	return &invoice, nil
-}
+	if err := json.Unmarshal(splits, &invoice.Splits); err != nil {
+		return nil, err
+	}
 
-func (r *PostgresInvoiceRepository) FindByAccountID(accountID string) ([]*domain.Invoice, error) {
-	log.Printf("FindByAccountID called with accountID: %s", accountID)
+	if err := json.Unmarshal(feeRule, &invoice.FeeRule); err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.Query(`
-		SELECT id, account_id, amount, status, description, payment_type, card_last_digits, created_at, updated_at
-		FROM invoices
-		WHERE account_id = $1
-	`, accountID)
+	invoice.Payer.AccountID = invoice.AccountID
 
-	if err != nil {
-		log.Printf("Error executing query for accountID %s: %v", accountID, err)
+	if keyID != "" {
+		envelope, err := r.cipher.OpenEnvelope(invoice.Payer.ID, keyID, wrappedKey)
+		if err != nil {
+			log.Printf("Error opening encryption envelope for payer %s: %v", invoice.Payer.ID, err)
+			return nil, err
+		}
+
+		if err := envelope.DecryptFields(invoice.Payer.SensitiveFields()); err != nil {
+			log.Printf("Error decrypting payer %s: %v", invoice.Payer.ID, err)
+			return nil, err
+		}
+	}
+
+	return &invoice, nil
+}
 
+func (r *PostgresInvoiceRepository) queryInvoices(ctx context.Context, query string, args ...any) ([]*domain.Invoice, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
 		return nil, err
 	}
 
@@ -130,22 +244,54 @@ func (r *PostgresInvoiceRepository) FindByAccountID(accountID string) ([]*domain
 
 	var invoices []*domain.Invoice
 	for rows.Next() {
-		var invoice domain.Invoice
-		err := rows.Scan(
-			&invoice.ID, &invoice.AccountID, &invoice.Amount, &invoice.Status, &invoice.Description, &invoice.PaymentType, &invoice.CardLastDigits, &invoice.CreatedAt, &invoice.UpdatedAt,
-		)
+		invoice, err := r.scanInvoice(rows)
 		if err != nil {
-			log.Printf("Error scanning row for accountID %s: %v", accountID, err)
-
 			return nil, err
 		}
 
-		log.Printf("Fetched invoice: %+v", invoice)
-		invoices = append(invoices, &invoice)
+		invoices = append(invoices, invoice)
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("Rows iteration error for accountID %s: %v", accountID, err)
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+func (r *PostgresInvoiceRepository) FindByID(ctx context.Context, id string) (*domain.Invoice, error) {
+	log.Printf("Finding invoice by ID: %s", id)
+
+	invoice, err := r.scanInvoice(r.db.QueryRowContext(ctx, selectInvoice+`
+		WHERE i.id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		log.Printf("Invoice not found: %s", id)
+		return nil, domain.ErrInvoiceNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding invoice %s: %v", id, err)
+
+		return nil, err
+	}
+
+	log.Printf("Invoice found: %s", invoice.ID)
+
+	return invoice, nil
+}
+
+func (r *PostgresInvoiceRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.Invoice, error) {
+	log.Printf("FindByAccountID called with accountID: %s", accountID)
+
+	invoices, err := r.queryInvoices(ctx, selectInvoice+`
+		WHERE i.account_id = $1
+		ORDER BY i.created_at DESC
+	`, accountID)
+
+	if err != nil {
+		log.Printf("Error fetching invoices for accountID %s: %v", accountID, err)
 
 		return nil, err
 	}
@@ -155,10 +301,35 @@ func (r *PostgresInvoiceRepository) FindByAccountID(accountID string) ([]*domain
 	return invoices, nil
 }
 
-func (r *PostgresInvoiceRepository) UpdateStatus(invoice *domain.Invoice) error {
+func (r *PostgresInvoiceRepository) Search(ctx context.Context, filter domain.InvoiceFilter) ([]*domain.Invoice, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	invoices, err := r.queryInvoices(ctx, selectInvoice+`
+		WHERE ($1 = '' OR i.account_id::text = $1)
+			AND ($2 = '' OR i.customer_id::text = $2)
+			AND ($3 = '' OR i.status = $3)
+			AND ($4 = '' OR i.id::text = $4 OR i.reference ILIKE '%' || $4 || '%' OR i.description ILIKE '%' || $4 || '%'
+				OR p.name ILIKE '%' || $4 || '%' OR p.tax_id_index = $5)
+		ORDER BY i.created_at DESC
+		LIMIT $6
+	`, filter.AccountID, filter.CustomerID, string(filter.Status), filter.Query, r.cipher.BlindIndex(filter.Query), limit)
+
+	if err != nil {
+		log.Printf("Error searching invoices: %v", err)
+
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+func (r *PostgresInvoiceRepository) UpdateStatus(ctx context.Context, invoice *domain.Invoice) error {
 	log.Printf("Updating status for invoice ID %s to %s", invoice.ID, invoice.Status)
 
-	result, err := r.db.Exec(
+	result, err := r.db.ExecContext(ctx,
 		"UPDATE invoices SET status = $1, updated_at = $2 WHERE id = $3",
 		invoice.Status, invoice.UpdatedAt, invoice.ID,
 	)
@@ -185,3 +356,151 @@ func (r *PostgresInvoiceRepository) UpdateStatus(invoice *domain.Invoice) error
 
 	return nil
 }
+
+// Cancel moves a still pending invoice to its new status; it fails with
+// ErrInvalidStatus when the invoice settled or was cancelled in the meantime.
+func (r *PostgresInvoiceRepository) Cancel(ctx context.Context, invoice *domain.Invoice) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE invoices SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4",
+		invoice.Status, invoice.UpdatedAt, invoice.ID, domain.StatusPending,
+	)
+
+	if err != nil {
+		log.Printf("Error cancelling invoice %s: %v", invoice.ID, err)
+
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidStatus
+	}
+
+	return nil
+}
+
+func (r *PostgresInvoiceRepository) FindByProviderChargeID(ctx context.Context, chargeID string) (*domain.Invoice, error) {
+	invoice, err := r.scanInvoice(r.db.QueryRowContext(ctx, selectInvoice+`
+		WHERE i.provider_charge_id = $1
+	`, chargeID))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvoiceNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding invoice for provider charge %s: %v", chargeID, err)
+
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// Settle records the payment of a still pending invoice; it fails with
+// ErrInvalidStatus when the invoice was already settled or expired.
+func (r *PostgresInvoiceRepository) Settle(ctx context.Context, invoice *domain.Invoice) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE invoices SET status = $1, paid_amount = $2, paid_at = $3, fee_amount = $4, net_amount = $5, updated_at = $6 WHERE id = $7 AND status = $8",
+		invoice.Status, invoice.PaidAmount, nullTime(invoice.PaidAt), invoice.FeeAmount, invoice.NetAmount, invoice.UpdatedAt, invoice.ID, domain.StatusPending,
+	)
+
+	if err != nil {
+		log.Printf("Error settling invoice %s: %v", invoice.ID, err)
+
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidStatus
+	}
+
+	return nil
+}
+
+func (r *PostgresInvoiceRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Invoice, error) {
+	invoices, err := r.queryInvoices(ctx, selectInvoice+`
+		WHERE i.status = $1
+			AND i.due_date + (i.grace_days + 1) * INTERVAL '1 day' <= $2
+		ORDER BY i.due_date
+		LIMIT $3
+	`, domain.StatusPending, now, limit)
+
+	if err != nil {
+		log.Printf("Error finding expired invoices: %v", err)
+
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// FindUnregistered lists pending invoices of a payment type that were never
+// registered with a provider and are not yet past their due date.
+func (r *PostgresInvoiceRepository) FindUnregistered(ctx context.Context, paymentType domain.PaymentMethod, limit int) ([]*domain.Invoice, error) {
+	invoices, err := r.queryInvoices(ctx, selectInvoice+`
+		WHERE i.status = $1
+			AND i.payment_type = $2
+			AND i.provider_charge_id IS NULL
+			AND i.due_date >= CURRENT_DATE
+		ORDER BY i.created_at
+		LIMIT $3
+	`, domain.StatusPending, paymentType, limit)
+
+	if err != nil {
+		log.Printf("Error finding unregistered invoices: %v", err)
+
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// FindOpenForPeriod returns the pending or paid invoice charging a
+// subscription period, if any.
+func (r *PostgresInvoiceRepository) FindOpenForPeriod(ctx context.Context, subscriptionID string, periodStart time.Time) (*domain.Invoice, error) {
+	invoice, err := r.scanInvoice(r.db.QueryRowContext(ctx, selectInvoice+`
+		WHERE i.subscription_id = $1
+			AND i.period_start = $2
+			AND i.status IN ($3, $4)
+	`, subscriptionID, nullDate(periodStart), domain.StatusPending, domain.StatusApproved))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvoiceNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding invoice of subscription %s for period %s: %v", subscriptionID, periodStart.Format(time.DateOnly), err)
+
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullDate keeps only the calendar day, so the database never shifts it
+// across time zones.
+func nullDate(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: t.Format(time.DateOnly), Valid: true}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
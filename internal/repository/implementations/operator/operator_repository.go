@@ -0,0 +1,123 @@
+package operator_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type OperatorRepository struct {
+	db *sql.DB
+}
+
+func NewOperatorRepository(db *sql.DB) *OperatorRepository {
+	return &OperatorRepository{
+		db: db,
+	}
+}
+
+func scanOperator(row interface{ Scan(dest ...any) error }) (*domain.Operator, error) {
+	var operator domain.Operator
+	var totpSecret sql.NullString
+	var disabledAt sql.NullTime
+
+	err := row.Scan(
+		&operator.ID,
+		&operator.Name,
+		&operator.Email,
+		&operator.PasswordHash,
+		&totpSecret,
+		&operator.Role,
+		&operator.CreatedAt,
+		&operator.UpdatedAt,
+		&disabledAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	operator.TOTPSecret = totpSecret.String
+	if disabledAt.Valid {
+		operator.DisabledAt = disabledAt.Time
+	}
+
+	return &operator, nil
+}
+
+func (repository *OperatorRepository) Save(ctx context.Context, operator *domain.Operator) error {
+	log.Printf("Saving operator %s with role %s", operator.ID, operator.Role)
+
+	_, err := repository.db.ExecContext(ctx, `
+		INSERT INTO operators (id, name, email, password_hash, totp_secret, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		operator.ID,
+		operator.Name,
+		operator.Email,
+		operator.PasswordHash,
+		sql.NullString{String: operator.TOTPSecret, Valid: operator.TOTPSecret != ""},
+		operator.Role,
+		operator.CreatedAt,
+		operator.UpdatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving operator %s: %v", operator.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (repository *OperatorRepository) FindByID(ctx context.Context, id string) (*domain.Operator, error) {
+	operator, err := scanOperator(repository.db.QueryRowContext(ctx, `
+		SELECT id, name, email, password_hash, totp_secret, role, created_at, updated_at, disabled_at
+		FROM operators
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrOperatorNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding operator %s: %v", id, err)
+		return nil, err
+	}
+
+	return operator, nil
+}
+
+func (repository *OperatorRepository) FindByEmail(ctx context.Context, email string) (*domain.Operator, error) {
+	operator, err := scanOperator(repository.db.QueryRowContext(ctx, `
+		SELECT id, name, email, password_hash, totp_secret, role, created_at, updated_at, disabled_at
+		FROM operators
+		WHERE email = $1
+	`, email))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrOperatorNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding operator by email: %v", err)
+		return nil, err
+	}
+
+	return operator, nil
+}
+
+func (repository *OperatorRepository) Count(ctx context.Context) (int, error) {
+	var count int
+
+	err := repository.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM operators`).Scan(&count)
+	if err != nil {
+		log.Printf("Error counting operators: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
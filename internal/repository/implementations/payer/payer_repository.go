@@ -0,0 +1,448 @@
+package payer_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/encryption"
+)
+
+type PayerRepository struct {
+	db     *sql.DB
+	cipher *encryption.FieldCipher
+}
+
+func NewPayerRepository(db *sql.DB, cipher *encryption.FieldCipher) *PayerRepository {
+	return &PayerRepository{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+type sealedPayer struct {
+	payer      domain.Payer
+	envelope   *encryption.Envelope
+	taxIDIndex string
+	emailIndex string
+}
+
+func (repository *PayerRepository) seal(payer *domain.Payer) (*sealedPayer, error) {
+	sealed := &sealedPayer{
+		payer:      *payer,
+		taxIDIndex: repository.cipher.BlindIndex(payer.TaxID),
+		emailIndex: repository.cipher.BlindIndex(payer.Email),
+	}
+
+	envelope, err := repository.cipher.NewEnvelope(payer.ID)
+	if err != nil {
+		log.Printf("Error creating encryption envelope for payer %s: %v", payer.ID, err)
+		return nil, err
+	}
+
+	if err := envelope.EncryptFields(sealed.payer.SensitiveFields()); err != nil {
+		log.Printf("Error encrypting payer %s: %v", payer.ID, err)
+		return nil, err
+	}
+
+	sealed.envelope = envelope
+
+	return sealed, nil
+}
+
+func (repository *PayerRepository) Save(ctx context.Context, payer *domain.Payer) error {
+	sealed, err := repository.seal(payer)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.db.ExecContext(ctx, `
+		INSERT INTO payers (id, account_id, name, tax_id, email, phone, address, number, district, city, state, zip_code,
+			key_id, wrapped_key, tax_id_index, email_index, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+	`,
+		sealed.payer.ID,
+		sealed.payer.AccountID,
+		sealed.payer.Name,
+		sealed.payer.TaxID,
+		sealed.payer.Email,
+		sealed.payer.Phone,
+		sealed.payer.Address,
+		sealed.payer.Number,
+		sealed.payer.District,
+		sealed.payer.City,
+		sealed.payer.State,
+		sealed.payer.ZipCode,
+		sealed.envelope.KeyID,
+		sealed.envelope.WrappedKey,
+		sealed.taxIDIndex,
+		sealed.emailIndex,
+		sealed.payer.CreatedAt,
+		sealed.payer.UpdatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving payer %s: %v", payer.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (repository *PayerRepository) Update(ctx context.Context, payer *domain.Payer) error {
+	sealed, err := repository.seal(payer)
+	if err != nil {
+		return err
+	}
+
+	result, err := repository.db.ExecContext(ctx, `
+		UPDATE payers
+		SET name = $1, tax_id = $2, email = $3, phone = $4, address = $5, number = $6, district = $7, city = $8,
+			state = $9, zip_code = $10, key_id = $11, wrapped_key = $12, tax_id_index = $13, email_index = $14,
+			updated_at = $15, deleted_at = $16
+		WHERE id = $17
+	`,
+		sealed.payer.Name,
+		sealed.payer.TaxID,
+		sealed.payer.Email,
+		sealed.payer.Phone,
+		sealed.payer.Address,
+		sealed.payer.Number,
+		sealed.payer.District,
+		sealed.payer.City,
+		sealed.payer.State,
+		sealed.payer.ZipCode,
+		sealed.envelope.KeyID,
+		sealed.envelope.WrappedKey,
+		sealed.taxIDIndex,
+		sealed.emailIndex,
+		sealed.payer.UpdatedAt,
+		nullTime(sealed.payer.DeletedAt),
+		sealed.payer.ID,
+	)
+
+	if err != nil {
+		log.Printf("Error updating payer %s: %v", payer.ID, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCustomerNotFound
+	}
+
+	return nil
+}
+
+const selectPayer = `
+	SELECT id, COALESCE(account_id::text, ''), name, tax_id, email, phone, address, number, district, city, state, zip_code,
+		COALESCE(key_id, ''), COALESCE(wrapped_key, ''), created_at, updated_at, deleted_at
+	FROM payers
+`
+
+func (repository *PayerRepository) scanPayer(row interface{ Scan(dest ...any) error }) (*domain.Payer, error) {
+	var payer domain.Payer
+	var keyID, wrappedKey string
+	var deletedAt sql.NullTime
+
+	err := row.Scan(
+		&payer.ID,
+		&payer.AccountID,
+		&payer.Name,
+		&payer.TaxID,
+		&payer.Email,
+		&payer.Phone,
+		&payer.Address,
+		&payer.Number,
+		&payer.District,
+		&payer.City,
+		&payer.State,
+		&payer.ZipCode,
+		&keyID,
+		&wrappedKey,
+		&payer.CreatedAt,
+		&payer.UpdatedAt,
+		&deletedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deletedAt.Valid {
+		payer.DeletedAt = deletedAt.Time
+	}
+
+	if keyID != "" {
+		envelope, err := repository.cipher.OpenEnvelope(payer.ID, keyID, wrappedKey)
+		if err != nil {
+			log.Printf("Error opening encryption envelope for payer %s: %v", payer.ID, err)
+			return nil, err
+		}
+
+		if err := envelope.DecryptFields(payer.SensitiveFields()); err != nil {
+			log.Printf("Error decrypting payer %s: %v", payer.ID, err)
+			return nil, err
+		}
+	}
+
+	return &payer, nil
+}
+
+func (repository *PayerRepository) FindByID(ctx context.Context, id string) (*domain.Payer, error) {
+	payer, err := repository.scanPayer(repository.db.QueryRowContext(ctx, selectPayer+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCustomerNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding payer %s: %v", id, err)
+		return nil, err
+	}
+
+	return payer, nil
+}
+
+func (repository *PayerRepository) FindByTaxID(ctx context.Context, accountID, taxID string) (*domain.Payer, error) {
+	payer, err := repository.scanPayer(repository.db.QueryRowContext(ctx, selectPayer+`
+		WHERE account_id = $1 AND tax_id_index = $2 AND deleted_at IS NULL
+		ORDER BY created_at
+		LIMIT 1
+	`, accountID, repository.cipher.BlindIndex(taxID)))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCustomerNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding payer by tax id for account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	return payer, nil
+}
+
+func (repository *PayerRepository) Search(ctx context.Context, filter domain.CustomerFilter) ([]*domain.Payer, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, selectPayer+`
+		WHERE account_id = $1
+			AND ($2 = '' OR tax_id_index = $2)
+			AND ($3 = '' OR email_index = $3)
+			AND ($4 = '' OR id::text = $4 OR name ILIKE '%' || $4 || '%')
+			AND ($5 OR deleted_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $6
+	`,
+		filter.AccountID,
+		repository.cipher.BlindIndex(filter.TaxID),
+		repository.cipher.BlindIndex(filter.Email),
+		filter.Query,
+		filter.IncludeDeleted,
+		limit,
+	)
+
+	if err != nil {
+		log.Printf("Error searching payers: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var payers []*domain.Payer
+	for rows.Next() {
+		payer, err := repository.scanPayer(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		payers = append(payers, payer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payers, nil
+}
+
+func (repository *PayerRepository) Totals(ctx context.Context, id string) (*domain.CustomerTotals, error) {
+	var totals domain.CustomerTotals
+	var lastInvoiceAt sql.NullTime
+
+	err := repository.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(amount), 0), COALESCE(SUM(amount) FILTER (WHERE status = $2), 0), MAX(created_at)
+		FROM invoices
+		WHERE customer_id = $1
+	`, id, domain.StatusApproved).Scan(&totals.InvoiceCount, &totals.TotalAmount, &totals.ApprovedAmount, &lastInvoiceAt)
+
+	if err != nil {
+		log.Printf("Error computing totals for payer %s: %v", id, err)
+		return nil, err
+	}
+
+	if lastInvoiceAt.Valid {
+		totals.LastInvoiceAt = lastInvoiceAt.Time
+	}
+
+	return &totals, nil
+}
+
+// ReencryptBatch moves up to limit payers onto the current key. Rows written
+// before encryption existed are sealed for the first time; rows under an older
+// key only get their data key rewrapped, and rows sealed before the email
+// index existed get it filled in.
+func (repository *PayerRepository) ReencryptBatch(ctx context.Context, limit int) (int, error) {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, tax_id, email, phone, address, number, district, city, state, zip_code,
+			COALESCE(key_id, ''), COALESCE(wrapped_key, '')
+		FROM payers
+		WHERE key_id IS NULL OR key_id <> $1 OR email_index IS NULL
+		ORDER BY created_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, repository.cipher.CurrentKeyID(), limit)
+
+	if err != nil {
+		log.Printf("Error selecting payers to re-encrypt: %v", err)
+		return 0, err
+	}
+
+	type stalePayer struct {
+		payer      domain.Payer
+		keyID      string
+		wrappedKey string
+	}
+
+	var stale []stalePayer
+	for rows.Next() {
+		var item stalePayer
+
+		err := rows.Scan(
+			&item.payer.ID,
+			&item.payer.TaxID,
+			&item.payer.Email,
+			&item.payer.Phone,
+			&item.payer.Address,
+			&item.payer.Number,
+			&item.payer.District,
+			&item.payer.City,
+			&item.payer.State,
+			&item.payer.ZipCode,
+			&item.keyID,
+			&item.wrappedKey,
+		)
+
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		stale = append(stale, item)
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, item := range stale {
+		payer := item.payer
+		var envelope *encryption.Envelope
+
+		if item.keyID == "" {
+			taxIDIndex := repository.cipher.BlindIndex(payer.TaxID)
+			emailIndex := repository.cipher.BlindIndex(payer.Email)
+
+			envelope, err = repository.cipher.NewEnvelope(payer.ID)
+			if err != nil {
+				return 0, err
+			}
+
+			if err := envelope.EncryptFields(payer.SensitiveFields()); err != nil {
+				return 0, err
+			}
+
+			_, err = tx.ExecContext(ctx, `
+				UPDATE payers
+				SET tax_id = $1, email = $2, phone = $3, address = $4, number = $5, district = $6, city = $7,
+					state = $8, zip_code = $9, key_id = $10, wrapped_key = $11, tax_id_index = $12, email_index = $13
+				WHERE id = $14
+			`,
+				payer.TaxID,
+				payer.Email,
+				payer.Phone,
+				payer.Address,
+				payer.Number,
+				payer.District,
+				payer.City,
+				payer.State,
+				payer.ZipCode,
+				envelope.KeyID,
+				envelope.WrappedKey,
+				taxIDIndex,
+				emailIndex,
+				payer.ID,
+			)
+		} else {
+			envelope, err = repository.cipher.OpenEnvelope(payer.ID, item.keyID, item.wrappedKey)
+			if err != nil {
+				log.Printf("Error opening envelope for payer %s with key %s: %v", payer.ID, item.keyID, err)
+				return 0, err
+			}
+
+			var email string
+			email, err = envelope.Decrypt("email", payer.Email)
+			if err != nil {
+				log.Printf("Error decrypting email for payer %s: %v", payer.ID, err)
+				return 0, err
+			}
+
+			envelope, err = repository.cipher.Rewrap(envelope)
+			if err != nil {
+				return 0, err
+			}
+
+			_, err = tx.ExecContext(ctx, `
+				UPDATE payers
+				SET key_id = $1, wrapped_key = $2, email_index = $3
+				WHERE id = $4
+			`, envelope.KeyID, envelope.WrappedKey, repository.cipher.BlindIndex(email), payer.ID)
+		}
+
+		if err != nil {
+			log.Printf("Error re-encrypting payer %s: %v", payer.ID, err)
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(stale), nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,253 @@
+package payment_method_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type PaymentMethodRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentMethodRepository(db *sql.DB) *PaymentMethodRepository {
+	return &PaymentMethodRepository{
+		db: db,
+	}
+}
+
+func (repository *PaymentMethodRepository) Save(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	_, err := repository.db.ExecContext(ctx, `
+		INSERT INTO payment_methods (id, account_id, customer_id, type, vault_token, brand, last4, expiry_month, expiry_year,
+			cardholder_name, is_default, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`,
+		method.ID,
+		method.AccountID,
+		method.CustomerID,
+		method.Type,
+		nullString(method.VaultToken),
+		method.Brand,
+		method.Last4,
+		method.ExpiryMonth,
+		method.ExpiryYear,
+		method.CardholderName,
+		method.IsDefault,
+		method.Status,
+		method.CreatedAt,
+		method.UpdatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving payment method %s: %v", method.ID, err)
+	}
+
+	return err
+}
+
+func (repository *PaymentMethodRepository) Update(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	result, err := repository.db.ExecContext(ctx, `
+		UPDATE payment_methods
+		SET vault_token = $1, is_default = $2, status = $3, updated_at = $4, deleted_at = $5
+		WHERE id = $6
+	`, nullString(method.VaultToken), method.IsDefault, method.Status, method.UpdatedAt, nullTime(method.DeletedAt), method.ID)
+
+	if err != nil {
+		log.Printf("Error updating payment method %s: %v", method.ID, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrPaymentMethodNotFound
+	}
+
+	return nil
+}
+
+// SetDefault clears the customer's current default and marks method in the
+// same transaction, keeping the one-default-per-customer index satisfied.
+func (repository *PaymentMethodRepository) SetDefault(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE payment_methods
+		SET is_default = FALSE, updated_at = $1
+		WHERE customer_id = $2 AND is_default AND id <> $3
+	`, method.UpdatedAt, method.CustomerID, method.ID)
+
+	if err != nil {
+		log.Printf("Error clearing default payment method for customer %s: %v", method.CustomerID, err)
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE payment_methods
+		SET is_default = TRUE, updated_at = $1
+		WHERE id = $2
+	`, method.UpdatedAt, method.ID)
+
+	if err != nil {
+		log.Printf("Error setting default payment method %s: %v", method.ID, err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const selectPaymentMethod = `
+	SELECT id, account_id, customer_id, type, COALESCE(vault_token, ''), brand, last4, expiry_month, expiry_year,
+		cardholder_name, is_default, status, created_at, updated_at, deleted_at
+	FROM payment_methods
+`
+
+func scanPaymentMethod(row interface{ Scan(dest ...any) error }) (*domain.SavedPaymentMethod, error) {
+	var method domain.SavedPaymentMethod
+	var deletedAt sql.NullTime
+
+	err := row.Scan(
+		&method.ID,
+		&method.AccountID,
+		&method.CustomerID,
+		&method.Type,
+		&method.VaultToken,
+		&method.Brand,
+		&method.Last4,
+		&method.ExpiryMonth,
+		&method.ExpiryYear,
+		&method.CardholderName,
+		&method.IsDefault,
+		&method.Status,
+		&method.CreatedAt,
+		&method.UpdatedAt,
+		&deletedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deletedAt.Valid {
+		method.DeletedAt = deletedAt.Time
+	}
+
+	return &method, nil
+}
+
+func (repository *PaymentMethodRepository) queryPaymentMethods(ctx context.Context, query string, args ...any) ([]*domain.SavedPaymentMethod, error) {
+	rows, err := repository.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var methods []*domain.SavedPaymentMethod
+	for rows.Next() {
+		method, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		methods = append(methods, method)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return methods, nil
+}
+
+func (repository *PaymentMethodRepository) FindByID(ctx context.Context, id string) (*domain.SavedPaymentMethod, error) {
+	method, err := scanPaymentMethod(repository.db.QueryRowContext(ctx, selectPaymentMethod+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPaymentMethodNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding payment method %s: %v", id, err)
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (repository *PaymentMethodRepository) FindByCustomerID(ctx context.Context, customerID string) ([]*domain.SavedPaymentMethod, error) {
+	methods, err := repository.queryPaymentMethods(ctx, selectPaymentMethod+`
+		WHERE customer_id = $1 AND deleted_at IS NULL
+		ORDER BY is_default DESC, created_at DESC
+	`, customerID)
+
+	if err != nil {
+		log.Printf("Error listing payment methods for customer %s: %v", customerID, err)
+	}
+
+	return methods, err
+}
+
+func (repository *PaymentMethodRepository) FindDefault(ctx context.Context, customerID string) (*domain.SavedPaymentMethod, error) {
+	method, err := scanPaymentMethod(repository.db.QueryRowContext(ctx, selectPaymentMethod+`
+		WHERE customer_id = $1 AND is_default AND deleted_at IS NULL
+	`, customerID))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNoDefaultPaymentMethod
+	}
+
+	if err != nil {
+		log.Printf("Error finding default payment method for customer %s: %v", customerID, err)
+		return nil, err
+	}
+
+	return method, nil
+}
+
+// ExpireCards flags every active card whose expiry month ended before now and
+// returns the rows it changed.
+func (repository *PaymentMethodRepository) ExpireCards(ctx context.Context, now time.Time) ([]*domain.SavedPaymentMethod, error) {
+	methods, err := repository.queryPaymentMethods(ctx, `
+		UPDATE payment_methods
+		SET status = $1, is_default = FALSE, updated_at = $2
+		WHERE type = $3 AND status = $4 AND deleted_at IS NULL
+			AND (expiry_year < $5 OR (expiry_year = $5 AND expiry_month < $6))
+		RETURNING id, account_id, customer_id, type, COALESCE(vault_token, ''), brand, last4, expiry_month, expiry_year,
+			cardholder_name, is_default, status, created_at, updated_at, deleted_at
+	`,
+		domain.PaymentMethodStatusExpired,
+		now,
+		domain.PaymentMethodCard,
+		domain.PaymentMethodStatusActive,
+		now.Year(),
+		int(now.Month()),
+	)
+
+	if err != nil {
+		log.Printf("Error expiring cards: %v", err)
+	}
+
+	return methods, err
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
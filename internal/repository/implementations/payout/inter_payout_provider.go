@@ -0,0 +1,118 @@
+package payout_repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/inter"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/taxid"
+)
+
+// InterPayoutProvider sends payouts through Inter's pagamento-pix API, which
+// covers both Pix keys and bank accounts (by ISPB).
+type InterPayoutProvider struct {
+	client *inter.Client
+}
+
+func NewInterPayoutProvider(client *inter.Client) *InterPayoutProvider {
+	return &InterPayoutProvider{
+		client: client,
+	}
+}
+
+type interPixPaymentResponse struct {
+	TipoRetorno       string `json:"tipoRetorno"`
+	CodigoSolicitacao string `json:"codigoSolicitacao"`
+}
+
+type interPixStatusResponse struct {
+	TransacaoPix struct {
+		Status string `json:"status"`
+	} `json:"transacaoPix"`
+}
+
+var interAccountTypes = map[domain.BankAccountType]string{
+	domain.BankAccountChecking: "CONTA_CORRENTE",
+	domain.BankAccountSavings:  "CONTA_POUPANCA",
+	domain.BankAccountPayment:  "CONTA_PAGAMENTO",
+}
+
+func (provider *InterPayoutProvider) Transfer(ctx context.Context, payout *domain.Payout, destination *domain.PayoutDestination) (*domain.PayoutResult, error) {
+	recipient := map[string]any{"tipo": "CHAVE", "chave": destination.PixKey}
+	if destination.Type == domain.PayoutDestinationBankAccount {
+		recipient = map[string]any{
+			"tipo":          "DADOS_BANCARIOS",
+			"nome":          destination.HolderName,
+			"cpfCnpj":       taxid.Normalize(destination.HolderTaxID),
+			"tipoConta":     interAccountTypes[destination.AccountType],
+			"agencia":       destination.Branch,
+			"contaCorrente": destination.AccountNumber,
+			"instituicaoFinanceira": map[string]any{
+				"ispb": destination.BankISPB,
+			},
+		}
+	}
+
+	payload := map[string]any{
+		"valor":        fmt.Sprintf("%.2f", payout.Amount),
+		"descricao":    payout.Description,
+		"destinatario": recipient,
+	}
+
+	// The payout id doubles as the idempotency key, so a retried transfer is
+	// never paid twice.
+	status, body, err := provider.client.Do(ctx, "POST", "/banking/v2/pix", payload, map[string]string{"x-id-idempotente": payout.ID})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrProviderUnavailable, err)
+	}
+
+	if status >= 500 {
+		return nil, fmt.Errorf("%w: status %d", domain.ErrProviderUnavailable, status)
+	}
+
+	if status >= 400 {
+		log.Printf("[InterPayoutProvider] Transfer for payout %s refused (status %d): %s", payout.ID, status, string(body))
+
+		return &domain.PayoutResult{Status: domain.PayoutStatusFailed, FailureReason: fmt.Sprintf("refused by provider (status %d)", status)}, nil
+	}
+
+	// Inter accepted the transfer but its answer cannot be read, so the
+	// outcome is unknown: the payout stays processing and the retry is
+	// deduplicated by the idempotency key.
+	var response interPixPaymentResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		log.Printf("[InterPayoutProvider] Error decoding transfer response for payout %s: %v", payout.ID, err)
+
+		return nil, fmt.Errorf("%w: %v", domain.ErrProviderUnavailable, err)
+	}
+
+	return &domain.PayoutResult{ProviderTransferID: response.CodigoSolicitacao, Status: domain.PayoutStatusProcessing}, nil
+}
+
+func (provider *InterPayoutProvider) Check(ctx context.Context, payout *domain.Payout) (*domain.PayoutResult, error) {
+	status, body, err := provider.client.Do(ctx, "GET", "/banking/v2/pix/"+payout.ProviderTransferID, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrProviderUnavailable, err)
+	}
+
+	if status >= 400 {
+		return nil, fmt.Errorf("%w: status %d", domain.ErrProviderUnavailable, status)
+	}
+
+	var response interPixStatusResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	switch response.TransacaoPix.Status {
+	case "PAGO", "EFETIVADO":
+		return &domain.PayoutResult{Status: domain.PayoutStatusPaid}, nil
+	case "FALHA", "CANCELADO", "DEVOLVIDO", "EXPIRADO":
+		return &domain.PayoutResult{Status: domain.PayoutStatusFailed, FailureReason: "provider reported " + response.TransacaoPix.Status}, nil
+	default:
+		return &domain.PayoutResult{Status: domain.PayoutStatusProcessing}, nil
+	}
+}
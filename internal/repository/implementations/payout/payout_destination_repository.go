@@ -0,0 +1,191 @@
+package payout_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/encryption"
+)
+
+type PayoutDestinationRepository struct {
+	db     *sql.DB
+	cipher *encryption.FieldCipher
+}
+
+func NewPayoutDestinationRepository(db *sql.DB, cipher *encryption.FieldCipher) *PayoutDestinationRepository {
+	return &PayoutDestinationRepository{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+func (repository *PayoutDestinationRepository) Save(ctx context.Context, destination *domain.PayoutDestination) error {
+	sealed := *destination
+
+	envelope, err := repository.cipher.NewEnvelope(destination.ID)
+	if err != nil {
+		log.Printf("Error creating encryption envelope for payout destination %s: %v", destination.ID, err)
+		return err
+	}
+
+	if err := envelope.EncryptFields(sealed.SensitiveFields()); err != nil {
+		log.Printf("Error encrypting payout destination %s: %v", destination.ID, err)
+		return err
+	}
+
+	_, err = repository.db.ExecContext(ctx, `
+		INSERT INTO payout_destinations (id, account_id, type, holder_name, holder_tax_id, pix_key_type, pix_key, bank_ispb,
+			branch, account_number, account_type, key_id, wrapped_key, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`,
+		sealed.ID,
+		sealed.AccountID,
+		sealed.Type,
+		sealed.HolderName,
+		sealed.HolderTaxID,
+		sealed.PixKeyType,
+		sealed.PixKey,
+		sealed.BankISPB,
+		sealed.Branch,
+		sealed.AccountNumber,
+		sealed.AccountType,
+		envelope.KeyID,
+		envelope.WrappedKey,
+		sealed.CreatedAt,
+		sealed.UpdatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving payout destination %s: %v", destination.ID, err)
+	}
+
+	return err
+}
+
+func (repository *PayoutDestinationRepository) Update(ctx context.Context, destination *domain.PayoutDestination) error {
+	result, err := repository.db.ExecContext(ctx, `
+		UPDATE payout_destinations
+		SET updated_at = $1, deleted_at = $2
+		WHERE id = $3
+	`, destination.UpdatedAt, nullTime(destination.DeletedAt), destination.ID)
+
+	if err != nil {
+		log.Printf("Error updating payout destination %s: %v", destination.ID, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrPayoutDestinationNotFound
+	}
+
+	return nil
+}
+
+const selectPayoutDestination = `
+	SELECT id, account_id, type, holder_name, holder_tax_id, pix_key_type, pix_key, bank_ispb, branch, account_number,
+		account_type, key_id, wrapped_key, created_at, updated_at, deleted_at
+	FROM payout_destinations
+`
+
+func (repository *PayoutDestinationRepository) scan(row interface{ Scan(dest ...any) error }) (*domain.PayoutDestination, error) {
+	var destination domain.PayoutDestination
+	var keyID, wrappedKey string
+	var deletedAt sql.NullTime
+
+	err := row.Scan(
+		&destination.ID,
+		&destination.AccountID,
+		&destination.Type,
+		&destination.HolderName,
+		&destination.HolderTaxID,
+		&destination.PixKeyType,
+		&destination.PixKey,
+		&destination.BankISPB,
+		&destination.Branch,
+		&destination.AccountNumber,
+		&destination.AccountType,
+		&keyID,
+		&wrappedKey,
+		&destination.CreatedAt,
+		&destination.UpdatedAt,
+		&deletedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	destination.DeletedAt = deletedAt.Time
+
+	envelope, err := repository.cipher.OpenEnvelope(destination.ID, keyID, wrappedKey)
+	if err != nil {
+		log.Printf("Error opening encryption envelope for payout destination %s: %v", destination.ID, err)
+		return nil, err
+	}
+
+	if err := envelope.DecryptFields(destination.SensitiveFields()); err != nil {
+		log.Printf("Error decrypting payout destination %s: %v", destination.ID, err)
+		return nil, err
+	}
+
+	return &destination, nil
+}
+
+func (repository *PayoutDestinationRepository) FindByID(ctx context.Context, id string) (*domain.PayoutDestination, error) {
+	destination, err := repository.scan(repository.db.QueryRowContext(ctx, selectPayoutDestination+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPayoutDestinationNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding payout destination %s: %v", id, err)
+		return nil, err
+	}
+
+	return destination, nil
+}
+
+func (repository *PayoutDestinationRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.PayoutDestination, error) {
+	rows, err := repository.db.QueryContext(ctx, selectPayoutDestination+`
+		WHERE account_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`, accountID)
+
+	if err != nil {
+		log.Printf("Error listing payout destinations for account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var destinations []*domain.PayoutDestination
+	for rows.Next() {
+		destination, err := repository.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		destinations = append(destinations, destination)
+	}
+
+	return destinations, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
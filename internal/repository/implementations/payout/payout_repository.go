@@ -0,0 +1,185 @@
+package payout_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type PayoutRepository struct {
+	db *sql.DB
+}
+
+func NewPayoutRepository(db *sql.DB) *PayoutRepository {
+	return &PayoutRepository{
+		db: db,
+	}
+}
+
+func (repository *PayoutRepository) Save(ctx context.Context, payout *domain.Payout) error {
+	_, err := repository.db.ExecContext(ctx, `
+		INSERT INTO payouts (id, account_id, destination_id, amount, description, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		payout.ID,
+		payout.AccountID,
+		payout.DestinationID,
+		payout.Amount,
+		payout.Description,
+		payout.Status,
+		payout.CreatedAt,
+		payout.UpdatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving payout %s: %v", payout.ID, err)
+	}
+
+	return err
+}
+
+// UpdateIfStatus persists the payout only while it is still in status from,
+// so a transition is applied once even when the job and a request race.
+func (repository *PayoutRepository) UpdateIfStatus(ctx context.Context, payout *domain.Payout, from domain.PayoutStatus) (bool, error) {
+	result, err := repository.db.ExecContext(ctx, `
+		UPDATE payouts
+		SET status = $1, provider_transfer_id = $2, failure_reason = $3, updated_at = $4, paid_at = $5, failed_at = $6
+		WHERE id = $7 AND status = $8
+	`,
+		payout.Status,
+		nullString(payout.ProviderTransferID),
+		nullString(payout.FailureReason),
+		payout.UpdatedAt,
+		nullTime(payout.PaidAt),
+		nullTime(payout.FailedAt),
+		payout.ID,
+		from,
+	)
+
+	if err != nil {
+		log.Printf("Error updating payout %s: %v", payout.ID, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+const selectPayout = `
+	SELECT id, account_id, destination_id, amount, description, status, COALESCE(provider_transfer_id, ''),
+		COALESCE(failure_reason, ''), created_at, updated_at, paid_at, failed_at
+	FROM payouts
+`
+
+func scanPayout(row interface{ Scan(dest ...any) error }) (*domain.Payout, error) {
+	var payout domain.Payout
+	var paidAt, failedAt sql.NullTime
+
+	err := row.Scan(
+		&payout.ID,
+		&payout.AccountID,
+		&payout.DestinationID,
+		&payout.Amount,
+		&payout.Description,
+		&payout.Status,
+		&payout.ProviderTransferID,
+		&payout.FailureReason,
+		&payout.CreatedAt,
+		&payout.UpdatedAt,
+		&paidAt,
+		&failedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	payout.PaidAt = paidAt.Time
+	payout.FailedAt = failedAt.Time
+
+	return &payout, nil
+}
+
+func (repository *PayoutRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Payout, error) {
+	rows, err := repository.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var payouts []*domain.Payout
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		payouts = append(payouts, payout)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
+
+func (repository *PayoutRepository) FindByID(ctx context.Context, id string) (*domain.Payout, error) {
+	payout, err := scanPayout(repository.db.QueryRowContext(ctx, selectPayout+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPayoutNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding payout %s: %v", id, err)
+		return nil, err
+	}
+
+	return payout, nil
+}
+
+func (repository *PayoutRepository) Search(ctx context.Context, filter domain.PayoutFilter) ([]*domain.Payout, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	payouts, err := repository.query(ctx, selectPayout+`
+		WHERE account_id = $1
+			AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, filter.AccountID, string(filter.Status), limit)
+
+	if err != nil {
+		log.Printf("Error searching payouts: %v", err)
+	}
+
+	return payouts, err
+}
+
+// FindProcessing returns payouts waiting on the provider, plus pending ones
+// whose transfer request never got an answer.
+func (repository *PayoutRepository) FindProcessing(ctx context.Context, limit int) ([]*domain.Payout, error) {
+	payouts, err := repository.query(ctx, selectPayout+`
+		WHERE status = $1 OR (status = $2 AND updated_at < NOW() - INTERVAL '15 minutes')
+		ORDER BY updated_at
+		LIMIT $3
+	`, domain.PayoutStatusProcessing, domain.PayoutStatusPending, limit)
+
+	if err != nil {
+		log.Printf("Error finding processing payouts: %v", err)
+	}
+
+	return payouts, err
+}
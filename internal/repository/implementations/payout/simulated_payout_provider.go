@@ -0,0 +1,29 @@
+package payout_repository
+
+import (
+	"context"
+	"math"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+// SimulatedPayoutProvider stands in for the bank outside production. Every
+// transfer is accepted and completes on the next check, except amounts
+// ending in 13 cents, which fail so the refund path can be exercised.
+type SimulatedPayoutProvider struct{}
+
+func NewSimulatedPayoutProvider() *SimulatedPayoutProvider {
+	return &SimulatedPayoutProvider{}
+}
+
+func (provider *SimulatedPayoutProvider) Transfer(ctx context.Context, payout *domain.Payout, destination *domain.PayoutDestination) (*domain.PayoutResult, error) {
+	return &domain.PayoutResult{ProviderTransferID: "sim_" + payout.ID, Status: domain.PayoutStatusProcessing}, nil
+}
+
+func (provider *SimulatedPayoutProvider) Check(ctx context.Context, payout *domain.Payout) (*domain.PayoutResult, error) {
+	if int(math.Round(payout.Amount*100))%100 == 13 {
+		return &domain.PayoutResult{Status: domain.PayoutStatusFailed, FailureReason: "simulated failure"}, nil
+	}
+
+	return &domain.PayoutResult{Status: domain.PayoutStatusPaid}, nil
+}
@@ -0,0 +1,151 @@
+package plan_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type PlanRepository struct {
+	db *sql.DB
+}
+
+func NewPlanRepository(db *sql.DB) *PlanRepository {
+	return &PlanRepository{
+		db: db,
+	}
+}
+
+func (repository *PlanRepository) Save(ctx context.Context, plan *domain.Plan) error {
+	_, err := repository.db.ExecContext(ctx, `
+		INSERT INTO plans (id, account_id, name, amount, interval, interval_count, trial_days, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		plan.ID,
+		plan.AccountID,
+		plan.Name,
+		plan.Amount,
+		plan.Interval,
+		plan.IntervalCount,
+		plan.TrialDays,
+		plan.CreatedAt,
+		plan.UpdatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving plan %s: %v", plan.ID, err)
+	}
+
+	return err
+}
+
+func (repository *PlanRepository) Update(ctx context.Context, plan *domain.Plan) error {
+	result, err := repository.db.ExecContext(ctx, `
+		UPDATE plans
+		SET name = $1, updated_at = $2, archived_at = $3
+		WHERE id = $4
+	`, plan.Name, plan.UpdatedAt, nullTime(plan.ArchivedAt), plan.ID)
+
+	if err != nil {
+		log.Printf("Error updating plan %s: %v", plan.ID, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrPlanNotFound
+	}
+
+	return nil
+}
+
+const selectPlan = `
+	SELECT id, account_id, name, amount, interval, interval_count, trial_days, created_at, updated_at, archived_at
+	FROM plans
+`
+
+func scanPlan(row interface{ Scan(dest ...any) error }) (*domain.Plan, error) {
+	var plan domain.Plan
+	var archivedAt sql.NullTime
+
+	err := row.Scan(
+		&plan.ID,
+		&plan.AccountID,
+		&plan.Name,
+		&plan.Amount,
+		&plan.Interval,
+		&plan.IntervalCount,
+		&plan.TrialDays,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+		&archivedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if archivedAt.Valid {
+		plan.ArchivedAt = archivedAt.Time
+	}
+
+	return &plan, nil
+}
+
+func (repository *PlanRepository) FindByID(ctx context.Context, id string) (*domain.Plan, error) {
+	plan, err := scanPlan(repository.db.QueryRowContext(ctx, selectPlan+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPlanNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding plan %s: %v", id, err)
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (repository *PlanRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.Plan, error) {
+	rows, err := repository.db.QueryContext(ctx, selectPlan+`
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+
+	if err != nil {
+		log.Printf("Error listing plans for account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var plans []*domain.Plan
+	for rows.Next() {
+		plan, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		plans = append(plans, plan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
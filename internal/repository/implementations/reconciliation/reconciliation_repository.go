@@ -0,0 +1,203 @@
+package reconciliation_repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/lib/pq"
+)
+
+type ReconciliationRepository struct {
+	db *sql.DB
+}
+
+func NewReconciliationRepository(db *sql.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		db: db,
+	}
+}
+
+func (repository *ReconciliationRepository) Save(ctx context.Context, reconciliation *domain.Reconciliation) error {
+	items, err := json.Marshal(reconciliation.Items)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.db.ExecContext(ctx, `
+		INSERT INTO reconciliations (id, period_from, period_to, matched, unmatched, mismatched, not_settled, missing, items, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		reconciliation.ID,
+		reconciliation.From,
+		reconciliation.To,
+		reconciliation.Summary.Matched,
+		reconciliation.Summary.Unmatched,
+		reconciliation.Summary.Mismatched,
+		reconciliation.Summary.NotSettled,
+		reconciliation.Summary.Missing,
+		string(items),
+		reconciliation.CreatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving reconciliation %s: %v", reconciliation.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+const selectReconciliation = `
+	SELECT id, period_from, period_to, matched, unmatched, mismatched, not_settled, missing, items, created_at
+	FROM reconciliations
+`
+
+func scanReconciliation(row interface{ Scan(dest ...any) error }) (*domain.Reconciliation, error) {
+	var reconciliation domain.Reconciliation
+	var items []byte
+
+	err := row.Scan(
+		&reconciliation.ID,
+		&reconciliation.From,
+		&reconciliation.To,
+		&reconciliation.Summary.Matched,
+		&reconciliation.Summary.Unmatched,
+		&reconciliation.Summary.Mismatched,
+		&reconciliation.Summary.NotSettled,
+		&reconciliation.Summary.Missing,
+		&items,
+		&reconciliation.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(items, &reconciliation.Items); err != nil {
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+func (repository *ReconciliationRepository) FindByID(ctx context.Context, id string) (*domain.Reconciliation, error) {
+	reconciliation, err := scanReconciliation(repository.db.QueryRowContext(ctx, selectReconciliation+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrReconciliationNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding reconciliation %s: %v", id, err)
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// Search lists reconciliations overlapping the filter's period, newest
+// first.
+func (repository *ReconciliationRepository) Search(ctx context.Context, filter domain.ReconciliationFilter) ([]*domain.Reconciliation, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, selectReconciliation+`
+		WHERE ($1::date IS NULL OR period_to >= $1)
+			AND ($2::date IS NULL OR period_from <= $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, nullTime(filter.From), nullTime(filter.To), limit)
+
+	if err != nil {
+		log.Printf("Error searching reconciliations: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var reconciliations []*domain.Reconciliation
+	for rows.Next() {
+		reconciliation, err := scanReconciliation(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reconciliations = append(reconciliations, reconciliation)
+	}
+
+	return reconciliations, rows.Err()
+}
+
+const selectReconciliationInvoice = `
+	SELECT id, account_id, COALESCE(reference, ''), COALESCE(provider_charge_id, ''), status, paid_amount, paid_at
+	FROM invoices
+`
+
+// FindPaidInvoices lists the invoices charged through the bank that were
+// paid between from and to.
+func (repository *ReconciliationRepository) FindPaidInvoices(ctx context.Context, from, to time.Time) ([]*domain.ReconciliationInvoice, error) {
+	rows, err := repository.db.QueryContext(ctx, selectReconciliationInvoice+`
+		WHERE provider_charge_id IS NOT NULL
+			AND paid_at >= $1 AND paid_at < $2
+	`, from, to)
+
+	if err != nil {
+		log.Printf("Error finding paid invoices to reconcile: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanReconciliationInvoices(rows)
+}
+
+// FindInvoicesByIdentifiers looks invoices up by reference or provider
+// charge id, whatever their status.
+func (repository *ReconciliationRepository) FindInvoicesByIdentifiers(ctx context.Context, references, chargeIDs []string) ([]*domain.ReconciliationInvoice, error) {
+	if len(references) == 0 && len(chargeIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := repository.db.QueryContext(ctx, selectReconciliationInvoice+`
+		WHERE deleted_at IS NULL
+			AND (reference = ANY($1) OR provider_charge_id = ANY($2))
+	`, pq.Array(references), pq.Array(chargeIDs))
+
+	if err != nil {
+		log.Printf("Error finding invoices to reconcile: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanReconciliationInvoices(rows)
+}
+
+func scanReconciliationInvoices(rows *sql.Rows) ([]*domain.ReconciliationInvoice, error) {
+	var invoices []*domain.ReconciliationInvoice
+	for rows.Next() {
+		var invoice domain.ReconciliationInvoice
+		var paidAt sql.NullTime
+
+		if err := rows.Scan(&invoice.ID, &invoice.AccountID, &invoice.Reference, &invoice.ProviderChargeID, &invoice.Status, &invoice.PaidAmount, &paidAt); err != nil {
+			return nil, err
+		}
+
+		invoice.PaidAt = paidAt.Time
+		invoices = append(invoices, &invoice)
+	}
+
+	return invoices, rows.Err()
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,199 @@
+package refund_repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	balance_transaction_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_transaction"
+)
+
+type RefundRepository struct {
+	db *sql.DB
+}
+
+func NewRefundRepository(db *sql.DB) *RefundRepository {
+	return &RefundRepository{
+		db: db,
+	}
+}
+
+// Save records the refund on the invoice and takes each reversal back from
+// its account in one transaction. Money still waiting to settle for the
+// invoice is taken first, the rest comes out of the available balance; if
+// any account can't cover its part nothing is refunded. The amount under an
+// open or lost dispute is already held for the card network, so it can't be
+// refunded as well.
+func (repository *RefundRepository) Save(ctx context.Context, refund *domain.Refund, invoice *domain.Invoice) error {
+	reversals, err := json.Marshal(refund.Reversals)
+	if err != nil {
+		return err
+	}
+
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for refund %s: %v", refund.ID, err)
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// Disputes lock the invoice before they are opened, so once the lock is
+	// held the disputed amount below can't change under the refund.
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM invoices WHERE id = $1 FOR UPDATE`, invoice.ID); err != nil {
+		log.Printf("Error locking invoice %s for refund: %v", invoice.ID, err)
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE invoices
+		SET refunded_amount = refunded_amount + $1, status = $2, updated_at = $3
+		WHERE id = $4 AND status = $5
+			AND refunded_amount + $1 <= paid_amount - (
+				SELECT COALESCE(SUM(amount), 0)
+				FROM disputes
+				WHERE invoice_id = $4 AND status <> $6
+			)
+	`, refund.Amount, invoice.Status, invoice.UpdatedAt, invoice.ID, domain.StatusApproved, domain.DisputeStatusWon)
+
+	if err != nil {
+		log.Printf("Error refunding invoice %s: %v", invoice.ID, err)
+		return err
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return domain.ErrInvalidRefundAmount
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO refunds (id, invoice_id, account_id, amount, reason, reversals, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, refund.ID, refund.InvoiceID, refund.AccountID, refund.Amount, refund.Reason, string(reversals), refund.CreatedAt)
+
+	if err != nil {
+		log.Printf("Error saving refund %s: %v", refund.ID, err)
+		return err
+	}
+
+	for _, reversal := range refund.Reversals {
+		if err := reverse(ctx, tx, refund, reversal); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func reverse(ctx context.Context, tx *sql.Tx, refund *domain.Refund, reversal domain.SplitReversal) error {
+	if reversal.Amount <= 0 {
+		return nil
+	}
+
+	invoiceID := refund.InvoiceID
+
+	var settlementID string
+	var scheduled float64
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, amount
+		FROM settlements
+		WHERE invoice_id = $1 AND account_id = $2 AND status = $3
+		FOR UPDATE
+	`, invoiceID, reversal.AccountID, domain.SettlementStatusScheduled).Scan(&settlementID, &scheduled)
+
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error locking settlement of invoice %s for account %s: %v", invoiceID, reversal.AccountID, err)
+		return err
+	}
+
+	fromPending := min(reversal.Amount, scheduled)
+	if fromPending > 0 {
+		if _, err := tx.ExecContext(ctx, `UPDATE settlements SET amount = amount - $1 WHERE id = $2`, fromPending, settlementID); err != nil {
+			log.Printf("Error reducing settlement %s: %v", settlementID, err)
+			return err
+		}
+	}
+
+	fromAvailable := reversal.Amount - fromPending
+
+	var balance float64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE accounts
+		SET pending_balance = pending_balance - $1, balance = balance - $2, updated_at = $3
+		WHERE id = $4 AND balance - $2 >= 0
+		RETURNING balance
+	`, fromPending, fromAvailable, time.Now(), reversal.AccountID).Scan(&balance)
+
+	if err == sql.ErrNoRows {
+		return domain.ErrInsufficientBalance
+	}
+
+	if err != nil {
+		log.Printf("Error reversing %.2f from account %s: %v", reversal.Amount, reversal.AccountID, err)
+		return err
+	}
+
+	// The ledger follows the available balance, which a refund taken from
+	// pending never touches. The part of the payment refunded before it
+	// settled is entered as a charge so the statement shows the charge and the
+	// whole refund, and the running balance still adds up.
+	var transactions []*domain.BalanceTransaction
+	if fromPending > 0 {
+		charge := domain.NewBalanceTransaction(domain.BalanceTransactionCharge, "invoice", invoiceID, "Invoice payment refunded before settlement")
+		charge.Amount = fromPending
+		charge.BalanceAfter = balance + fromAvailable + fromPending
+
+		transactions = append(transactions, charge)
+	}
+
+	debit := domain.NewBalanceTransaction(domain.BalanceTransactionRefund, "refund", refund.ID, refund.Reason)
+	debit.Amount = -reversal.Amount
+	debit.BalanceAfter = balance
+
+	for _, transaction := range append(transactions, debit) {
+		transaction.AccountID = reversal.AccountID
+
+		if err := balance_transaction_repository.Record(ctx, tx, transaction); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repository *RefundRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.Refund, error) {
+	rows, err := repository.db.QueryContext(ctx, `
+		SELECT id, invoice_id, account_id, amount, reason, reversals, created_at
+		FROM refunds
+		WHERE invoice_id = $1
+		ORDER BY created_at
+	`, invoiceID)
+
+	if err != nil {
+		log.Printf("Error listing refunds of invoice %s: %v", invoiceID, err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var refunds []*domain.Refund
+	for rows.Next() {
+		var refund domain.Refund
+		var reversals []byte
+
+		if err := rows.Scan(&refund.ID, &refund.InvoiceID, &refund.AccountID, &refund.Amount, &refund.Reason, &reversals, &refund.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(reversals, &refund.Reversals); err != nil {
+			return nil, err
+		}
+
+		refunds = append(refunds, &refund)
+	}
+
+	return refunds, rows.Err()
+}
@@ -0,0 +1,294 @@
+package settlement_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	balance_hold_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_hold"
+	balance_transaction_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_transaction"
+)
+
+type SettlementRepository struct {
+	db *sql.DB
+}
+
+func NewSettlementRepository(db *sql.DB) *SettlementRepository {
+	return &SettlementRepository{
+		db: db,
+	}
+}
+
+// Save schedules the settlements of an invoice, adds their amounts to each
+// account's pending balance, puts any rolling reserve in its held balance and
+// credits the fee to the fee account, all in one transaction.
+func (repository *SettlementRepository) Save(ctx context.Context, settlements []*domain.Settlement) error {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for settlements: %v", err)
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, settlement := range settlements {
+		if err := Insert(ctx, tx, settlement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Insert saves a settlement and credits its accounts within tx, so callers
+// can schedule settlements in the same transaction as the invoice.
+func Insert(ctx context.Context, tx *sql.Tx, settlement *domain.Settlement) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO settlements (id, account_id, invoice_id, payment_method, amount, fee, fee_account_id, status, available_on, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		settlement.ID,
+		settlement.AccountID,
+		settlement.InvoiceID,
+		settlement.PaymentMethod,
+		settlement.Amount,
+		settlement.Fee,
+		nullString(settlement.FeeAccountID),
+		settlement.Status,
+		settlement.AvailableOn,
+		settlement.CreatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving settlement %s: %v", settlement.ID, err)
+		return err
+	}
+
+	var held float64
+	if settlement.Hold != nil {
+		held = settlement.Hold.Amount
+
+		if err := balance_hold_repository.Insert(ctx, tx, settlement.Hold); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE accounts
+		SET pending_balance = pending_balance + $1, held_balance = held_balance + $2, updated_at = $3
+		WHERE id = $4
+	`, settlement.Amount, held, time.Now(), settlement.AccountID)
+
+	if err != nil {
+		log.Printf("Error crediting pending balance for account %s: %v", settlement.AccountID, err)
+		return err
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return domain.ErrAccountNotFound
+	}
+
+	if settlement.Fee > 0 {
+		var balance float64
+		err := tx.QueryRowContext(ctx, `
+			UPDATE accounts
+			SET balance = balance + $1, updated_at = $2
+			WHERE id = $3
+			RETURNING balance
+		`, settlement.Fee, time.Now(), settlement.FeeAccountID).Scan(&balance)
+
+		if err == sql.ErrNoRows {
+			return domain.ErrAccountNotFound
+		}
+
+		if err != nil {
+			log.Printf("Error crediting fee of settlement %s to account %s: %v", settlement.ID, settlement.FeeAccountID, err)
+			return err
+		}
+
+		revenue := domain.NewBalanceTransaction(domain.BalanceTransactionFeeRevenue, "invoice", settlement.InvoiceID, "Fee on invoice payment")
+		revenue.AccountID = settlement.FeeAccountID
+		revenue.Amount = settlement.Fee
+		revenue.BalanceAfter = balance
+
+		if err := balance_transaction_repository.Record(ctx, tx, revenue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Release moves a scheduled settlement from the pending to the available
+// balance. It reports false when the settlement was already released. The
+// amount is read back from the row, since a refund may have reduced it.
+func (repository *SettlementRepository) Release(ctx context.Context, settlement *domain.Settlement) (bool, error) {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for settlement %s: %v", settlement.ID, err)
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE settlements
+		SET status = $1, released_at = $2
+		WHERE id = $3 AND status = $4
+		RETURNING amount
+	`, settlement.Status, settlement.ReleasedAt, settlement.ID, domain.SettlementStatusScheduled).Scan(&settlement.Amount)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		log.Printf("Error releasing settlement %s: %v", settlement.ID, err)
+		return false, err
+	}
+
+	var balance float64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE accounts
+		SET pending_balance = pending_balance - $1, balance = balance + $1, updated_at = $2
+		WHERE id = $3
+		RETURNING balance
+	`, settlement.Amount, time.Now(), settlement.AccountID).Scan(&balance)
+
+	if err != nil {
+		log.Printf("Error releasing pending balance for account %s: %v", settlement.AccountID, err)
+		return false, err
+	}
+
+	if err := recordRelease(ctx, tx, settlement, balance); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// recordRelease writes a released settlement to the ledger as the gross
+// credit followed by the fee taken from it, ending at balance.
+func recordRelease(ctx context.Context, tx *sql.Tx, settlement *domain.Settlement, balance float64) error {
+	charge := domain.NewBalanceTransaction(domain.BalanceTransactionCharge, "invoice", settlement.InvoiceID, "Invoice payment")
+	charge.Amount = settlement.Amount + settlement.Fee
+	charge.BalanceAfter = balance + settlement.Fee
+
+	fee := domain.NewBalanceTransaction(domain.BalanceTransactionFee, "invoice", settlement.InvoiceID, "Processing fee")
+	fee.Amount = -settlement.Fee
+	fee.BalanceAfter = balance
+
+	for _, transaction := range []*domain.BalanceTransaction{charge, fee} {
+		if transaction.Amount == 0 {
+			continue
+		}
+
+		transaction.AccountID = settlement.AccountID
+
+		if err := balance_transaction_repository.Record(ctx, tx, transaction); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const selectSettlement = `
+	SELECT id, account_id, invoice_id, payment_method, amount, fee, COALESCE(fee_account_id::text, ''), status, available_on, created_at, released_at
+	FROM settlements
+`
+
+func scanSettlement(row interface{ Scan(dest ...any) error }) (*domain.Settlement, error) {
+	var settlement domain.Settlement
+	var releasedAt sql.NullTime
+
+	err := row.Scan(
+		&settlement.ID,
+		&settlement.AccountID,
+		&settlement.InvoiceID,
+		&settlement.PaymentMethod,
+		&settlement.Amount,
+		&settlement.Fee,
+		&settlement.FeeAccountID,
+		&settlement.Status,
+		&settlement.AvailableOn,
+		&settlement.CreatedAt,
+		&releasedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	settlement.ReleasedAt = releasedAt.Time
+
+	return &settlement, nil
+}
+
+func (repository *SettlementRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.Settlement, error) {
+	rows, err := repository.db.QueryContext(ctx, selectSettlement+`
+		WHERE status = $1 AND available_on <= $2
+		ORDER BY available_on
+		LIMIT $3
+	`, domain.SettlementStatusScheduled, now, limit)
+
+	if err != nil {
+		log.Printf("Error finding due settlements: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var settlements []*domain.Settlement
+	for rows.Next() {
+		settlement, err := scanSettlement(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements, rows.Err()
+}
+
+// Upcoming sums the scheduled settlements of an account per release date.
+func (repository *SettlementRepository) Upcoming(ctx context.Context, accountID string, limit int) ([]domain.UpcomingSettlement, error) {
+	rows, err := repository.db.QueryContext(ctx, `
+		SELECT available_on, SUM(amount)
+		FROM settlements
+		WHERE account_id = $1 AND status = $2
+		GROUP BY available_on
+		ORDER BY available_on
+		LIMIT $3
+	`, accountID, domain.SettlementStatusScheduled, limit)
+
+	if err != nil {
+		log.Printf("Error listing upcoming settlements for account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var upcoming []domain.UpcomingSettlement
+	for rows.Next() {
+		var settlement domain.UpcomingSettlement
+		if err := rows.Scan(&settlement.Date, &settlement.Amount); err != nil {
+			return nil, err
+		}
+
+		upcoming = append(upcoming, settlement)
+	}
+
+	return upcoming, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
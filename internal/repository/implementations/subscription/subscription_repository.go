@@ -0,0 +1,272 @@
+package subscription_repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type SubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepository(db *sql.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{
+		db: db,
+	}
+}
+
+func (repository *SubscriptionRepository) Save(ctx context.Context, subscription *domain.Subscription) error {
+	_, err := repository.db.ExecContext(ctx, `
+		INSERT INTO subscriptions (id, account_id, customer_id, payment_method_id, plan_id, status, current_period_start,
+			current_period_end, next_billing_at, trial_end, cancel_at_period_end, pending_proration, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`,
+		subscription.ID,
+		subscription.AccountID,
+		subscription.CustomerID,
+		nullString(subscription.PaymentMethodID),
+		subscription.PlanID,
+		subscription.Status,
+		nullTime(subscription.CurrentPeriodStart),
+		nullTime(subscription.CurrentPeriodEnd),
+		subscription.NextBillingAt,
+		nullTime(subscription.TrialEnd),
+		subscription.CancelAtPeriodEnd,
+		subscription.PendingProration,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+
+	if err != nil {
+		log.Printf("Error saving subscription %s: %v", subscription.ID, err)
+	}
+
+	return err
+}
+
+func (repository *SubscriptionRepository) Update(ctx context.Context, subscription *domain.Subscription) error {
+	_, err := repository.update(ctx, subscription, "")
+	return err
+}
+
+// UpdateIfDue writes the subscription only while its stored next_billing_at
+// still equals billedAt, so two schedulers can't bill the same period.
+func (repository *SubscriptionRepository) UpdateIfDue(ctx context.Context, subscription *domain.Subscription, billedAt time.Time) (bool, error) {
+	return repository.update(ctx, subscription, " AND next_billing_at = $20", billedAt)
+}
+
+// UpdateIfRetryDue is the dunning counterpart of UpdateIfDue, keyed on
+// next_retry_at.
+func (repository *SubscriptionRepository) UpdateIfRetryDue(ctx context.Context, subscription *domain.Subscription, retryAt time.Time) (bool, error) {
+	return repository.update(ctx, subscription, " AND next_retry_at = $20", retryAt)
+}
+
+func (repository *SubscriptionRepository) update(ctx context.Context, subscription *domain.Subscription, condition string, extra ...any) (bool, error) {
+	args := []any{
+		nullString(subscription.PaymentMethodID),
+		subscription.PlanID,
+		subscription.Status,
+		nullTime(subscription.CurrentPeriodStart),
+		nullTime(subscription.CurrentPeriodEnd),
+		subscription.NextBillingAt,
+		nullTime(subscription.TrialEnd),
+		subscription.CancelAtPeriodEnd,
+		subscription.PendingProration,
+		nullString(subscription.LatestInvoiceID),
+		subscription.UpdatedAt,
+		nullTime(subscription.PausedAt),
+		nullTime(subscription.CancelledAt),
+		nullString(string(subscription.DunningStep)),
+		subscription.DunningAttempts,
+		subscription.DunningAmount,
+		nullTime(subscription.DunningStartedAt),
+		nullTime(subscription.NextRetryAt),
+		subscription.ID,
+	}
+	args = append(args, extra...)
+
+	result, err := repository.db.ExecContext(ctx, `
+		UPDATE subscriptions
+		SET payment_method_id = $1, plan_id = $2, status = $3, current_period_start = $4, current_period_end = $5,
+			next_billing_at = $6, trial_end = $7, cancel_at_period_end = $8, pending_proration = $9,
+			latest_invoice_id = $10, updated_at = $11, paused_at = $12, cancelled_at = $13, dunning_step = $14,
+			dunning_attempts = $15, dunning_amount = $16, dunning_started_at = $17, next_retry_at = $18
+		WHERE id = $19`+condition, args...)
+
+	if err != nil {
+		log.Printf("Error updating subscription %s: %v", subscription.ID, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected == 0 && condition == "" {
+		return false, domain.ErrSubscriptionNotFound
+	}
+
+	return rowsAffected > 0, nil
+}
+
+const selectSubscription = `
+	SELECT id, account_id, customer_id, COALESCE(payment_method_id::text, ''), plan_id, status, current_period_start,
+		current_period_end, next_billing_at, trial_end, cancel_at_period_end, pending_proration,
+		COALESCE(latest_invoice_id::text, ''), created_at, updated_at, paused_at, cancelled_at,
+		COALESCE(dunning_step, ''), dunning_attempts, dunning_amount, dunning_started_at, next_retry_at
+	FROM subscriptions
+`
+
+func scanSubscription(row interface{ Scan(dest ...any) error }) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	var currentPeriodStart, currentPeriodEnd, trialEnd, pausedAt, cancelledAt, dunningStartedAt, nextRetryAt sql.NullTime
+
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.AccountID,
+		&subscription.CustomerID,
+		&subscription.PaymentMethodID,
+		&subscription.PlanID,
+		&subscription.Status,
+		&currentPeriodStart,
+		&currentPeriodEnd,
+		&subscription.NextBillingAt,
+		&trialEnd,
+		&subscription.CancelAtPeriodEnd,
+		&subscription.PendingProration,
+		&subscription.LatestInvoiceID,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+		&pausedAt,
+		&cancelledAt,
+		&subscription.DunningStep,
+		&subscription.DunningAttempts,
+		&subscription.DunningAmount,
+		&dunningStartedAt,
+		&nextRetryAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.CurrentPeriodStart = currentPeriodStart.Time
+	subscription.CurrentPeriodEnd = currentPeriodEnd.Time
+	subscription.TrialEnd = trialEnd.Time
+	subscription.PausedAt = pausedAt.Time
+	subscription.CancelledAt = cancelledAt.Time
+	subscription.DunningStartedAt = dunningStartedAt.Time
+	subscription.NextRetryAt = nextRetryAt.Time
+
+	return &subscription, nil
+}
+
+func (repository *SubscriptionRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Subscription, error) {
+	rows, err := repository.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var subscriptions []*domain.Subscription
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (repository *SubscriptionRepository) FindByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	subscription, err := scanSubscription(repository.db.QueryRowContext(ctx, selectSubscription+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding subscription %s: %v", id, err)
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (repository *SubscriptionRepository) Search(ctx context.Context, filter domain.SubscriptionFilter) ([]*domain.Subscription, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	subscriptions, err := repository.query(ctx, selectSubscription+`
+		WHERE account_id = $1
+			AND ($2 = '' OR customer_id::text = $2)
+			AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`, filter.AccountID, filter.CustomerID, string(filter.Status), limit)
+
+	if err != nil {
+		log.Printf("Error searching subscriptions: %v", err)
+	}
+
+	return subscriptions, err
+}
+
+func (repository *SubscriptionRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error) {
+	subscriptions, err := repository.query(ctx, selectSubscription+`
+		WHERE status IN ($1, $2, $3) AND next_billing_at <= $4
+		ORDER BY next_billing_at
+		LIMIT $5
+	`,
+		domain.SubscriptionStatusTrialing,
+		domain.SubscriptionStatusActive,
+		domain.SubscriptionStatusPastDue,
+		now,
+		limit,
+	)
+
+	if err != nil {
+		log.Printf("Error finding due subscriptions: %v", err)
+	}
+
+	return subscriptions, err
+}
+
+func (repository *SubscriptionRepository) FindRetryDue(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error) {
+	subscriptions, err := repository.query(ctx, selectSubscription+`
+		WHERE status = $1 AND next_retry_at <= $2
+		ORDER BY next_retry_at
+		LIMIT $3
+	`, domain.SubscriptionStatusPastDue, now, limit)
+
+	if err != nil {
+		log.Printf("Error finding subscriptions due for a retry: %v", err)
+	}
+
+	return subscriptions, err
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,295 @@
+package transfer_repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	balance_transaction_repository "github.com/NewLeonardooliv/gateway-payment/internal/repository/implementations/balance_transaction"
+	"github.com/lib/pq"
+)
+
+type TransferRepository struct {
+	db *sql.DB
+}
+
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{
+		db: db,
+	}
+}
+
+// Save records the transfer and moves its amount in one transaction. A
+// reused idempotency key fails with ErrIdempotencyKeyReused so the caller can
+// look up the original transfer.
+func (repository *TransferRepository) Save(ctx context.Context, transfer *domain.Transfer) error {
+	metadata, err := json.Marshal(transfer.Metadata)
+	if err != nil {
+		return err
+	}
+
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for transfer %s: %v", transfer.ID, err)
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO transfers (id, source_account_id, destination_account_id, amount, description, metadata, idempotency_key, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		transfer.ID,
+		transfer.SourceAccountID,
+		transfer.DestinationAccountID,
+		transfer.Amount,
+		transfer.Description,
+		string(metadata),
+		nullString(transfer.IdempotencyKey),
+		transfer.Status,
+		transfer.CreatedAt,
+	)
+
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return domain.ErrIdempotencyKeyReused
+	}
+
+	if err != nil {
+		log.Printf("Error saving transfer %s: %v", transfer.ID, err)
+		return err
+	}
+
+	if err := move(ctx, tx, transfer.SourceAccountID, transfer.DestinationAccountID, transfer.Amount, transfer.ID, transfer.Description); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Reverse marks the transfer as reversed and moves the amount back, as long
+// as the destination still has it available. It reports false when the
+// transfer was already reversed.
+func (repository *TransferRepository) Reverse(ctx context.Context, transfer *domain.Transfer) (bool, error) {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction for transfer %s: %v", transfer.ID, err)
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE transfers
+		SET status = $1, reversal_reason = $2, reversed_at = $3
+		WHERE id = $4 AND status = $5
+	`, transfer.Status, transfer.ReversalReason, transfer.ReversedAt, transfer.ID, domain.TransferStatusSucceeded)
+
+	if err != nil {
+		log.Printf("Error reversing transfer %s: %v", transfer.ID, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	if err := move(ctx, tx, transfer.DestinationAccountID, transfer.SourceAccountID, transfer.Amount, transfer.ID, transfer.ReversalReason); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// move debits from and credits to under row locks, taken in id order so two
+// opposite transfers can't deadlock, and writes the matching debit and credit
+// entries.
+func move(ctx context.Context, tx *sql.Tx, from, to string, amount float64, transferID, description string) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, balance
+		FROM accounts
+		WHERE id IN ($1, $2)
+		ORDER BY id
+		FOR UPDATE
+	`, from, to)
+
+	if err != nil {
+		log.Printf("Error locking accounts %s and %s: %v", from, to, err)
+		return err
+	}
+
+	balances := map[string]float64{}
+	for rows.Next() {
+		var id string
+		var balance float64
+		if err := rows.Scan(&id, &balance); err != nil {
+			rows.Close()
+			return err
+		}
+
+		balances[id] = balance
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(balances) != 2 {
+		return domain.ErrAccountNotFound
+	}
+
+	if balances[from] < amount {
+		return domain.ErrInsufficientBalance
+	}
+
+	for _, entry := range []struct {
+		accountID string
+		amount    float64
+	}{
+		{from, -amount},
+		{to, amount},
+	} {
+		var balance float64
+		err := tx.QueryRowContext(ctx, `
+			UPDATE accounts
+			SET balance = balance + $1, updated_at = $2
+			WHERE id = $3
+			RETURNING balance
+		`, entry.amount, time.Now(), entry.accountID).Scan(&balance)
+
+		if err != nil {
+			log.Printf("Error updating balance of account %s: %v", entry.accountID, err)
+			return err
+		}
+
+		transaction := domain.NewBalanceTransaction(domain.BalanceTransactionTransfer, "transfer", transferID, description)
+		transaction.AccountID = entry.accountID
+		transaction.Amount = entry.amount
+		transaction.BalanceAfter = balance
+
+		if err := balance_transaction_repository.Record(ctx, tx, transaction); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const selectTransfer = `
+	SELECT id, source_account_id, destination_account_id, amount, description, metadata, COALESCE(idempotency_key, ''),
+		status, COALESCE(reversal_reason, ''), created_at, reversed_at
+	FROM transfers
+`
+
+func scanTransfer(row interface{ Scan(dest ...any) error }) (*domain.Transfer, error) {
+	var transfer domain.Transfer
+	var metadata []byte
+	var reversedAt sql.NullTime
+
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.SourceAccountID,
+		&transfer.DestinationAccountID,
+		&transfer.Amount,
+		&transfer.Description,
+		&metadata,
+		&transfer.IdempotencyKey,
+		&transfer.Status,
+		&transfer.ReversalReason,
+		&transfer.CreatedAt,
+		&reversedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.ReversedAt = reversedAt.Time
+
+	if err := json.Unmarshal(metadata, &transfer.Metadata); err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func (repository *TransferRepository) FindByID(ctx context.Context, id string) (*domain.Transfer, error) {
+	transfer, err := scanTransfer(repository.db.QueryRowContext(ctx, selectTransfer+`
+		WHERE id = $1
+	`, id))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTransferNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding transfer %s: %v", id, err)
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (repository *TransferRepository) FindByIdempotencyKey(ctx context.Context, sourceAccountID, key string) (*domain.Transfer, error) {
+	transfer, err := scanTransfer(repository.db.QueryRowContext(ctx, selectTransfer+`
+		WHERE source_account_id = $1 AND idempotency_key = $2
+	`, sourceAccountID, key))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTransferNotFound
+	}
+
+	if err != nil {
+		log.Printf("Error finding transfer by idempotency key for account %s: %v", sourceAccountID, err)
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// Search lists the transfers an account sent or received.
+func (repository *TransferRepository) Search(ctx context.Context, filter domain.TransferFilter) ([]*domain.Transfer, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := repository.db.QueryContext(ctx, selectTransfer+`
+		WHERE (source_account_id = $1 OR destination_account_id = $1)
+			AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, filter.AccountID, string(filter.Status), limit)
+
+	if err != nil {
+		log.Printf("Error searching transfers: %v", err)
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var transfers []*domain.Transfer
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -1,17 +1,193 @@
 package repository
 
-import "github.com/NewLeonardooliv/gateway-payment/internal/domain"
+import (
+	"context"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
 
 type AccountRepository interface {
-	Save(account *domain.Account) error
-	FindByAPIKey(apiKey string) (*domain.Account, error)
-	FindByID(id string) (*domain.Account, error)
-	UpdateBalance(account *domain.Account) error
+	Save(ctx context.Context, account *domain.Account) error
+	FindByAPIKey(ctx context.Context, apiKey string) (*domain.Account, error)
+	FindByID(ctx context.Context, id string) (*domain.Account, error)
+	ApplyBalanceChange(ctx context.Context, account *domain.Account, change domain.BalanceChange, transaction *domain.BalanceTransaction) error
+	Update(ctx context.Context, account *domain.Account) error
+	FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Account, error)
+	Search(ctx context.Context, filter domain.AccountFilter) ([]*domain.Account, error)
 }
 
 type InvoiceRepository interface {
-	Save(invoice *domain.Invoice) error
-	FindByID(id string) (*domain.Invoice, error)
-	FindByAccountID(accountID string) ([]*domain.Invoice, error)
-	UpdateStatus(invoice *domain.Invoice) error
+	Save(ctx context.Context, invoice *domain.Invoice) error
+	FindByID(ctx context.Context, id string) (*domain.Invoice, error)
+	FindByAccountID(ctx context.Context, accountID string) ([]*domain.Invoice, error)
+	UpdateStatus(ctx context.Context, invoice *domain.Invoice) error
+	Search(ctx context.Context, filter domain.InvoiceFilter) ([]*domain.Invoice, error)
+	Create(ctx context.Context, invoice *domain.Invoice, settlements []*domain.Settlement) error
+	Cancel(ctx context.Context, invoice *domain.Invoice) error
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Invoice, error)
+	FindByProviderChargeID(ctx context.Context, chargeID string) (*domain.Invoice, error)
+	Settle(ctx context.Context, invoice *domain.Invoice) error
+	FindUnregistered(ctx context.Context, paymentType domain.PaymentMethod, limit int) ([]*domain.Invoice, error)
+	FindOpenForPeriod(ctx context.Context, subscriptionID string, periodStart time.Time) (*domain.Invoice, error)
+}
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, apiKey *domain.APIKey) error
+	FindByKey(ctx context.Context, key string) (*domain.APIKey, error)
+	FindByID(ctx context.Context, id string) (*domain.APIKey, error)
+	FindByAccountID(ctx context.Context, accountID string) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, apiKey *domain.APIKey) error
+	RevokeByAccountID(ctx context.Context, accountID string) error
+}
+
+type AuditRepository interface {
+	Save(ctx context.Context, entry *domain.AuditEntry) error
+	Search(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error)
+	ListAfter(ctx context.Context, seq int64, limit int) ([]*domain.AuditEntry, error)
+}
+
+type OperatorRepository interface {
+	Save(ctx context.Context, operator *domain.Operator) error
+	FindByID(ctx context.Context, id string) (*domain.Operator, error)
+	FindByEmail(ctx context.Context, email string) (*domain.Operator, error)
+	Count(ctx context.Context) (int, error)
+}
+
+type PayerRepository interface {
+	Save(ctx context.Context, payer *domain.Payer) error
+	Update(ctx context.Context, payer *domain.Payer) error
+	FindByID(ctx context.Context, id string) (*domain.Payer, error)
+	FindByTaxID(ctx context.Context, accountID, taxID string) (*domain.Payer, error)
+	Search(ctx context.Context, filter domain.CustomerFilter) ([]*domain.Payer, error)
+	Totals(ctx context.Context, id string) (*domain.CustomerTotals, error)
+	ReencryptBatch(ctx context.Context, limit int) (int, error)
+}
+
+type PaymentMethodRepository interface {
+	Save(ctx context.Context, method *domain.SavedPaymentMethod) error
+	Update(ctx context.Context, method *domain.SavedPaymentMethod) error
+	SetDefault(ctx context.Context, method *domain.SavedPaymentMethod) error
+	FindByID(ctx context.Context, id string) (*domain.SavedPaymentMethod, error)
+	FindByCustomerID(ctx context.Context, customerID string) ([]*domain.SavedPaymentMethod, error)
+	FindDefault(ctx context.Context, customerID string) (*domain.SavedPaymentMethod, error)
+	ExpireCards(ctx context.Context, now time.Time) ([]*domain.SavedPaymentMethod, error)
+}
+
+type CardVault interface {
+	Store(ctx context.Context, accountID string, card domain.CreditCard) (string, error)
+	Delete(ctx context.Context, token string) error
+}
+
+type PlanRepository interface {
+	Save(ctx context.Context, plan *domain.Plan) error
+	Update(ctx context.Context, plan *domain.Plan) error
+	FindByID(ctx context.Context, id string) (*domain.Plan, error)
+	FindByAccountID(ctx context.Context, accountID string) ([]*domain.Plan, error)
+}
+
+type SubscriptionRepository interface {
+	Save(ctx context.Context, subscription *domain.Subscription) error
+	Update(ctx context.Context, subscription *domain.Subscription) error
+	UpdateIfDue(ctx context.Context, subscription *domain.Subscription, billedAt time.Time) (bool, error)
+	UpdateIfRetryDue(ctx context.Context, subscription *domain.Subscription, retryAt time.Time) (bool, error)
+	FindByID(ctx context.Context, id string) (*domain.Subscription, error)
+	Search(ctx context.Context, filter domain.SubscriptionFilter) ([]*domain.Subscription, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error)
+	FindRetryDue(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error)
+}
+
+type EventRepository interface {
+	Save(ctx context.Context, event *domain.Event) error
+	Search(ctx context.Context, filter domain.EventFilter) ([]*domain.Event, error)
+}
+
+type PayoutDestinationRepository interface {
+	Save(ctx context.Context, destination *domain.PayoutDestination) error
+	Update(ctx context.Context, destination *domain.PayoutDestination) error
+	FindByID(ctx context.Context, id string) (*domain.PayoutDestination, error)
+	FindByAccountID(ctx context.Context, accountID string) ([]*domain.PayoutDestination, error)
+}
+
+type PayoutRepository interface {
+	Save(ctx context.Context, payout *domain.Payout) error
+	UpdateIfStatus(ctx context.Context, payout *domain.Payout, from domain.PayoutStatus) (bool, error)
+	FindByID(ctx context.Context, id string) (*domain.Payout, error)
+	Search(ctx context.Context, filter domain.PayoutFilter) ([]*domain.Payout, error)
+	FindProcessing(ctx context.Context, limit int) ([]*domain.Payout, error)
+}
+
+// PayoutProvider executes transfers out of the platform and reports on them.
+type PayoutProvider interface {
+	Transfer(ctx context.Context, payout *domain.Payout, destination *domain.PayoutDestination) (*domain.PayoutResult, error)
+	Check(ctx context.Context, payout *domain.Payout) (*domain.PayoutResult, error)
+}
+
+type SettlementRepository interface {
+	Save(ctx context.Context, settlements []*domain.Settlement) error
+	Release(ctx context.Context, settlement *domain.Settlement) (bool, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.Settlement, error)
+	Upcoming(ctx context.Context, accountID string, limit int) ([]domain.UpcomingSettlement, error)
+}
+
+type RefundRepository interface {
+	Save(ctx context.Context, refund *domain.Refund, invoice *domain.Invoice) error
+	FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.Refund, error)
+}
+
+type TransferRepository interface {
+	Save(ctx context.Context, transfer *domain.Transfer) error
+	Reverse(ctx context.Context, transfer *domain.Transfer) (bool, error)
+	FindByID(ctx context.Context, id string) (*domain.Transfer, error)
+	FindByIdempotencyKey(ctx context.Context, sourceAccountID, key string) (*domain.Transfer, error)
+	Search(ctx context.Context, filter domain.TransferFilter) ([]*domain.Transfer, error)
+}
+
+type BalanceTransactionRepository interface {
+	Search(ctx context.Context, filter domain.BalanceTransactionFilter) ([]*domain.BalanceTransaction, error)
+	ListPeriod(ctx context.Context, accountID string, from, to time.Time) ([]*domain.BalanceTransaction, error)
+	BalanceAt(ctx context.Context, accountID string, t time.Time) (float64, error)
+}
+
+type DisputeRepository interface {
+	Save(ctx context.Context, dispute *domain.Dispute) error
+	FindByID(ctx context.Context, id string) (*domain.Dispute, error)
+	Search(ctx context.Context, filter domain.DisputeFilter) ([]*domain.Dispute, error)
+	FindOverdue(ctx context.Context, now time.Time, limit int) ([]*domain.Dispute, error)
+	Submit(ctx context.Context, dispute *domain.Dispute) (bool, error)
+	Resolve(ctx context.Context, dispute *domain.Dispute) (bool, error)
+	SaveEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error
+	ListEvidence(ctx context.Context, disputeID string) ([]*domain.DisputeEvidence, error)
+	FindEvidence(ctx context.Context, disputeID, id string) (*domain.DisputeEvidence, error)
+}
+
+type BalanceHoldRepository interface {
+	Place(ctx context.Context, hold *domain.BalanceHold) error
+	Release(ctx context.Context, hold *domain.BalanceHold) (bool, error)
+	FindByID(ctx context.Context, id string) (*domain.BalanceHold, error)
+	Search(ctx context.Context, filter domain.BalanceHoldFilter) ([]*domain.BalanceHold, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.BalanceHold, error)
+}
+
+// BankStatementProvider reads the credits and debits of the bank account
+// that receives boleto and Pix charges.
+type BankStatementProvider interface {
+	Statement(ctx context.Context, from, to time.Time) ([]domain.BankStatementEntry, error)
+}
+
+type ReconciliationRepository interface {
+	Save(ctx context.Context, reconciliation *domain.Reconciliation) error
+	FindByID(ctx context.Context, id string) (*domain.Reconciliation, error)
+	Search(ctx context.Context, filter domain.ReconciliationFilter) ([]*domain.Reconciliation, error)
+	FindPaidInvoices(ctx context.Context, from, to time.Time) ([]*domain.ReconciliationInvoice, error)
+	FindInvoicesByIdentifiers(ctx context.Context, references, chargeIDs []string) ([]*domain.ReconciliationInvoice, error)
+}
+
+type CNABRepository interface {
+	NextRemittanceSequence(ctx context.Context) (int, error)
+	NextOurNumbers(ctx context.Context, count int) ([]int64, error)
+	SaveRemittance(ctx context.Context, file *domain.CNABFile) error
+	SaveReturn(ctx context.Context, file *domain.CNABFile) error
+	FindByID(ctx context.Context, id string) (*domain.CNABFile, error)
+	Search(ctx context.Context, filter domain.CNABFileFilter) ([]*domain.CNABFile, error)
 }
@@ -1,25 +1,39 @@
 package service
 
 import (
+	"context"
+	"strings"
+
 	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
 	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
 	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
 )
 
 type AccountService struct {
-	repository repository.AccountRepository
+	repository        repository.AccountRepository
+	apiKeyRepository  repository.APIKeyRepository
+	feeSchedule       domain.FeeSchedule
+	settlementService *SettlementService
+	auditService      *AuditService
 }
 
-func NewAccountService(repository repository.AccountRepository) *AccountService {
+func NewAccountService(repository repository.AccountRepository, apiKeyRepository repository.APIKeyRepository, feeSchedule domain.FeeSchedule, settlementService *SettlementService, auditService *AuditService) *AccountService {
 	return &AccountService{
-		repository: repository,
+		repository:        repository,
+		apiKeyRepository:  apiKeyRepository,
+		feeSchedule:       feeSchedule,
+		settlementService: settlementService,
+		auditService:      auditService,
 	}
 }
 
-func (service *AccountService) CreateAccount(input dto.CreateAccountInput) (*dto.AccountOutput, error) {
-	account := dto.ToAccount(input)
+func (service *AccountService) CreateAccount(ctx context.Context, input dto.CreateAccountInput) (*dto.AccountOutput, error) {
+	account, err := dto.ToAccount(input)
+	if err != nil {
+		return nil, err
+	}
 
-	existingAccount, err := service.repository.FindByAPIKey(account.APIKey)
+	existingAccount, err := service.repository.FindByAPIKey(ctx, account.APIKey)
 
 	if err != nil && err != domain.ErrAccountNotFound {
 		return nil, err
@@ -29,56 +43,342 @@ func (service *AccountService) CreateAccount(input dto.CreateAccountInput) (*dto
 		return nil, domain.ErrDuplicatedAPIKey
 	}
 
-	err = service.repository.Save(account)
+	err = service.repository.Save(ctx, account)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.apiKeyRepository.Save(ctx, domain.NewMasterAPIKey(account))
+
+	if err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "account.created", "account", account.ID, nil, account.Snapshot())
+
+	output := dto.FromAccount(account)
+
+	return &output, nil
+}
+
+// MoveBalance applies change to the account's available, pending and
+// reserved balances and records it in the audit log under action. Whatever
+// it does to the available balance is written to the ledger as transaction.
+func (service *AccountService) MoveBalance(ctx context.Context, accountID string, change domain.BalanceChange, action string, transaction *domain.BalanceTransaction) error {
+	account, err := service.repository.FindByIDIncludingDeleted(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	before := account.Snapshot()
+
+	if err := service.repository.ApplyBalanceChange(ctx, account, change, transaction); err != nil {
+		return err
+	}
+
+	service.auditService.Record(ctx, action, "account", account.ID, before, account.Snapshot())
+
+	return nil
+}
+
+func (service *AccountService) FindByID(ctx context.Context, id string) (*dto.AccountOutput, error) {
+	account, err := service.repository.FindByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming, err := service.settlementService.Upcoming(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromAccount(account)
+	output.APIKey = ""
+	output.UpcomingSettlements = dto.FromUpcomingSettlements(upcoming)
+	output.FeeSchedule = dto.FromFeeSchedule(service.feeSchedule.Merge(account.FeeSchedule))
+
+	return &output, nil
+}
+
+// FeeRuleFor is the account's price for method, falling back to the platform
+// default when the account has no price of its own.
+func (service *AccountService) FeeRuleFor(account *domain.Account, method domain.PaymentMethod) domain.FeeRule {
+	return service.feeSchedule.Merge(account.FeeSchedule)[method]
+}
+
+// SetFeeSchedule replaces the account's pricing plan. Invoices already issued
+// keep the price they were created with.
+func (service *AccountService) SetFeeSchedule(ctx context.Context, id string, input dto.UpdateFeeScheduleInput) (*dto.AccountOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	account, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := account.Snapshot()
+	account.SetFeeSchedule(dto.ToFeeSchedule(input.Fees))
+
+	if err := service.repository.Update(ctx, account); err != nil {
+		return nil, err
+	}
+
+	after := account.Snapshot()
+	after["reason"] = input.Reason
+
+	service.auditService.Record(ctx, "account.fee_schedule_updated", "account", account.ID, before, after)
+
+	output := dto.FromAccount(account)
+	output.APIKey = ""
+	output.FeeSchedule = dto.FromFeeSchedule(service.feeSchedule.Merge(account.FeeSchedule))
+
+	return &output, nil
+}
+
+// SetReservePolicy changes how much of each settlement the account has
+// withheld and for how long. It only applies to invoices paid from now on.
+func (service *AccountService) SetReservePolicy(ctx context.Context, id string, input dto.UpdateReservePolicyInput) (*dto.AccountOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	account, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := account.Snapshot()
+
+	if err := account.SetReservePolicy(domain.ReservePolicy{Percent: input.Percent, Days: input.Days}); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Update(ctx, account); err != nil {
+		return nil, err
+	}
+
+	after := account.Snapshot()
+	after["reason"] = input.Reason
+
+	service.auditService.Record(ctx, "account.reserve_policy_updated", "account", account.ID, before, after)
+
+	output := dto.FromAccount(account)
+	output.APIKey = ""
+
+	return &output, nil
+}
+
+func (service *AccountService) Update(ctx context.Context, id string, input dto.UpdateAccountInput) (*dto.AccountOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	account, err := service.repository.FindByID(ctx, id)
 
 	if err != nil {
 		return nil, err
 	}
 
+	before := account.Snapshot()
+	account.Update(input.Name, input.Email)
+
+	if input.InvoiceGraceDays != nil {
+		if err := account.SetInvoiceGraceDays(*input.InvoiceGraceDays); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.ChargeDefaults != nil {
+		account.SetChargeDefaults(dto.ToChargeDefaults(*input.ChargeDefaults))
+	}
+
+	if err := service.repository.Update(ctx, account); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "account.updated", "account", account.ID, before, account.Snapshot())
+
 	output := dto.FromAccount(account)
+	output.APIKey = ""
 
 	return &output, nil
 }
 
-func (service *AccountService) UpdateBalance(apiKey string, amount float64) (*dto.AccountOutput, error) {
-	account, err := service.repository.FindByAPIKey(apiKey)
+func (service *AccountService) Delete(ctx context.Context, id string) (*dto.AccountOutput, error) {
+	account, err := service.repository.FindByID(ctx, id)
 
 	if err != nil {
 		return nil, err
 	}
 
-	account.AddBalance(amount)
-	err = service.repository.UpdateBalance(account)
+	before := account.Snapshot()
+
+	if err := account.Delete(); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Update(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if err := service.apiKeyRepository.RevokeByAccountID(ctx, account.ID); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "account.deleted", "account", account.ID, before, account.Snapshot())
+
+	output := dto.FromAccount(account)
+	output.APIKey = ""
+
+	return &output, nil
+}
+
+func (service *AccountService) Restore(ctx context.Context, id string) (*dto.AccountOutput, error) {
+	account, err := service.repository.FindByIDIncludingDeleted(ctx, id)
 
 	if err != nil {
 		return nil, err
 	}
 
+	before := account.Snapshot()
+
+	if err := account.Restore(); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Update(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if err := service.apiKeyRepository.Save(ctx, domain.NewMasterAPIKey(account)); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "account.restored", "account", account.ID, before, account.Snapshot())
+
 	output := dto.FromAccount(account)
 
 	return &output, nil
 }
 
-func (service *AccountService) FindByAPIKey(apiKey string) (*dto.AccountOutput, error) {
-	account, err := service.repository.FindByAPIKey(apiKey)
+func (service *AccountService) Search(ctx context.Context, filter domain.AccountFilter) ([]dto.AccountOutput, error) {
+	accounts, err := service.repository.Search(ctx, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.AccountOutput, len(accounts))
+	for i, account := range accounts {
+		output[i] = dto.FromAccount(account)
+		output[i].APIKey = ""
+	}
+
+	return output, nil
+}
+
+func (service *AccountService) CheckActive(ctx context.Context, id string) error {
+	account, err := service.repository.FindByID(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if account.IsFrozen() {
+		return domain.ErrAccountFrozen
+	}
+
+	return nil
+}
+
+// Find returns the account itself, for services that depend on its settings.
+func (service *AccountService) Find(ctx context.Context, id string) (*domain.Account, error) {
+	return service.repository.FindByID(ctx, id)
+}
+
+func (service *AccountService) HasNegativeBalance(ctx context.Context, id string) (bool, error) {
+	account, err := service.repository.FindByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return account.HasNegativeBalance(), nil
+}
+
+func (service *AccountService) AdjustBalance(ctx context.Context, id string, input dto.BalanceAdjustmentInput) (*dto.AccountOutput, error) {
+	if strings.TrimSpace(input.Reason) == "" {
+		return nil, domain.ErrReasonRequired
+	}
+
+	if input.Amount == 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	account, err := service.repository.FindByIDIncludingDeleted(ctx, id)
 
 	if err != nil {
 		return nil, err
 	}
 
+	before := account.Snapshot()
+
+	transaction := domain.NewBalanceTransaction(domain.BalanceTransactionAdjustment, "", "", input.Reason)
+	if err := service.repository.ApplyBalanceChange(ctx, account, domain.BalanceChange{Available: input.Amount}, transaction); err != nil {
+		return nil, err
+	}
+
+	after := account.Snapshot()
+	after["adjustment"] = input.Amount
+	after["reason"] = input.Reason
+
+	service.auditService.Record(ctx, "account.balance_adjusted", "account", account.ID, before, after)
+
 	output := dto.FromAccount(account)
+	output.APIKey = ""
 
 	return &output, nil
 }
 
-func (service *AccountService) FindByID(id string) (*dto.AccountOutput, error) {
-	account, err := service.repository.FindByID(id)
+func (service *AccountService) Freeze(ctx context.Context, id string, input dto.FreezeAccountInput) (*dto.AccountOutput, error) {
+	return service.changeFreeze(ctx, id, input.Reason, "account.frozen", (*domain.Account).Freeze)
+}
+
+func (service *AccountService) Unfreeze(ctx context.Context, id string, input dto.FreezeAccountInput) (*dto.AccountOutput, error) {
+	return service.changeFreeze(ctx, id, input.Reason, "account.unfrozen", (*domain.Account).Unfreeze)
+}
+
+func (service *AccountService) changeFreeze(ctx context.Context, id, reason, action string, change func(*domain.Account) error) (*dto.AccountOutput, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, domain.ErrReasonRequired
+	}
+
+	account, err := service.repository.FindByID(ctx, id)
 
 	if err != nil {
 		return nil, err
 	}
 
+	before := account.Snapshot()
+
+	if err := change(account); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Update(ctx, account); err != nil {
+		return nil, err
+	}
+
+	after := account.Snapshot()
+	after["reason"] = reason
+
+	service.auditService.Record(ctx, action, "account", account.ID, before, after)
+
 	output := dto.FromAccount(account)
+	output.APIKey = ""
 
 	return &output, nil
 }
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+type APIKeyService struct {
+	repository   repository.APIKeyRepository
+	auditService *AuditService
+}
+
+func NewAPIKeyService(repository repository.APIKeyRepository, auditService *AuditService) *APIKeyService {
+	return &APIKeyService{
+		repository:   repository,
+		auditService: auditService,
+	}
+}
+
+func (service *APIKeyService) Authenticate(ctx context.Context, key string) (*domain.Principal, error) {
+	apiKey, err := service.repository.FindByKey(ctx, key)
+	if err == domain.ErrAPIKeyNotFound {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewPrincipal(apiKey), nil
+}
+
+func (service *APIKeyService) Create(ctx context.Context, accountID string, input dto.CreateAPIKeyInput) (*dto.APIKeyOutput, error) {
+	apiKey, err := dto.ToAPIKey(input, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Save(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "api_key.created", "api_key", apiKey.ID, nil, apiKey.Snapshot())
+
+	output := dto.FromAPIKey(apiKey)
+	output.Key = apiKey.Key
+
+	return &output, nil
+}
+
+func (service *APIKeyService) List(ctx context.Context, accountID string) ([]dto.APIKeyOutput, error) {
+	apiKeys, err := service.repository.FindByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.APIKeyOutput, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		output[i] = dto.FromAPIKey(apiKey)
+	}
+
+	return output, nil
+}
+
+func (service *APIKeyService) Revoke(ctx context.Context, accountID, id string) (*dto.APIKeyOutput, error) {
+	apiKey, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.AccountID != accountID {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	before := apiKey.Snapshot()
+
+	if err := apiKey.Revoke(); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Revoke(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "api_key.revoked", "api_key", apiKey.ID, before, apiKey.Snapshot())
+
+	output := dto.FromAPIKey(apiKey)
+
+	return &output, nil
+}
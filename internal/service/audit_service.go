@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+const auditVerifyBatchSize = 500
+
+type AuditService struct {
+	repository repository.AuditRepository
+}
+
+func NewAuditService(repository repository.AuditRepository) *AuditService {
+	return &AuditService{
+		repository: repository,
+	}
+}
+
+// Record runs after the change it describes is committed, so a failure is
+// logged rather than failing a request that already took effect.
+func (service *AuditService) Record(ctx context.Context, action, targetType, targetID string, before, after map[string]any) {
+	entry := domain.NewAuditEntry(
+		domain.ActorFromContext(ctx),
+		domain.RequestInfoFromContext(ctx),
+		action,
+		targetType,
+		targetID,
+		before,
+		after,
+	)
+
+	if err := service.repository.Save(ctx, entry); err != nil {
+		log.Printf("[AuditService] Error recording %s on %s %s: %v", action, targetType, targetID, err)
+	}
+}
+
+func (service *AuditService) Search(ctx context.Context, filter domain.AuditFilter) ([]dto.AuditEntryOutput, error) {
+	entries, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.AuditEntryOutput, len(entries))
+	for i, entry := range entries {
+		output[i] = dto.FromAuditEntry(entry)
+	}
+
+	return output, nil
+}
+
+func (service *AuditService) Verify(ctx context.Context) (*dto.AuditVerificationOutput, error) {
+	output := &dto.AuditVerificationOutput{Valid: true}
+
+	var lastSeq int64
+	var lastHash string
+
+	for {
+		entries, err := service.repository.ListAfter(ctx, lastSeq, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if (output.Checked > 0 && entry.PrevHash != lastHash) || entry.ComputeHash() != entry.Hash {
+				log.Printf("[AuditService] Audit chain broken at seq %d", entry.Seq)
+
+				output.Valid = false
+				output.BrokenAtSeq = entry.Seq
+				output.Error = domain.ErrAuditChainBroken.Error()
+
+				return output, nil
+			}
+
+			output.Checked++
+			lastSeq = entry.Seq
+			lastHash = entry.Hash
+		}
+
+		if len(entries) < auditVerifyBatchSize {
+			break
+		}
+	}
+
+	output.LastHash = lastHash
+
+	return output, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+const balanceHoldBatchSize = 500
+
+type BalanceHoldService struct {
+	repository     repository.BalanceHoldRepository
+	accountService AccountService
+	eventService   *EventService
+	auditService   *AuditService
+}
+
+func NewBalanceHoldService(repository repository.BalanceHoldRepository, accountService AccountService, eventService *EventService, auditService *AuditService) *BalanceHoldService {
+	return &BalanceHoldService{
+		repository:     repository,
+		accountService: accountService,
+		eventService:   eventService,
+		auditService:   auditService,
+	}
+}
+
+// Place holds part of the account's available balance on an operator's
+// request, keeping it out of payouts and transfers.
+func (service *BalanceHoldService) Place(ctx context.Context, accountID string, input dto.CreateBalanceHoldInput) (*dto.BalanceHoldOutput, error) {
+	hold, err := dto.ToBalanceHold(input, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := service.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Place(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "balance_hold.placed", "balance_hold", hold.ID, nil, hold.Snapshot())
+
+	if err := service.publish(ctx, hold, "balance_hold.placed"); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromBalanceHold(hold)
+
+	return &output, nil
+}
+
+// Release gives a hold back to the available balance before its release
+// date. Operators use it for manual holds and to free a reserve early.
+func (service *BalanceHoldService) Release(ctx context.Context, id string, input dto.ReleaseBalanceHoldInput) (*dto.BalanceHoldOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	hold, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.release(ctx, hold, input.Reason); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromBalanceHold(hold)
+
+	return &output, nil
+}
+
+func (service *BalanceHoldService) Search(ctx context.Context, filter domain.BalanceHoldFilter) ([]dto.BalanceHoldOutput, error) {
+	holds, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromBalanceHolds(holds), nil
+}
+
+func (service *BalanceHoldService) ReleaseDue(ctx context.Context) (int, error) {
+	holds, err := service.repository.FindDue(ctx, time.Now(), balanceHoldBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, hold := range holds {
+		if err := service.release(ctx, hold, "release date reached"); err != nil {
+			log.Printf("[BalanceHoldService] Error releasing hold %s: %v", hold.ID, err)
+			continue
+		}
+
+		released++
+	}
+
+	if released > 0 {
+		log.Printf("[BalanceHoldService] Released %d holds", released)
+	}
+
+	return released, nil
+}
+
+func (service *BalanceHoldService) release(ctx context.Context, hold *domain.BalanceHold, reason string) error {
+	before := hold.Snapshot()
+
+	if err := hold.Release(); err != nil {
+		return err
+	}
+
+	released, err := service.repository.Release(ctx, hold)
+	if err != nil {
+		return err
+	}
+
+	if !released {
+		return domain.ErrInvalidHoldStatus
+	}
+
+	after := hold.Snapshot()
+	after["reason"] = reason
+
+	service.auditService.Record(ctx, "balance_hold.released", "balance_hold", hold.ID, before, after)
+
+	return service.publish(ctx, hold, "balance_hold.released")
+}
+
+func (service *BalanceHoldService) publish(ctx context.Context, hold *domain.BalanceHold, eventType string) error {
+	return service.eventService.Publish(ctx, hold.AccountID, eventType, "balance_hold", hold.ID, map[string]any{
+		"kind":       hold.Kind,
+		"amount":     hold.Amount,
+		"invoice_id": hold.InvoiceID,
+		"status":     hold.Status,
+		"release_at": hold.ReleaseAt,
+	})
+}
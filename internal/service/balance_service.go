@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+type BalanceService struct {
+	repository        repository.BalanceTransactionRepository
+	accountRepository repository.AccountRepository
+}
+
+func NewBalanceService(repository repository.BalanceTransactionRepository, accountRepository repository.AccountRepository) *BalanceService {
+	return &BalanceService{
+		repository:        repository,
+		accountRepository: accountRepository,
+	}
+}
+
+func (service *BalanceService) ListTransactions(ctx context.Context, accountID string, query dto.BalanceTransactionQuery, limit int) ([]dto.BalanceTransactionOutput, error) {
+	filter, err := dto.ToBalanceTransactionFilter(query, accountID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromBalanceTransactions(transactions), nil
+}
+
+// Statement collects an account's balance transactions for a month given as
+// YYYY-MM, between the balance it opened and closed with.
+func (service *BalanceService) Statement(ctx context.Context, accountID, month string) (*domain.Statement, error) {
+	start, err := dto.ParseStatementMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := service.accountRepository.FindByIDIncludingDeleted(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := start.AddDate(0, 1, 0)
+
+	opening, err := service.repository.BalanceAt(ctx, accountID, start)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := service.repository.ListPeriod(ctx, accountID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewStatement(account, start, opening, transactions), nil
+}
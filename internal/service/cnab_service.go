@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/cnab"
+)
+
+type CNABService struct {
+	repository        repository.CNABRepository
+	invoiceRepository repository.InvoiceRepository
+	invoiceService    *InvoiceService
+	company           cnab.Company
+	boletoProvider    domain.BoletoProvider
+	auditService      *AuditService
+}
+
+func NewCNABService(repository repository.CNABRepository, invoiceRepository repository.InvoiceRepository, invoiceService *InvoiceService, company cnab.Company, boletoProvider domain.BoletoProvider, auditService *AuditService) *CNABService {
+	return &CNABService{
+		repository:        repository,
+		invoiceRepository: invoiceRepository,
+		invoiceService:    invoiceService,
+		company:           company,
+		boletoProvider:    boletoProvider,
+		auditService:      auditService,
+	}
+}
+
+// GenerateRemittance registers the pending boleto invoices not yet sent to
+// the bank. Each gets a nosso número, stored as its provider charge id so
+// the return file can settle it. Only boletos created while BOLETO_PROVIDER
+// is cnab are left unregistered, so remittances are refused otherwise.
+func (service *CNABService) GenerateRemittance(ctx context.Context, input dto.GenerateRemittanceInput) (*dto.CNABFileOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if service.boletoProvider != domain.BoletoProviderCNAB {
+		return nil, domain.ErrCNABDisabled
+	}
+
+	if !service.company.IsConfigured() {
+		return nil, domain.ErrCNABNotConfigured
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = domain.MaxRemittanceTitles
+	}
+
+	invoices, err := service.invoiceRepository.FindUnregistered(ctx, domain.PaymentMethodBoleto, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(invoices) == 0 {
+		return nil, domain.ErrNoInvoicesToRegister
+	}
+
+	ourNumbers, err := service.repository.NextOurNumbers(ctx, len(invoices))
+	if err != nil {
+		return nil, err
+	}
+
+	sequence, err := service.repository.NextRemittanceSequence(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	titles := make([]cnab.Title, 0, len(invoices))
+	items := make([]domain.CNABFileItem, 0, len(invoices))
+	for i, invoice := range invoices {
+		ourNumber := fmt.Sprintf("%011d", ourNumbers[i])
+
+		titles = append(titles, toTitle(invoice, ourNumber, now))
+		items = append(items, domain.CNABFileItem{
+			OurNumber: ourNumber,
+			InvoiceID: invoice.ID,
+			Movement:  "01",
+			Amount:    invoice.Amount,
+			Outcome:   domain.CNABOutcomeRegistered,
+		})
+	}
+
+	var content bytes.Buffer
+	if err := cnab.WriteRemittance(&content, service.company, sequence, now, titles); err != nil {
+		return nil, err
+	}
+
+	file := domain.NewCNABFile(domain.CNABFileRemittance, sequence, fmt.Sprintf("remessa-%06d.rem", sequence), content.Bytes(), items)
+
+	if err := service.repository.SaveRemittance(ctx, file); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "cnab.remittance_generated", "cnab_file", file.ID, nil, map[string]any{
+		"sequence": file.Sequence,
+		"titles":   len(file.Items),
+	})
+
+	output := dto.FromCNABFile(file, true)
+
+	return &output, nil
+}
+
+// ImportReturn applies a return file from the bank: paid titles settle their
+// invoices and rejected entries are reported with the bank's reasons. The
+// file is kept along with what happened to each title.
+func (service *CNABService) ImportReturn(ctx context.Context, fileName string, content []byte) (*dto.CNABFileOutput, error) {
+	parsed, err := cnab.ParseReturn(bytes.NewReader(content))
+	if err != nil {
+		if errors.Is(err, cnab.ErrInvalidFile) {
+			return nil, domain.NewValidationError(domain.NewFieldError("file", "invalid_format", err.Error()))
+		}
+
+		return nil, err
+	}
+
+	if service.company.BankCode != "" && parsed.BankCode != service.company.BankCode {
+		return nil, domain.NewValidationError(domain.NewFieldError("file", "invalid_value", "file is from bank "+parsed.BankCode+", expected "+service.company.BankCode))
+	}
+
+	items := make([]domain.CNABFileItem, 0, len(parsed.Items))
+	for _, returned := range parsed.Items {
+		items = append(items, service.apply(ctx, returned))
+	}
+
+	file := domain.NewCNABFile(domain.CNABFileReturn, parsed.Sequence, fileName, content, items)
+
+	if err := service.repository.SaveReturn(ctx, file); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "cnab.return_imported", "cnab_file", file.ID, nil, map[string]any{
+		"sequence": file.Sequence,
+		"titles":   len(file.Items),
+	})
+
+	output := dto.FromCNABFile(file, true)
+
+	return &output, nil
+}
+
+func (service *CNABService) Get(ctx context.Context, id string) (*domain.CNABFile, error) {
+	return service.repository.FindByID(ctx, id)
+}
+
+func (service *CNABService) Search(ctx context.Context, filter domain.CNABFileFilter) ([]dto.CNABFileOutput, error) {
+	files, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromCNABFiles(files), nil
+}
+
+func (service *CNABService) apply(ctx context.Context, returned cnab.ReturnItem) domain.CNABFileItem {
+	item := domain.CNABFileItem{
+		OurNumber:  returned.OurNumber,
+		Movement:   returned.Movement,
+		Amount:     returned.Amount,
+		PaidAmount: returned.PaidAmount,
+	}
+
+	invoice, err := service.invoiceRepository.FindByProviderChargeID(ctx, returned.OurNumber)
+	if errors.Is(err, domain.ErrInvoiceNotFound) {
+		item.Outcome = domain.CNABOutcomeNotFound
+		return item
+	}
+
+	if err != nil {
+		log.Printf("[CNABService] Error finding invoice for nosso número %s: %v", returned.OurNumber, err)
+		item.Outcome = domain.CNABOutcomeFailed
+		item.Note = err.Error()
+		return item
+	}
+
+	item.InvoiceID = invoice.ID
+
+	switch {
+	case returned.IsPaid():
+		if invoice.Status != domain.StatusPending {
+			item.Outcome = domain.CNABOutcomeIgnored
+			item.Note = "invoice is " + string(invoice.Status)
+			return item
+		}
+
+		paidAt := returned.OccurredAt
+		if paidAt.IsZero() {
+			paidAt = time.Now()
+		}
+
+		if err := service.invoiceService.SettleProviderCharge(ctx, returned.OurNumber, paidAt, returned.PaidAmount); err != nil {
+			log.Printf("[CNABService] Error settling invoice %s: %v", invoice.ID, err)
+			item.Outcome = domain.CNABOutcomeFailed
+			item.Note = err.Error()
+			return item
+		}
+
+		item.Outcome = domain.CNABOutcomeSettled
+	case returned.Movement == cnab.MovementEntryRejected:
+		item.Outcome = domain.CNABOutcomeRejected
+		item.Note = "rejected by the bank, reasons " + returned.Reasons
+	case returned.Movement == cnab.MovementEntryConfirmed:
+		item.Outcome = domain.CNABOutcomeRegistered
+	default:
+		item.Outcome = domain.CNABOutcomeIgnored
+	}
+
+	return item
+}
+
+// toTitle maps an invoice to the boleto registered for it. Only the first
+// discount still valid fits in segment P.
+func toTitle(invoice *domain.Invoice, ourNumber string, issuedAt time.Time) cnab.Title {
+	title := cnab.Title{
+		OurNumber:      ourNumber,
+		DocumentNumber: ourNumber,
+		CompanyUse:     invoice.Reference,
+		IssueDate:      issuedAt,
+		DueDate:        invoice.DueDate,
+		Amount:         invoice.Amount,
+		Payer: cnab.Payer{
+			TaxID:    invoice.Payer.TaxID,
+			Name:     invoice.Payer.Name,
+			Address:  strings.TrimSpace(invoice.Payer.Address + " " + invoice.Payer.Number),
+			District: invoice.Payer.District,
+			ZipCode:  invoice.Payer.ZipCode,
+			City:     invoice.Payer.City,
+			State:    invoice.Payer.State,
+		},
+	}
+
+	if invoice.Interest != nil {
+		title.Interest = cnab.Rate{Percent: invoice.Interest.Type == domain.AdjustmentPercent, Value: invoice.Interest.Value}
+	}
+
+	for _, discount := range invoice.Discounts {
+		if discount.Until.Format(time.DateOnly) < issuedAt.Format(time.DateOnly) {
+			continue
+		}
+
+		title.Discount = cnab.Rate{Percent: discount.Type == domain.AdjustmentPercent, Value: discount.Value}
+		title.DiscountUntil = discount.Until
+
+		break
+	}
+
+	return title
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+type CustomerService struct {
+	repository   repository.PayerRepository
+	auditService *AuditService
+}
+
+func NewCustomerService(repository repository.PayerRepository, auditService *AuditService) *CustomerService {
+	return &CustomerService{
+		repository:   repository,
+		auditService: auditService,
+	}
+}
+
+func (service *CustomerService) Create(ctx context.Context, accountID string, input dto.CustomerInput) (*dto.CustomerOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	customer := domain.NewCustomer(accountID, dto.ToPayer(input))
+	if err := service.save(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromCustomer(customer, nil)
+
+	return &output, nil
+}
+
+func (service *CustomerService) Get(ctx context.Context, accountID, id string) (*dto.CustomerOutput, error) {
+	customer, err := service.Find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := service.repository.Totals(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromCustomer(customer, totals)
+
+	return &output, nil
+}
+
+func (service *CustomerService) Update(ctx context.Context, accountID, id string, input dto.CustomerInput) (*dto.CustomerOutput, error) {
+	if err := input.ValidateUpdate(); err != nil {
+		return nil, err
+	}
+
+	customer, err := service.Find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.IsDeleted() {
+		return nil, domain.ErrCustomerDeleted
+	}
+
+	if err := service.update(ctx, customer, dto.ToPayer(input)); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromCustomer(customer, nil)
+
+	return &output, nil
+}
+
+func (service *CustomerService) Delete(ctx context.Context, accountID, id string) (*dto.CustomerOutput, error) {
+	customer, err := service.Find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := customer.Snapshot()
+
+	if err := customer.Delete(); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Update(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "customer.deleted", "customer", customer.ID, before, customer.Snapshot())
+
+	output := dto.FromCustomer(customer, nil)
+
+	return &output, nil
+}
+
+func (service *CustomerService) Search(ctx context.Context, filter domain.CustomerFilter) ([]dto.CustomerOutput, error) {
+	customers, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.CustomerOutput, len(customers))
+	for i, customer := range customers {
+		output[i] = dto.FromCustomer(customer, nil)
+	}
+
+	return output, nil
+}
+
+// Find loads a customer only if it belongs to the account; customers of other
+// accounts are reported as missing rather than forbidden.
+func (service *CustomerService) Find(ctx context.Context, accountID, id string) (*domain.Payer, error) {
+	customer, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.AccountID != accountID {
+		return nil, domain.ErrCustomerNotFound
+	}
+
+	return customer, nil
+}
+
+// Resolve returns the customer an invoice should be billed to: the one named
+// by customerID, or the account's customer with the same tax id refreshed with
+// the payer details, or a new customer created from them.
+func (service *CustomerService) Resolve(ctx context.Context, accountID, customerID string, details domain.Payer) (*domain.Payer, error) {
+	if customerID != "" {
+		customer, err := service.Find(ctx, accountID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		if customer.IsDeleted() {
+			return nil, domain.ErrCustomerDeleted
+		}
+
+		return customer, nil
+	}
+
+	customer, err := service.repository.FindByTaxID(ctx, accountID, details.TaxID)
+	if err == domain.ErrCustomerNotFound {
+		customer = domain.NewCustomer(accountID, details)
+
+		return customer, service.save(ctx, customer)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, service.update(ctx, customer, details)
+}
+
+func (service *CustomerService) save(ctx context.Context, customer *domain.Payer) error {
+	if err := service.repository.Save(ctx, customer); err != nil {
+		return err
+	}
+
+	service.auditService.Record(ctx, "customer.created", "customer", customer.ID, nil, customer.Snapshot())
+
+	return nil
+}
+
+func (service *CustomerService) update(ctx context.Context, customer *domain.Payer, details domain.Payer) error {
+	before := customer.Snapshot()
+	if !customer.Update(details) {
+		return nil
+	}
+
+	if err := service.repository.Update(ctx, customer); err != nil {
+		return err
+	}
+
+	service.auditService.Record(ctx, "customer.updated", "customer", customer.ID, before, customer.Snapshot())
+
+	return nil
+}
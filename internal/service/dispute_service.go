@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+const disputeExpiryBatchSize = 100
+
+type DisputeService struct {
+	repository     repository.DisputeRepository
+	invoiceService *InvoiceService
+	eventService   *EventService
+	auditService   *AuditService
+}
+
+func NewDisputeService(repository repository.DisputeRepository, invoiceService *InvoiceService, eventService *EventService, auditService *AuditService) *DisputeService {
+	return &DisputeService{
+		repository:     repository,
+		invoiceService: invoiceService,
+		eventService:   eventService,
+		auditService:   auditService,
+	}
+}
+
+// Simulate opens a dispute on one of the account's card invoices as if the
+// cardholder's bank had raised it, holding the amount in reserve.
+func (service *DisputeService) Simulate(ctx context.Context, accountID string, input dto.SimulateDisputeInput) (*dto.DisputeOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	invoice, err := service.invoiceService.Find(ctx, accountID, input.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := input.Amount
+	if amount == 0 {
+		amount = invoice.PaidAmount - invoice.RefundedAmount
+	}
+
+	dispute, err := invoice.OpenDispute(domain.DisputeReason(input.Reason), amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Save(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "dispute.created", "dispute", dispute.ID, nil, dispute.Snapshot())
+
+	if err := service.publish(ctx, dispute, "dispute.created"); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromDispute(dispute, nil)
+
+	return &output, nil
+}
+
+func (service *DisputeService) Get(ctx context.Context, accountID, id string) (*dto.DisputeOutput, error) {
+	dispute, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.output(ctx, dispute)
+}
+
+func (service *DisputeService) Search(ctx context.Context, filter domain.DisputeFilter) ([]dto.DisputeOutput, error) {
+	disputes, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.DisputeOutput, len(disputes))
+	for i, dispute := range disputes {
+		output[i] = dto.FromDispute(dispute, nil)
+	}
+
+	return output, nil
+}
+
+// AddEvidence stores a statement and files for the dispute. Evidence can be
+// added until it is submitted or the deadline passes.
+func (service *DisputeService) AddEvidence(ctx context.Context, accountID, id string, input dto.AddDisputeEvidenceInput) (*dto.DisputeOutput, error) {
+	dispute, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dispute.CanTakeEvidence(); err != nil {
+		return nil, err
+	}
+
+	evidence, err := dto.ToDisputeEvidence(input, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range evidence {
+		if err := service.repository.SaveEvidence(ctx, item); err != nil {
+			return nil, err
+		}
+
+		service.auditService.Record(ctx, "dispute.evidence_added", "dispute", dispute.ID, nil, map[string]any{
+			"evidence_id": item.ID,
+			"kind":        item.Kind,
+			"file_name":   item.FileName,
+			"size":        item.Size,
+		})
+	}
+
+	return service.output(ctx, dispute)
+}
+
+func (service *DisputeService) Evidence(ctx context.Context, accountID, id, evidenceID string) (*domain.DisputeEvidence, error) {
+	dispute, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.repository.FindEvidence(ctx, dispute.ID, evidenceID)
+}
+
+// Submit sends the evidence for review. It needs at least one piece of
+// evidence and closes the dispute to more.
+func (service *DisputeService) Submit(ctx context.Context, accountID, id string) (*dto.DisputeOutput, error) {
+	dispute, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence, err := service.repository.ListEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(evidence) == 0 {
+		return nil, domain.ErrDisputeEvidenceRequired
+	}
+
+	before := dispute.Snapshot()
+
+	if err := dispute.Submit(); err != nil {
+		return nil, err
+	}
+
+	submitted, err := service.repository.Submit(ctx, dispute)
+	if err != nil {
+		return nil, err
+	}
+
+	if !submitted {
+		return nil, domain.ErrInvalidDisputeStatus
+	}
+
+	service.auditService.Record(ctx, "dispute.submitted", "dispute", dispute.ID, before, dispute.Snapshot())
+
+	if err := service.publish(ctx, dispute, "dispute.submitted"); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromDispute(dispute, evidence)
+
+	return &output, nil
+}
+
+// Resolve closes the dispute with the network's decision and settles the
+// reserve. An empty accountID means an operator is recording the outcome.
+func (service *DisputeService) Resolve(ctx context.Context, accountID, id string, input dto.ResolveDisputeInput) (*dto.DisputeOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	dispute, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.resolve(ctx, dispute, domain.DisputeStatus(input.Outcome), input.Reason); err != nil {
+		return nil, err
+	}
+
+	return service.output(ctx, dispute)
+}
+
+// ExpireOverdue loses the disputes nobody answered before their deadline.
+func (service *DisputeService) ExpireOverdue(ctx context.Context) (int, error) {
+	disputes, err := service.repository.FindOverdue(ctx, time.Now(), disputeExpiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, dispute := range disputes {
+		if err := service.resolve(ctx, dispute, domain.DisputeStatusLost, "no response before the deadline"); err != nil {
+			log.Printf("[DisputeService] Error expiring dispute %s: %v", dispute.ID, err)
+			continue
+		}
+
+		expired++
+	}
+
+	if expired > 0 {
+		log.Printf("[DisputeService] Expired %d disputes", expired)
+	}
+
+	return expired, nil
+}
+
+func (service *DisputeService) resolve(ctx context.Context, dispute *domain.Dispute, outcome domain.DisputeStatus, reason string) error {
+	before := dispute.Snapshot()
+
+	if err := dispute.Resolve(outcome); err != nil {
+		return err
+	}
+
+	resolved, err := service.repository.Resolve(ctx, dispute)
+	if err != nil {
+		return err
+	}
+
+	if !resolved {
+		return domain.ErrInvalidDisputeStatus
+	}
+
+	after := dispute.Snapshot()
+	after["reason"] = reason
+
+	action := "dispute." + string(dispute.Status)
+	service.auditService.Record(ctx, action, "dispute", dispute.ID, before, after)
+
+	return service.publish(ctx, dispute, action)
+}
+
+func (service *DisputeService) find(ctx context.Context, accountID, id string) (*domain.Dispute, error) {
+	dispute, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if accountID != "" && dispute.AccountID != accountID {
+		return nil, domain.ErrDisputeNotFound
+	}
+
+	return dispute, nil
+}
+
+func (service *DisputeService) output(ctx context.Context, dispute *domain.Dispute) (*dto.DisputeOutput, error) {
+	evidence, err := service.repository.ListEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromDispute(dispute, evidence)
+
+	return &output, nil
+}
+
+// publish tells the invoice's account and every other account with money on
+// hold for the dispute.
+func (service *DisputeService) publish(ctx context.Context, dispute *domain.Dispute, eventType string) error {
+	accounts := []string{dispute.AccountID}
+	for _, hold := range dispute.Holds {
+		if hold.AccountID != dispute.AccountID {
+			accounts = append(accounts, hold.AccountID)
+		}
+	}
+
+	for _, accountID := range accounts {
+		if err := service.eventService.Publish(ctx, accountID, eventType, "dispute", dispute.ID, map[string]any{
+			"invoice_id": dispute.InvoiceID,
+			"reason":     dispute.Reason,
+			"amount":     dispute.Amount,
+			"status":     dispute.Status,
+			"holds":      dispute.Holds,
+			"deadline":   dispute.Deadline,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+type EventService struct {
+	repository repository.EventRepository
+}
+
+func NewEventService(repository repository.EventRepository) *EventService {
+	return &EventService{
+		repository: repository,
+	}
+}
+
+func (service *EventService) Publish(ctx context.Context, accountID, eventType, resourceType, resourceID string, data map[string]any) error {
+	event := domain.NewEvent(accountID, eventType, resourceType, resourceID, data)
+
+	if err := service.repository.Save(ctx, event); err != nil {
+		log.Printf("[EventService] Error publishing %s for %s %s: %v", eventType, resourceType, resourceID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (service *EventService) Search(ctx context.Context, filter domain.EventFilter) ([]dto.EventOutput, error) {
+	events, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.EventOutput, len(events))
+	for i, event := range events {
+		output[i] = dto.FromEvent(event)
+	}
+
+	return output, nil
+}
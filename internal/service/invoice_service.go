@@ -1,72 +1,435 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
 	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
 	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
 	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
 )
 
+const invoiceExpiryBatchSize = 100
+
 type InvoiceService struct {
-	invoiceRepository repository.InvoiceRepository
-	accountService    AccountService
+	invoiceRepository    repository.InvoiceRepository
+	providerRepository   repository.InvoiceRepository
+	refundRepository     repository.RefundRepository
+	accountService       AccountService
+	customerService      *CustomerService
+	paymentMethodService *PaymentMethodService
+	settlementService    *SettlementService
+	eventService         *EventService
+	auditService         *AuditService
+	boletoProvider       domain.BoletoProvider
 }
 
-func NewInvoiceService(invoiceRepository repository.InvoiceRepository, accountService AccountService) *InvoiceService {
+func NewInvoiceService(invoiceRepository repository.InvoiceRepository, providerRepository repository.InvoiceRepository, refundRepository repository.RefundRepository, accountService AccountService, customerService *CustomerService, paymentMethodService *PaymentMethodService, settlementService *SettlementService, eventService *EventService, auditService *AuditService, boletoProvider domain.BoletoProvider) *InvoiceService {
 	return &InvoiceService{
-		invoiceRepository: invoiceRepository,
-		accountService:    accountService,
+		invoiceRepository:    invoiceRepository,
+		providerRepository:   providerRepository,
+		refundRepository:     refundRepository,
+		accountService:       accountService,
+		customerService:      customerService,
+		paymentMethodService: paymentMethodService,
+		settlementService:    settlementService,
+		eventService:         eventService,
+		auditService:         auditService,
+		boletoProvider:       boletoProvider,
 	}
 }
 
-func (s *InvoiceService) Create(input dto.CreateInvoiceInput) (*dto.InvoiceOutput, error) {
-	accountOutput, err := s.accountService.FindByAPIKey(input.APIKey)
+func (s *InvoiceService) Create(ctx context.Context, accountID string, input dto.CreateInvoiceInput) (*dto.InvoiceOutput, error) {
+	invoice, err := s.create(ctx, accountID, input, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	invoice, err := dto.ToInvoice(input, accountOutput.ID)
+	return dto.FromInvoice(invoice), nil
+}
+
+// CreateForSubscription charges a subscription to its saved payment method,
+// or to the customer's default when none is pinned. A paymentType issues the
+// charge on that method instead, as dunning does when falling back to boleto
+// or Pix.
+func (s *InvoiceService) CreateForSubscription(ctx context.Context, subscription *domain.Subscription, plan *domain.Plan, amount float64, paymentType domain.PaymentMethod, dueDate time.Time) (*domain.Invoice, error) {
+	input := dto.CreateInvoiceInput{
+		Amount:          amount,
+		Description:     fmt.Sprintf("%s (%s - %s)", plan.Name, subscription.CurrentPeriodStart.Format("2006-01-02"), subscription.CurrentPeriodEnd.Format("2006-01-02")),
+		DueDate:         dueDate,
+		Reference:       subscription.ID[:15],
+		CustomerID:      subscription.CustomerID,
+		PaymentMethodID: subscription.PaymentMethodID,
+	}
+
+	if paymentType != "" {
+		input.PaymentType = string(paymentType)
+		input.PaymentMethodID = ""
+	}
+
+	// A period is charged at most once: a retry after a failure that left
+	// the invoice behind gets that invoice back instead of a second one.
+	existing, err := s.invoiceRepository.FindOpenForPeriod(ctx, subscription.ID, subscription.CurrentPeriodStart)
+	if err == nil {
+		return existing, nil
+	}
+
+	if !errors.Is(err, domain.ErrInvoiceNotFound) {
+		return nil, err
+	}
+
+	invoice, err := s.create(ctx, subscription.AccountID, input, subscription)
+	if errors.Is(err, domain.ErrPeriodAlreadyInvoiced) {
+		return s.invoiceRepository.FindOpenForPeriod(ctx, subscription.ID, subscription.CurrentPeriodStart)
+	}
+
+	return invoice, err
+}
+
+func (s *InvoiceService) create(ctx context.Context, accountID string, input dto.CreateInvoiceInput, subscription *domain.Subscription) (*domain.Invoice, error) {
+	if input.PaymentType != "" && !canCharge(ctx, domain.PaymentMethod(input.PaymentType)) {
+		return nil, domain.ErrPaymentTypeNotAllowed
+	}
+
+	account, err := s.accountService.Find(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
 
+	invoice, err := dto.ToInvoice(input, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSplitRecipients(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	invoice.GraceDays = account.InvoiceGraceDays
+	invoice.ApplyChargeDefaults(account.ChargeDefaults, time.Now())
+
+	customer, err := s.customerService.Resolve(ctx, accountID, input.CustomerID, invoice.Payer)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.AttachCustomer(customer)
+	if subscription != nil {
+		invoice.SubscriptionID = subscription.ID
+		invoice.PeriodStart = subscription.CurrentPeriodStart
+	}
+
+	if input.PaymentMethodID != "" || invoice.PaymentType == "" {
+		method, err := s.paymentMethodService.ForCharge(ctx, accountID, customer.ID, input.PaymentMethodID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := invoice.AttachPaymentMethod(method); err != nil {
+			return nil, err
+		}
+	}
+
+	if !canCharge(ctx, domain.PaymentMethod(invoice.PaymentType)) {
+		return nil, domain.ErrPaymentTypeNotAllowed
+	}
+
+	if err := invoice.ApplyFeeRule(s.accountService.FeeRuleFor(account, domain.PaymentMethod(invoice.PaymentType))); err != nil {
+		return nil, err
+	}
+
 	if err := invoice.Process(); err != nil {
 		return nil, err
 	}
 
+	// Boleto and Pix charges are registered with the provider before anything
+	// is stored, so a provider failure leaves nothing behind.
+	if s.registersWithProvider(invoice) {
+		if err := s.providerRepository.Save(ctx, invoice); err != nil {
+			return nil, err
+		}
+	}
+
+	var settlements []*domain.Settlement
 	if invoice.Status == domain.StatusApproved {
-		_, err = s.accountService.UpdateBalance(input.APIKey, invoice.Amount)
+		settlements, err = s.settlementService.Plan(ctx, invoice)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if err := s.invoiceRepository.Save(invoice); err != nil {
+	if err := s.invoiceRepository.Create(ctx, invoice, settlements); err != nil {
+		if invoice.ProviderChargeID != "" {
+			if cancelErr := s.providerRepository.Cancel(ctx, invoice); cancelErr != nil {
+				log.Printf("[InvoiceService] Error withdrawing charge %s of unsaved invoice %s: %v", invoice.ProviderChargeID, invoice.ID, cancelErr)
+			}
+		}
+
 		return nil, err
 	}
 
-	return dto.FromInvoice(invoice), nil
+	s.auditService.Record(ctx, "invoice.created", "invoice", invoice.ID, nil, invoice.Snapshot())
+
+	if err := s.settlementService.Scheduled(ctx, settlements); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
 }
 
-func (s *InvoiceService) GetByID(id, apiKey string) (*dto.InvoiceOutput, error) {
-	invoice, err := s.invoiceRepository.FindByID(id)
+// registersWithProvider tells whether the charge goes through the provider's
+// API. Under CNAB collection boletos are left for the next remittance, and
+// the bank drops them on its own once they expire.
+func (s *InvoiceService) registersWithProvider(invoice *domain.Invoice) bool {
+	switch domain.PaymentMethod(invoice.PaymentType) {
+	case domain.PaymentMethodCard:
+		return false
+	case domain.PaymentMethodBoleto:
+		return s.boletoProvider != domain.BoletoProviderCNAB
+	default:
+		return true
+	}
+}
+
+func (s *InvoiceService) checkSplitRecipients(ctx context.Context, invoice *domain.Invoice) error {
+	for _, rule := range invoice.Splits {
+		if rule.RecipientAccountID == invoice.AccountID {
+			return domain.ErrInvalidSplitRecipient
+		}
+
+		err := s.accountService.CheckActive(ctx, rule.RecipientAccountID)
+		if errors.Is(err, domain.ErrAccountNotFound) || errors.Is(err, domain.ErrAccountFrozen) {
+			return domain.ErrInvalidSplitRecipient
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Refund gives part or all of a paid invoice back to the payer, taking it
+// back from every split recipient in proportion to what each received.
+func (s *InvoiceService) Refund(ctx context.Context, accountID, id string, input dto.CreateRefundInput) (*dto.RefundOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.Find(ctx, accountID, id)
 	if err != nil {
 		return nil, err
 	}
 
-	accountOutput, err := s.accountService.FindByAPIKey(apiKey)
+	before := invoice.Snapshot()
+
+	refund, err := invoice.Refund(input.Amount, input.Reason)
 	if err != nil {
 		return nil, err
 	}
 
-	if invoice.AccountID != accountOutput.ID {
-		return nil, domain.ErrUnauthorizedAccess
+	if err := s.refundRepository.Save(ctx, refund, invoice); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, "invoice.refunded", "invoice", invoice.ID, before, invoice.Snapshot())
+
+	s.auditService.Record(ctx, "refund.created", "refund", refund.ID, nil, refund.Snapshot())
+
+	for _, reversal := range refund.Reversals[1:] {
+		if reversal.Amount <= 0 {
+			continue
+		}
+
+		if err := s.eventService.Publish(ctx, reversal.AccountID, "split.reversed", "refund", refund.ID, map[string]any{
+			"invoice_id": invoice.ID,
+			"amount":     reversal.Amount,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.eventService.Publish(ctx, invoice.AccountID, "invoice.refunded", "invoice", invoice.ID, map[string]any{
+		"refund_id":       refund.ID,
+		"amount":          refund.Amount,
+		"refunded_amount": invoice.RefundedAmount,
+		"reason":          refund.Reason,
+		"reversals":       refund.Reversals,
+	}); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromRefund(refund)
+
+	return &output, nil
+}
+
+func (s *InvoiceService) ListRefunds(ctx context.Context, accountID, id string) ([]dto.RefundOutput, error) {
+	invoice, err := s.Find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.refundRepository.FindByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.RefundOutput, len(refunds))
+	for i, refund := range refunds {
+		output[i] = dto.FromRefund(refund)
+	}
+
+	return output, nil
+}
+
+// ExpireOverdue expires pending invoices whose grace period is over and
+// withdraws their charge at the provider so they can no longer be paid.
+func (s *InvoiceService) ExpireOverdue(ctx context.Context) (int, error) {
+	invoices, err := s.invoiceRepository.FindExpired(ctx, time.Now(), invoiceExpiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, invoice := range invoices {
+		if err := s.expire(ctx, invoice); err != nil {
+			log.Printf("[InvoiceService] Error expiring invoice %s: %v", invoice.ID, err)
+			continue
+		}
+
+		expired++
+	}
+
+	if expired > 0 {
+		log.Printf("[InvoiceService] Expired %d invoices", expired)
+	}
+
+	return expired, nil
+}
+
+func (s *InvoiceService) expire(ctx context.Context, invoice *domain.Invoice) error {
+	before := invoice.Snapshot()
+
+	if err := invoice.Expire(); err != nil {
+		return err
+	}
+
+	if invoice.ProviderChargeID != "" && s.registersWithProvider(invoice) {
+		if err := s.providerRepository.Cancel(ctx, invoice); err != nil {
+			return err
+		}
+	}
+
+	if err := s.invoiceRepository.Cancel(ctx, invoice); err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, "invoice.expired", "invoice", invoice.ID, before, invoice.Snapshot())
+
+	return s.eventService.Publish(ctx, invoice.AccountID, "invoice.expired", "invoice", invoice.ID, map[string]any{
+		"amount":          invoice.Amount,
+		"customer_id":     invoice.CustomerID,
+		"subscription_id": invoice.SubscriptionID,
+		"due_date":        invoice.DueDate,
+		"expired_at":      invoice.UpdatedAt,
+	})
+}
+
+// SettleProviderCharge records a payment confirmed by the provider. The
+// amount due is recomputed from the invoice's discount, fine and interest
+// rules for the payment date; when the provider reports a different amount,
+// what was actually received wins. Notifications for invoices no longer
+// pending are ignored so redelivered webhooks are harmless.
+func (s *InvoiceService) SettleProviderCharge(ctx context.Context, chargeID string, paidAt time.Time, received float64) error {
+	invoice, err := s.invoiceRepository.FindByProviderChargeID(ctx, chargeID)
+	if err != nil {
+		return err
+	}
+
+	if invoice.Status != domain.StatusPending {
+		return nil
+	}
+
+	before := invoice.Snapshot()
+	breakdown := invoice.AmountDueAt(paidAt)
+
+	paid := breakdown.Total
+	if received > 0 {
+		if received != breakdown.Total {
+			log.Printf("[InvoiceService] Invoice %s paid %.2f, expected %.2f", invoice.ID, received, breakdown.Total)
+		}
+
+		paid = received
+	}
+
+	if err := invoice.MarkPaid(paid, paidAt); err != nil {
+		return err
+	}
+
+	if err := s.invoiceRepository.Settle(ctx, invoice); err != nil {
+		if errors.Is(err, domain.ErrInvalidStatus) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := s.settlementService.Schedule(ctx, invoice); err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, "invoice.paid", "invoice", invoice.ID, before, invoice.Snapshot())
+
+	return s.eventService.Publish(ctx, invoice.AccountID, "invoice.paid", "invoice", invoice.ID, map[string]any{
+		"amount":          breakdown.Amount,
+		"discount":        breakdown.Discount,
+		"fine":            breakdown.Fine,
+		"interest":        breakdown.Interest,
+		"expected_amount": breakdown.Total,
+		"paid_amount":     paid,
+		"fee_amount":      invoice.FeeAmount,
+		"net_amount":      invoice.NetAmount,
+		"paid_at":         paidAt,
+		"customer_id":     invoice.CustomerID,
+		"subscription_id": invoice.SubscriptionID,
+	})
+}
+
+func canCharge(ctx context.Context, method domain.PaymentMethod) bool {
+	principal, ok := domain.PrincipalFromContext(ctx)
+
+	return !ok || principal.CanCharge(method)
+}
+
+func (s *InvoiceService) GetByID(ctx context.Context, id, accountID string) (*dto.InvoiceOutput, error) {
+	invoice, err := s.Find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
 	}
 
 	return dto.FromInvoice(invoice), nil
 }
 
-func (s *InvoiceService) ListByAccount(accountID string) ([]*dto.InvoiceOutput, error) {
-	invoices, err := s.invoiceRepository.FindByAccountID(accountID)
+func (s *InvoiceService) Find(ctx context.Context, accountID, id string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.AccountID != accountID {
+		return nil, domain.ErrInvoiceNotFound
+	}
+
+	return invoice, nil
+}
+
+func (s *InvoiceService) ListByAccount(ctx context.Context, accountID string) ([]*dto.InvoiceOutput, error) {
+	invoices, err := s.invoiceRepository.FindByAccountID(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -78,11 +441,27 @@ func (s *InvoiceService) ListByAccount(accountID string) ([]*dto.InvoiceOutput,
 	return output, nil
 }
 
-func (s *InvoiceService) ListByAccountAPIKey(apiKey string) ([]*dto.InvoiceOutput, error) {
-	accountOutput, err := s.accountService.FindByAPIKey(apiKey)
+func (s *InvoiceService) ListByCustomer(ctx context.Context, accountID, customerID string, limit int) ([]*dto.InvoiceOutput, error) {
+	if _, err := s.customerService.Find(ctx, accountID, customerID); err != nil {
+		return nil, err
+	}
+
+	return s.Search(ctx, domain.InvoiceFilter{
+		AccountID:  accountID,
+		CustomerID: customerID,
+		Limit:      limit,
+	})
+}
+
+func (s *InvoiceService) Search(ctx context.Context, filter domain.InvoiceFilter) ([]*dto.InvoiceOutput, error) {
+	invoices, err := s.invoiceRepository.Search(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.ListByAccount(accountOutput.ID)
+	output := make([]*dto.InvoiceOutput, len(invoices))
+	for i, invoice := range invoices {
+		output[i] = dto.FromInvoice(invoice)
+	}
+	return output, nil
 }
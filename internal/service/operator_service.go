@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/jwt"
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	operatorTokenTTL = 8 * time.Hour
+	totpIssuer       = "gateway-payment"
+)
+
+type OperatorService struct {
+	repository   repository.OperatorRepository
+	auditService *AuditService
+	jwtSecret    []byte
+}
+
+func NewOperatorService(repository repository.OperatorRepository, auditService *AuditService, jwtSecret string) *OperatorService {
+	return &OperatorService{
+		repository:   repository,
+		auditService: auditService,
+		jwtSecret:    []byte(jwtSecret),
+	}
+}
+
+func (service *OperatorService) Login(ctx context.Context, input dto.LoginInput) (*dto.LoginOutput, error) {
+	if len(service.jwtSecret) == 0 {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	operator, err := service.repository.FindByEmail(ctx, input.Email)
+	if err == domain.ErrOperatorNotFound {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if operator.IsDisabled() {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(operator.PasswordHash), []byte(input.Password)); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if operator.TOTPSecret != "" && !totp.Validate(operator.TOTPSecret, input.TOTPCode, time.Now()) {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(operatorTokenTTL)
+
+	token, err := jwt.Sign(service.jwtSecret, jwt.Claims{
+		Subject:   operator.ID,
+		Role:      string(operator.Role),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = domain.WithActor(ctx, domain.Actor{Type: domain.ActorTypeOperator, ID: operator.ID})
+	service.auditService.Record(ctx, "operator.logged_in", "operator", operator.ID, nil, nil)
+
+	return &dto.LoginOutput{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+func (service *OperatorService) Authenticate(ctx context.Context, token string) (*domain.Operator, error) {
+	if len(service.jwtSecret) == 0 {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	claims, err := jwt.Verify(service.jwtSecret, token, time.Now())
+	if err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	operator, err := service.repository.FindByID(ctx, claims.Subject)
+	if err == domain.ErrOperatorNotFound {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if operator.IsDisabled() {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return operator, nil
+}
+
+func (service *OperatorService) Create(ctx context.Context, input dto.CreateOperatorInput) (*dto.OperatorOutput, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	totpSecret := ""
+	if input.EnableTOTP {
+		totpSecret = totp.GenerateSecret()
+	}
+
+	operator, err := domain.NewOperator(input.Name, input.Email, domain.Role(input.Role), string(hash), totpSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Save(ctx, operator); err != nil {
+		return nil, err
+	}
+
+	after := map[string]any{"name": operator.Name, "email": operator.Email, "role": operator.Role}
+	service.auditService.Record(ctx, "operator.created", "operator", operator.ID, nil, after)
+
+	output := dto.FromOperator(operator)
+	if totpSecret != "" {
+		output.TOTPURL = totp.URL(totpIssuer, operator.Email, totpSecret)
+	}
+
+	return &output, nil
+}
+
+// Bootstrap creates the first admin from the environment so a fresh install
+// has someone able to log in and create the remaining operators.
+func (service *OperatorService) Bootstrap(ctx context.Context, email, password string) error {
+	if email == "" || password == "" {
+		return nil
+	}
+
+	count, err := service.repository.Count(ctx)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	_, err = service.Create(ctx, dto.CreateOperatorInput{
+		Name:     "Administrator",
+		Email:    email,
+		Password: password,
+		Role:     string(domain.RoleAdmin),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[OperatorService] Bootstrap admin operator created: %s", email)
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+const payerReencryptBatchSize = 200
+
+type PayerService struct {
+	repository repository.PayerRepository
+}
+
+func NewPayerService(repository repository.PayerRepository) *PayerService {
+	return &PayerService{
+		repository: repository,
+	}
+}
+
+func (service *PayerService) Reencrypt(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		count, err := service.repository.ReencryptBatch(ctx, payerReencryptBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += count
+
+		if count < payerReencryptBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("[PayerService] Re-encrypted %d payers with the current key", total)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+type PaymentMethodService struct {
+	repository      repository.PaymentMethodRepository
+	vault           repository.CardVault
+	customerService *CustomerService
+	auditService    *AuditService
+}
+
+func NewPaymentMethodService(repository repository.PaymentMethodRepository, vault repository.CardVault, customerService *CustomerService, auditService *AuditService) *PaymentMethodService {
+	return &PaymentMethodService{
+		repository:      repository,
+		vault:           vault,
+		customerService: customerService,
+		auditService:    auditService,
+	}
+}
+
+func (service *PaymentMethodService) Create(ctx context.Context, accountID, customerID string, input dto.CreatePaymentMethodInput) (*dto.PaymentMethodOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	customer, err := service.customerService.Find(ctx, accountID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.IsDeleted() {
+		return nil, domain.ErrCustomerDeleted
+	}
+
+	method, err := service.newMethod(ctx, accountID, customer.ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Save(ctx, method); err != nil {
+		service.discardToken(ctx, method.VaultToken)
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "payment_method.created", "payment_method", method.ID, nil, method.Snapshot())
+
+	makeDefault := input.IsDefault
+	if !makeDefault {
+		_, err := service.repository.FindDefault(ctx, customer.ID)
+		if err != nil && err != domain.ErrNoDefaultPaymentMethod {
+			return nil, err
+		}
+
+		makeDefault = err == domain.ErrNoDefaultPaymentMethod
+	}
+
+	if makeDefault {
+		if err := service.setDefault(ctx, method); err != nil {
+			return nil, err
+		}
+	}
+
+	output := dto.FromPaymentMethod(method)
+
+	return &output, nil
+}
+
+func (service *PaymentMethodService) newMethod(ctx context.Context, accountID, customerID string, input dto.CreatePaymentMethodInput) (*domain.SavedPaymentMethod, error) {
+	if domain.PaymentMethod(input.Type) != domain.PaymentMethodCard {
+		return domain.NewSavedPaymentMethod(accountID, customerID, domain.PaymentMethod(input.Type))
+	}
+
+	card := domain.CreditCard{
+		Number:         input.CardNumber,
+		ExpiryMonth:    input.ExpiryMonth,
+		ExpiryYear:     input.ExpiryYear,
+		CardholderName: input.CardholderName,
+	}
+
+	token, err := service.vault.Store(ctx, accountID, card)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := domain.NewSavedCard(accountID, customerID, token, card)
+	if err != nil {
+		service.discardToken(ctx, token)
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (service *PaymentMethodService) List(ctx context.Context, accountID, customerID string) ([]dto.PaymentMethodOutput, error) {
+	if _, err := service.customerService.Find(ctx, accountID, customerID); err != nil {
+		return nil, err
+	}
+
+	methods, err := service.repository.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.PaymentMethodOutput, len(methods))
+	for i, method := range methods {
+		output[i] = dto.FromPaymentMethod(method)
+	}
+
+	return output, nil
+}
+
+func (service *PaymentMethodService) SetDefault(ctx context.Context, accountID, customerID, id string) (*dto.PaymentMethodOutput, error) {
+	method, err := service.find(ctx, accountID, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.checkUsable(ctx, method); err != nil {
+		return nil, err
+	}
+
+	if err := service.setDefault(ctx, method); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPaymentMethod(method)
+
+	return &output, nil
+}
+
+func (service *PaymentMethodService) Delete(ctx context.Context, accountID, customerID, id string) (*dto.PaymentMethodOutput, error) {
+	method, err := service.find(ctx, accountID, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := method.Snapshot()
+	token := method.VaultToken
+
+	if err := method.Delete(); err != nil {
+		return nil, err
+	}
+
+	method.VaultToken = ""
+	if err := service.repository.Update(ctx, method); err != nil {
+		return nil, err
+	}
+
+	service.discardToken(ctx, token)
+
+	service.auditService.Record(ctx, "payment_method.deleted", "payment_method", method.ID, before, method.Snapshot())
+
+	output := dto.FromPaymentMethod(method)
+
+	return &output, nil
+}
+
+// ForCharge returns the saved method an invoice should be charged to: the one
+// named by id, or the customer's default when id is empty.
+func (service *PaymentMethodService) ForCharge(ctx context.Context, accountID, customerID, id string) (*domain.SavedPaymentMethod, error) {
+	var method *domain.SavedPaymentMethod
+	var err error
+
+	if id == "" {
+		method, err = service.repository.FindDefault(ctx, customerID)
+		if err == nil && method.AccountID != accountID {
+			err = domain.ErrNoDefaultPaymentMethod
+		}
+	} else {
+		method, err = service.find(ctx, accountID, customerID, id)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.checkUsable(ctx, method); err != nil {
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (service *PaymentMethodService) ExpireCards(ctx context.Context) (int, error) {
+	methods, err := service.repository.ExpireCards(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, method := range methods {
+		after := method.Snapshot()
+		service.auditService.Record(ctx, "payment_method.expired", "payment_method", method.ID, nil, after)
+	}
+
+	if len(methods) > 0 {
+		log.Printf("[PaymentMethodService] Flagged %d expired cards", len(methods))
+	}
+
+	return len(methods), nil
+}
+
+func (service *PaymentMethodService) find(ctx context.Context, accountID, customerID, id string) (*domain.SavedPaymentMethod, error) {
+	method, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if method.AccountID != accountID || method.CustomerID != customerID || method.IsDeleted() {
+		return nil, domain.ErrPaymentMethodNotFound
+	}
+
+	return method, nil
+}
+
+// checkUsable persists the expired flag when a card is caught past its expiry
+// before the scheduled job got to it.
+func (service *PaymentMethodService) checkUsable(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	before := method.Snapshot()
+	wasActive := method.Status == domain.PaymentMethodStatusActive
+
+	err := method.CheckUsable(time.Now())
+	if err == domain.ErrPaymentMethodExpired && wasActive {
+		if updateErr := service.repository.Update(ctx, method); updateErr != nil {
+			return updateErr
+		}
+
+		service.auditService.Record(ctx, "payment_method.expired", "payment_method", method.ID, before, method.Snapshot())
+	}
+
+	return err
+}
+
+func (service *PaymentMethodService) setDefault(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	before := method.Snapshot()
+	method.MarkDefault()
+
+	if err := service.repository.SetDefault(ctx, method); err != nil {
+		return err
+	}
+
+	service.auditService.Record(ctx, "payment_method.default_set", "payment_method", method.ID, before, method.Snapshot())
+
+	return nil
+}
+
+func (service *PaymentMethodService) discardToken(ctx context.Context, token string) {
+	if token == "" {
+		return
+	}
+
+	if err := service.vault.Delete(ctx, token); err != nil {
+		log.Printf("[PaymentMethodService] Error discarding vault token: %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+const payoutBatchSize = 100
+
+type PayoutService struct {
+	destinationRepository repository.PayoutDestinationRepository
+	repository            repository.PayoutRepository
+	provider              repository.PayoutProvider
+	accountService        AccountService
+	eventService          *EventService
+	auditService          *AuditService
+}
+
+func NewPayoutService(destinationRepository repository.PayoutDestinationRepository, repository repository.PayoutRepository, provider repository.PayoutProvider, accountService AccountService, eventService *EventService, auditService *AuditService) *PayoutService {
+	return &PayoutService{
+		destinationRepository: destinationRepository,
+		repository:            repository,
+		provider:              provider,
+		accountService:        accountService,
+		eventService:          eventService,
+		auditService:          auditService,
+	}
+}
+
+func (service *PayoutService) CreateDestination(ctx context.Context, accountID string, input dto.CreatePayoutDestinationInput) (*dto.PayoutDestinationOutput, error) {
+	destination, err := dto.ToPayoutDestination(input, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.destinationRepository.Save(ctx, destination); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "payout_destination.created", "payout_destination", destination.ID, nil, destination.Snapshot())
+
+	output := dto.FromPayoutDestination(destination)
+
+	return &output, nil
+}
+
+func (service *PayoutService) ListDestinations(ctx context.Context, accountID string) ([]dto.PayoutDestinationOutput, error) {
+	destinations, err := service.destinationRepository.FindByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.PayoutDestinationOutput, len(destinations))
+	for i, destination := range destinations {
+		output[i] = dto.FromPayoutDestination(destination)
+	}
+
+	return output, nil
+}
+
+func (service *PayoutService) DeleteDestination(ctx context.Context, accountID, id string) (*dto.PayoutDestinationOutput, error) {
+	destination, err := service.findDestination(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := destination.Snapshot()
+
+	if err := destination.Delete(); err != nil {
+		return nil, err
+	}
+
+	if err := service.destinationRepository.Update(ctx, destination); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "payout_destination.deleted", "payout_destination", destination.ID, before, destination.Snapshot())
+
+	output := dto.FromPayoutDestination(destination)
+
+	return &output, nil
+}
+
+// Create moves the amount from the available to the reserved balance and
+// then asks the provider for the transfer. If the provider can't be reached the payout stays pending and
+// the payout job submits it again later.
+func (service *PayoutService) Create(ctx context.Context, accountID string, input dto.CreatePayoutInput) (*dto.PayoutOutput, error) {
+	payout, err := dto.ToPayout(input, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := service.findDestination(ctx, accountID, input.DestinationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if destination.IsDeleted() {
+		return nil, domain.ErrPayoutDestinationNotFound
+	}
+
+	account, err := service.accountService.Find(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.HasNegativeBalance() {
+		return nil, domain.ErrNegativeBalance
+	}
+
+	reserve := domain.BalanceChange{Available: -payout.Amount, Reserved: payout.Amount}
+	if err := service.accountService.MoveBalance(ctx, accountID, reserve, "account.balance_reserved", payoutTransaction(payout, domain.BalanceTransactionPayout)); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Save(ctx, payout); err != nil {
+		release := domain.BalanceChange{Available: payout.Amount, Reserved: -payout.Amount}
+		if releaseErr := service.accountService.MoveBalance(ctx, accountID, release, "account.balance_released", payoutTransaction(payout, domain.BalanceTransactionPayoutFailure)); releaseErr != nil {
+			log.Printf("[PayoutService] Error releasing %.2f to account %s after failing to save payout: %v", payout.Amount, accountID, releaseErr)
+		}
+
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "payout.created", "payout", payout.ID, nil, payout.Snapshot())
+
+	if err := service.submit(ctx, payout, destination); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPayout(payout)
+
+	return &output, nil
+}
+
+func (service *PayoutService) Get(ctx context.Context, accountID, id string) (*dto.PayoutOutput, error) {
+	payout, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPayout(payout)
+
+	return &output, nil
+}
+
+func (service *PayoutService) Search(ctx context.Context, filter domain.PayoutFilter) ([]dto.PayoutOutput, error) {
+	payouts, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.PayoutOutput, len(payouts))
+	for i, payout := range payouts {
+		output[i] = dto.FromPayout(payout)
+	}
+
+	return output, nil
+}
+
+// ProcessPending resubmits payouts the provider never acknowledged and polls
+// the ones in flight until they settle.
+func (service *PayoutService) ProcessPending(ctx context.Context) (int, error) {
+	payouts, err := service.repository.FindProcessing(ctx, payoutBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, payout := range payouts {
+		if err := service.advance(ctx, payout); err != nil {
+			log.Printf("[PayoutService] Error processing payout %s: %v", payout.ID, err)
+			continue
+		}
+
+		if payout.IsFinal() {
+			settled++
+		}
+	}
+
+	if settled > 0 {
+		log.Printf("[PayoutService] Settled %d payouts", settled)
+	}
+
+	return settled, nil
+}
+
+// advance holds pending payouts back while the account owes money, so a
+// dispute debited after the payout was created is covered first.
+func (service *PayoutService) advance(ctx context.Context, payout *domain.Payout) error {
+	if payout.Status == domain.PayoutStatusPending {
+		negative, err := service.accountService.HasNegativeBalance(ctx, payout.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if negative {
+			log.Printf("[PayoutService] Holding payout %s while account %s has a negative balance", payout.ID, payout.AccountID)
+			return nil
+		}
+
+		destination, err := service.destinationRepository.FindByID(ctx, payout.DestinationID)
+		if err != nil {
+			return err
+		}
+
+		return service.submit(ctx, payout, destination)
+	}
+
+	result, err := service.provider.Check(ctx, payout)
+	if err != nil {
+		return err
+	}
+
+	return service.apply(ctx, payout, *result)
+}
+
+func (service *PayoutService) submit(ctx context.Context, payout *domain.Payout, destination *domain.PayoutDestination) error {
+	result, err := service.provider.Transfer(ctx, payout, destination)
+	if errors.Is(err, domain.ErrProviderUnavailable) {
+		log.Printf("[PayoutService] Provider unavailable for payout %s, will retry: %v", payout.ID, err)
+		return nil
+	}
+
+	if err != nil {
+		result = &domain.PayoutResult{Status: domain.PayoutStatusFailed, FailureReason: err.Error()}
+	}
+
+	return service.apply(ctx, payout, *result)
+}
+
+// apply records a status reported by the provider. The conditional update
+// makes sure only one caller moves the payout forward, so a failed payout
+// is refunded exactly once.
+func (service *PayoutService) apply(ctx context.Context, payout *domain.Payout, result domain.PayoutResult) error {
+	from := payout.Status
+	before := payout.Snapshot()
+
+	if err := payout.Apply(result); err != nil {
+		return err
+	}
+
+	if payout.Status == from {
+		return nil
+	}
+
+	updated, err := service.repository.UpdateIfStatus(ctx, payout, from)
+	if err != nil || !updated {
+		return err
+	}
+
+	switch payout.Status {
+	case domain.PayoutStatusPaid:
+		change := domain.BalanceChange{Reserved: -payout.Amount}
+		if err := service.accountService.MoveBalance(ctx, payout.AccountID, change, "account.balance_paid_out", nil); err != nil {
+			return err
+		}
+	case domain.PayoutStatusFailed:
+		change := domain.BalanceChange{Available: payout.Amount, Reserved: -payout.Amount}
+		if err := service.accountService.MoveBalance(ctx, payout.AccountID, change, "account.balance_released", payoutTransaction(payout, domain.BalanceTransactionPayoutFailure)); err != nil {
+			return err
+		}
+	}
+
+	action := "payout." + string(payout.Status)
+	service.auditService.Record(ctx, action, "payout", payout.ID, before, payout.Snapshot())
+
+	return service.eventService.Publish(ctx, payout.AccountID, action, "payout", payout.ID, map[string]any{
+		"amount":               payout.Amount,
+		"destination_id":       payout.DestinationID,
+		"status":               payout.Status,
+		"provider_transfer_id": payout.ProviderTransferID,
+		"failure_reason":       payout.FailureReason,
+	})
+}
+
+func (service *PayoutService) findDestination(ctx context.Context, accountID, id string) (*domain.PayoutDestination, error) {
+	destination, err := service.destinationRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if destination.AccountID != accountID {
+		return nil, domain.ErrPayoutDestinationNotFound
+	}
+
+	return destination, nil
+}
+
+func (service *PayoutService) find(ctx context.Context, accountID, id string) (*domain.Payout, error) {
+	payout, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payout.AccountID != accountID {
+		return nil, domain.ErrPayoutNotFound
+	}
+
+	return payout, nil
+}
+
+func payoutTransaction(payout *domain.Payout, transactionType domain.BalanceTransactionType) *domain.BalanceTransaction {
+	return domain.NewBalanceTransaction(transactionType, "payout", payout.ID, payout.Description)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+type ReconciliationService struct {
+	repository   repository.ReconciliationRepository
+	provider     repository.BankStatementProvider
+	auditService *AuditService
+}
+
+func NewReconciliationService(repository repository.ReconciliationRepository, provider repository.BankStatementProvider, auditService *AuditService) *ReconciliationService {
+	return &ReconciliationService{
+		repository:   repository,
+		provider:     provider,
+		auditService: auditService,
+	}
+}
+
+// Run reconciles a period on an operator's request.
+func (service *ReconciliationService) Run(ctx context.Context, input dto.RunReconciliationInput) (*dto.ReconciliationOutput, error) {
+	from, to, err := dto.ToReconciliationPeriod(input)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation, err := service.reconcile(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromReconciliation(reconciliation, true)
+
+	return &output, nil
+}
+
+// RunPreviousDay reconciles yesterday's statement for the reconciliation
+// job. A day that already has its report is left alone, so restarts don't
+// pile up copies.
+func (service *ReconciliationService) RunPreviousDay(ctx context.Context) (*domain.Reconciliation, error) {
+	now := time.Now()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, now.Location())
+
+	existing, err := service.repository.Search(ctx, domain.ReconciliationFilter{From: yesterday, To: yesterday})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reconciliation := range existing {
+		if reconciliation.From.Equal(reconciliation.To) && reconciliation.From.Format(time.DateOnly) == yesterday.Format(time.DateOnly) {
+			return reconciliation, nil
+		}
+	}
+
+	return service.reconcile(ctx, yesterday, yesterday)
+}
+
+func (service *ReconciliationService) Get(ctx context.Context, id string) (*domain.Reconciliation, error) {
+	return service.repository.FindByID(ctx, id)
+}
+
+func (service *ReconciliationService) Search(ctx context.Context, filter domain.ReconciliationFilter) ([]dto.ReconciliationOutput, error) {
+	reconciliations, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromReconciliations(reconciliations), nil
+}
+
+func (service *ReconciliationService) reconcile(ctx context.Context, from, to time.Time) (*domain.Reconciliation, error) {
+	entries, err := service.provider.Statement(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := service.repository.FindPaidInvoices(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	var references, chargeIDs []string
+	for _, entry := range entries {
+		if !entry.IsCredit() {
+			continue
+		}
+
+		if entry.SeuNumero != "" {
+			references = append(references, entry.SeuNumero)
+		}
+
+		for _, id := range []string{entry.ChargeID, entry.NossoNumero} {
+			if id != "" {
+				chargeIDs = append(chargeIDs, id)
+			}
+		}
+	}
+
+	referenced, err := service.repository.FindInvoicesByIdentifiers(ctx, references, chargeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, invoice := range invoices {
+		known[invoice.ID] = true
+	}
+
+	for _, invoice := range referenced {
+		if !known[invoice.ID] {
+			invoices = append(invoices, invoice)
+		}
+	}
+
+	reconciliation := domain.Reconcile(from, to, entries, invoices)
+
+	if err := service.repository.Save(ctx, reconciliation); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "reconciliation.completed", "reconciliation", reconciliation.ID, nil, map[string]any{
+		"from":    reconciliation.From,
+		"to":      reconciliation.To,
+		"summary": reconciliation.Summary,
+	})
+
+	if reconciliation.HasIssues() {
+		summary := reconciliation.Summary
+		log.Printf("[ReconciliationService] Reconciliation %s found issues: %d unmatched, %d mismatched, %d not settled, %d missing", reconciliation.ID, summary.Unmatched, summary.Mismatched, summary.NotSettled, summary.Missing)
+	}
+
+	return reconciliation, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+const (
+	settlementBatchSize    = 500
+	upcomingSettlementDays = 30
+)
+
+type SettlementService struct {
+	repository        repository.SettlementRepository
+	accountRepository repository.AccountRepository
+	schedule          domain.SettlementSchedule
+	feeAccountID      string
+	eventService      *EventService
+	auditService      *AuditService
+}
+
+func NewSettlementService(repository repository.SettlementRepository, accountRepository repository.AccountRepository, schedule domain.SettlementSchedule, feeAccountID string, eventService *EventService, auditService *AuditService) *SettlementService {
+	return &SettlementService{
+		repository:        repository,
+		accountRepository: accountRepository,
+		schedule:          schedule,
+		feeAccountID:      feeAccountID,
+		eventService:      eventService,
+		auditService:      auditService,
+	}
+}
+
+// Schedule puts each recipient's net share of a paid invoice into its
+// pending balance, less the rolling reserve of its account, and posts the
+// fee to the platform account. Methods that settle on the same day are
+// released right away.
+func (service *SettlementService) Schedule(ctx context.Context, invoice *domain.Invoice) error {
+	settlements, err := service.Plan(ctx, invoice)
+	if err != nil {
+		return err
+	}
+
+	if err := service.repository.Save(ctx, settlements); err != nil {
+		return err
+	}
+
+	return service.Scheduled(ctx, settlements)
+}
+
+// Plan builds the settlements of a paid invoice without saving them, for
+// callers that store them along with the invoice. Scheduled must be called
+// once they are saved.
+func (service *SettlementService) Plan(ctx context.Context, invoice *domain.Invoice) ([]*domain.Settlement, error) {
+	settlements := domain.NewSettlements(invoice, service.schedule, service.feeAccountID)
+
+	for _, settlement := range settlements {
+		account, err := service.accountRepository.FindByIDIncludingDeleted(ctx, settlement.AccountID)
+		if err != nil {
+			return nil, err
+		}
+
+		settlement.Withhold(account.ReservePolicy)
+	}
+
+	return settlements, nil
+}
+
+// Scheduled audits settlements that were just saved and releases the ones
+// already due. A release that fails is left for the release job.
+func (service *SettlementService) Scheduled(ctx context.Context, settlements []*domain.Settlement) error {
+	for _, settlement := range settlements {
+		service.auditService.Record(ctx, "settlement.scheduled", "settlement", settlement.ID, nil, settlement.Snapshot())
+
+		if settlement.Hold != nil {
+			service.auditService.Record(ctx, "balance_hold.placed", "balance_hold", settlement.Hold.ID, nil, settlement.Hold.Snapshot())
+		}
+
+		if settlement.IsDueAt(time.Now()) {
+			if err := service.release(ctx, settlement); err != nil {
+				log.Printf("[SettlementService] Error releasing settlement %s: %v", settlement.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (service *SettlementService) ReleaseDue(ctx context.Context) (int, error) {
+	settlements, err := service.repository.FindDue(ctx, time.Now(), settlementBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, settlement := range settlements {
+		if err := service.release(ctx, settlement); err != nil {
+			log.Printf("[SettlementService] Error releasing settlement %s: %v", settlement.ID, err)
+			continue
+		}
+
+		released++
+	}
+
+	if released > 0 {
+		log.Printf("[SettlementService] Released %d settlements", released)
+	}
+
+	return released, nil
+}
+
+func (service *SettlementService) Upcoming(ctx context.Context, accountID string) ([]domain.UpcomingSettlement, error) {
+	return service.repository.Upcoming(ctx, accountID, upcomingSettlementDays)
+}
+
+func (service *SettlementService) release(ctx context.Context, settlement *domain.Settlement) error {
+	before := settlement.Snapshot()
+
+	if err := settlement.Release(); err != nil {
+		return err
+	}
+
+	released, err := service.repository.Release(ctx, settlement)
+	if err != nil || !released {
+		return err
+	}
+
+	service.auditService.Record(ctx, "settlement.released", "settlement", settlement.ID, before, settlement.Snapshot())
+
+	return service.eventService.Publish(ctx, settlement.AccountID, "settlement.released", "settlement", settlement.ID, map[string]any{
+		"invoice_id":     settlement.InvoiceID,
+		"payment_method": settlement.PaymentMethod,
+		"amount":         settlement.Amount,
+		"fee":            settlement.Fee,
+		"available_on":   settlement.AvailableOn,
+	})
+}
@@ -0,0 +1,585 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+const (
+	subscriptionBillingBatchSize = 100
+	subscriptionDueInDays        = 3
+
+	// dunningLease is how long a claimed dunning step stays hidden from other
+	// runs; if the step fails it becomes due again once the lease runs out.
+	dunningLease = time.Hour
+)
+
+type SubscriptionService struct {
+	planRepository       repository.PlanRepository
+	repository           repository.SubscriptionRepository
+	customerService      *CustomerService
+	paymentMethodService *PaymentMethodService
+	invoiceService       *InvoiceService
+	eventService         *EventService
+	auditService         *AuditService
+	dunningPolicy        domain.DunningPolicy
+}
+
+func NewSubscriptionService(planRepository repository.PlanRepository, repository repository.SubscriptionRepository, customerService *CustomerService, paymentMethodService *PaymentMethodService, invoiceService *InvoiceService, eventService *EventService, auditService *AuditService, dunningPolicy domain.DunningPolicy) *SubscriptionService {
+	return &SubscriptionService{
+		planRepository:       planRepository,
+		repository:           repository,
+		customerService:      customerService,
+		paymentMethodService: paymentMethodService,
+		invoiceService:       invoiceService,
+		eventService:         eventService,
+		auditService:         auditService,
+		dunningPolicy:        dunningPolicy,
+	}
+}
+
+func (service *SubscriptionService) CreatePlan(ctx context.Context, accountID string, input dto.CreatePlanInput) (*dto.PlanOutput, error) {
+	plan, err := dto.ToPlan(input, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.planRepository.Save(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "plan.created", "plan", plan.ID, nil, plan.Snapshot())
+
+	output := dto.FromPlan(plan)
+
+	return &output, nil
+}
+
+func (service *SubscriptionService) ListPlans(ctx context.Context, accountID string) ([]dto.PlanOutput, error) {
+	plans, err := service.planRepository.FindByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.PlanOutput, len(plans))
+	for i, plan := range plans {
+		output[i] = dto.FromPlan(plan)
+	}
+
+	return output, nil
+}
+
+func (service *SubscriptionService) GetPlan(ctx context.Context, accountID, id string) (*dto.PlanOutput, error) {
+	plan, err := service.findPlan(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPlan(plan)
+
+	return &output, nil
+}
+
+// ArchivePlan stops new subscriptions to the plan; existing ones keep
+// renewing on it until they change plan or cancel.
+func (service *SubscriptionService) ArchivePlan(ctx context.Context, accountID, id string) (*dto.PlanOutput, error) {
+	plan, err := service.findPlan(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := plan.Snapshot()
+	if err := plan.Archive(); err != nil {
+		return nil, err
+	}
+
+	if err := service.planRepository.Update(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "plan.archived", "plan", plan.ID, before, plan.Snapshot())
+
+	output := dto.FromPlan(plan)
+
+	return &output, nil
+}
+
+func (service *SubscriptionService) Create(ctx context.Context, accountID string, input dto.CreateSubscriptionInput) (*dto.SubscriptionOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	customer, err := service.customerService.Find(ctx, accountID, input.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.IsDeleted() {
+		return nil, domain.ErrCustomerDeleted
+	}
+
+	if _, err := service.paymentMethodService.ForCharge(ctx, accountID, customer.ID, input.PaymentMethodID); err != nil {
+		return nil, err
+	}
+
+	plan, err := service.findPlan(ctx, accountID, input.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	trialDays := plan.TrialDays
+	if input.TrialDays != nil {
+		trialDays = *input.TrialDays
+	}
+
+	subscription, err := domain.NewSubscription(accountID, customer.ID, input.PaymentMethodID, plan, trialDays, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Save(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "subscription.created", "subscription", subscription.ID, nil, subscription.Snapshot())
+
+	output := dto.FromSubscription(subscription)
+
+	return &output, nil
+}
+
+func (service *SubscriptionService) Get(ctx context.Context, accountID, id string) (*dto.SubscriptionOutput, error) {
+	subscription, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromSubscription(subscription)
+
+	return &output, nil
+}
+
+func (service *SubscriptionService) Search(ctx context.Context, filter domain.SubscriptionFilter) ([]dto.SubscriptionOutput, error) {
+	subscriptions, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.SubscriptionOutput, len(subscriptions))
+	for i, subscription := range subscriptions {
+		output[i] = dto.FromSubscription(subscription)
+	}
+
+	return output, nil
+}
+
+func (service *SubscriptionService) ChangePlan(ctx context.Context, accountID, id string, input dto.ChangePlanInput) (*dto.SubscriptionOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	subscription, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := service.planRepository.FindByID(ctx, subscription.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := service.findPlan(ctx, accountID, input.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	prorate := input.Prorate == nil || *input.Prorate
+
+	return service.apply(ctx, subscription, "subscription.plan_changed", func(s *domain.Subscription) error {
+		return s.ChangePlan(from, to, prorate, time.Now())
+	})
+}
+
+func (service *SubscriptionService) Pause(ctx context.Context, accountID, id string) (*dto.SubscriptionOutput, error) {
+	subscription, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.apply(ctx, subscription, "subscription.paused", func(s *domain.Subscription) error {
+		return s.Pause(time.Now())
+	})
+}
+
+func (service *SubscriptionService) Resume(ctx context.Context, accountID, id string) (*dto.SubscriptionOutput, error) {
+	subscription, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.apply(ctx, subscription, "subscription.resumed", func(s *domain.Subscription) error {
+		return s.Resume(time.Now())
+	})
+}
+
+func (service *SubscriptionService) Cancel(ctx context.Context, accountID, id string, input dto.CancelSubscriptionInput) (*dto.SubscriptionOutput, error) {
+	subscription, err := service.find(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.apply(ctx, subscription, "subscription.cancelled", func(s *domain.Subscription) error {
+		return s.Cancel(input.AtPeriodEnd, time.Now())
+	})
+}
+
+// BillDue renews every subscription whose billing date has passed. Each
+// period is claimed with a conditional update before the invoice is created,
+// so concurrent runs never bill the same period twice.
+func (service *SubscriptionService) BillDue(ctx context.Context) (int, error) {
+	subscriptions, err := service.repository.FindDue(ctx, time.Now(), subscriptionBillingBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	billed := 0
+	for _, subscription := range subscriptions {
+		ok, err := service.bill(ctx, subscription)
+		if err != nil {
+			log.Printf("[SubscriptionService] Error billing subscription %s: %v", subscription.ID, err)
+			continue
+		}
+
+		if ok {
+			billed++
+		}
+	}
+
+	if billed > 0 {
+		log.Printf("[SubscriptionService] Renewed %d subscriptions", billed)
+	}
+
+	return billed, nil
+}
+
+func (service *SubscriptionService) bill(ctx context.Context, subscription *domain.Subscription) (bool, error) {
+	now := time.Now()
+	billedAt := subscription.NextBillingAt
+	before := subscription.Snapshot()
+
+	if subscription.CancelAtPeriodEnd {
+		if err := subscription.Cancel(false, now); err != nil {
+			return false, err
+		}
+
+		claimed, err := service.repository.UpdateIfDue(ctx, subscription, billedAt)
+		if err != nil || !claimed {
+			return false, err
+		}
+
+		service.auditService.Record(ctx, "subscription.cancelled", "subscription", subscription.ID, before, subscription.Snapshot())
+
+		return true, nil
+	}
+
+	plan, err := service.planRepository.FindByID(ctx, subscription.PlanID)
+	if err != nil {
+		return false, err
+	}
+
+	previous := *subscription
+	amount := subscription.StartPeriod(plan)
+
+	claimed, err := service.repository.UpdateIfDue(ctx, subscription, billedAt)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	var events []string
+	if amount > 0 {
+		invoice, err := service.invoiceService.CreateForSubscription(ctx, subscription, plan, amount, "", now.AddDate(0, 0, subscriptionDueInDays))
+		if err != nil {
+			code, ok := declineCodeFor(err)
+			if !ok {
+				// Hand the period back so the next run retries it.
+				if _, revertErr := service.repository.UpdateIfDue(ctx, &previous, subscription.NextBillingAt); revertErr != nil {
+					log.Printf("[SubscriptionService] Error releasing subscription %s: %v", subscription.ID, revertErr)
+				}
+
+				return false, err
+			}
+
+			subscription.PaymentFailed(service.dunningPolicy, amount, code, now)
+			events = append(events, "subscription.payment_failed")
+		} else {
+			subscription.FollowInvoice(invoice, service.dunningPolicy, now)
+
+			if invoice.Status == domain.StatusRejected {
+				events = append(events, "subscription.payment_failed")
+			}
+		}
+	} else if subscription.Status == domain.SubscriptionStatusTrialing {
+		subscription.Status = domain.SubscriptionStatusActive
+	}
+
+	if previous.Status != subscription.Status && subscription.Status == domain.SubscriptionStatusPastDue {
+		events = append(events, "subscription.past_due")
+	}
+
+	if err := service.repository.Update(ctx, subscription); err != nil {
+		return false, err
+	}
+
+	service.auditService.Record(ctx, "subscription.renewed", "subscription", subscription.ID, before, subscription.Snapshot())
+
+	return true, service.publish(ctx, subscription, events...)
+}
+
+// RetryDue runs the next dunning step of every past due subscription whose
+// retry date has passed.
+func (service *SubscriptionService) RetryDue(ctx context.Context) (int, error) {
+	subscriptions, err := service.repository.FindRetryDue(ctx, time.Now(), subscriptionBillingBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, subscription := range subscriptions {
+		ok, err := service.dun(ctx, subscription)
+		if err != nil {
+			log.Printf("[SubscriptionService] Error running dunning for subscription %s: %v", subscription.ID, err)
+			continue
+		}
+
+		if ok {
+			processed++
+		}
+	}
+
+	if processed > 0 {
+		log.Printf("[SubscriptionService] Ran %d dunning steps", processed)
+	}
+
+	return processed, nil
+}
+
+func (service *SubscriptionService) dun(ctx context.Context, subscription *domain.Subscription) (bool, error) {
+	now := time.Now()
+	step := subscription.DunningStep
+	retryAt := subscription.NextRetryAt
+	before := subscription.Snapshot()
+	status := subscription.Status
+
+	subscription.NextRetryAt = now.Add(dunningLease)
+
+	claimed, err := service.repository.UpdateIfRetryDue(ctx, subscription, retryAt)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	var events []string
+	switch step {
+	case domain.DunningStepRetry:
+		events, err = service.retryCharge(ctx, subscription, now)
+	case domain.DunningStepFallback:
+		events, err = service.issueFallback(ctx, subscription, now)
+	case domain.DunningStepRemind:
+		events, err = service.remind(ctx, subscription, now)
+	default:
+		events, err = service.exhaust(ctx, subscription, now)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if status != subscription.Status {
+		events = append(events, "subscription."+string(subscription.Status))
+	}
+
+	if err := service.repository.Update(ctx, subscription); err != nil {
+		return false, err
+	}
+
+	service.auditService.Record(ctx, "subscription.dunning_"+string(step), "subscription", subscription.ID, before, subscription.Snapshot())
+
+	return true, service.publish(ctx, subscription, events...)
+}
+
+func (service *SubscriptionService) retryCharge(ctx context.Context, subscription *domain.Subscription, now time.Time) ([]string, error) {
+	plan, err := service.planRepository.FindByID(ctx, subscription.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := service.invoiceService.CreateForSubscription(ctx, subscription, plan, subscription.DunningAmount, "", now.AddDate(0, 0, subscriptionDueInDays))
+	if err != nil {
+		code, ok := declineCodeFor(err)
+		if !ok {
+			return nil, err
+		}
+
+		subscription.RetryFailed(service.dunningPolicy, code, now)
+
+		return []string{"subscription.payment_failed"}, nil
+	}
+
+	switch invoice.Status {
+	case domain.StatusApproved:
+		subscription.PaymentRecovered(invoice, now)
+		return []string{"subscription.payment_recovered"}, nil
+	case domain.StatusRejected:
+		subscription.LatestInvoiceID = invoice.ID
+		subscription.RetryFailed(service.dunningPolicy, invoice.DeclineCode, now)
+		return []string{"subscription.payment_failed"}, nil
+	default:
+		subscription.FallbackIssued(invoice, now)
+		return []string{"subscription.payment_action_required"}, nil
+	}
+}
+
+func (service *SubscriptionService) issueFallback(ctx context.Context, subscription *domain.Subscription, now time.Time) ([]string, error) {
+	plan, err := service.planRepository.FindByID(ctx, subscription.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate := now.AddDate(0, 0, service.dunningPolicy.FallbackDays)
+
+	invoice, err := service.invoiceService.CreateForSubscription(ctx, subscription, plan, subscription.DunningAmount, service.dunningPolicy.FallbackMethod, dueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status == domain.StatusApproved {
+		subscription.PaymentRecovered(invoice, now)
+		return []string{"subscription.payment_recovered"}, nil
+	}
+
+	subscription.FallbackIssued(invoice, now)
+
+	return []string{"subscription.payment_action_required"}, nil
+}
+
+func (service *SubscriptionService) remind(ctx context.Context, subscription *domain.Subscription, now time.Time) ([]string, error) {
+	invoice, err := service.invoiceService.Find(ctx, subscription.AccountID, subscription.LatestInvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status == domain.StatusApproved {
+		subscription.PaymentRecovered(invoice, now)
+		return []string{"subscription.payment_recovered"}, nil
+	}
+
+	subscription.ReminderSent(invoice, now)
+
+	return []string{"subscription.payment_reminder"}, nil
+}
+
+func (service *SubscriptionService) exhaust(ctx context.Context, subscription *domain.Subscription, now time.Time) ([]string, error) {
+	if subscription.LatestInvoiceID != "" {
+		invoice, err := service.invoiceService.Find(ctx, subscription.AccountID, subscription.LatestInvoiceID)
+		if err != nil {
+			return nil, err
+		}
+
+		if invoice.Status == domain.StatusApproved {
+			subscription.PaymentRecovered(invoice, now)
+			return []string{"subscription.payment_recovered"}, nil
+		}
+	}
+
+	subscription.ExhaustDunning(service.dunningPolicy, now)
+
+	return []string{"subscription.dunning_exhausted"}, nil
+}
+
+func (service *SubscriptionService) publish(ctx context.Context, subscription *domain.Subscription, events ...string) error {
+	for _, event := range events {
+		data := map[string]any{
+			"status":            subscription.Status,
+			"customer_id":       subscription.CustomerID,
+			"latest_invoice_id": subscription.LatestInvoiceID,
+		}
+
+		if subscription.InDunning() {
+			data["amount_due"] = subscription.DunningAmount
+			data["attempts"] = subscription.DunningAttempts
+			data["next_step"] = subscription.DunningStep
+			data["next_attempt_at"] = subscription.NextRetryAt
+		}
+
+		if err := service.eventService.Publish(ctx, subscription.AccountID, event, "subscription", subscription.ID, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// declineCodeFor turns a failure to even create a recurring charge into a
+// decline so dunning can take over; any other error is left for the next run.
+func declineCodeFor(err error) (domain.DeclineCode, bool) {
+	switch {
+	case domain.KindOf(err) == domain.KindProviderUnavailable:
+		return domain.DeclineProviderUnavailable, true
+	case errors.Is(err, domain.ErrPaymentMethodNotFound),
+		errors.Is(err, domain.ErrPaymentMethodExpired),
+		errors.Is(err, domain.ErrPaymentMethodMismatch),
+		errors.Is(err, domain.ErrNoDefaultPaymentMethod):
+		return domain.DeclinePaymentMethodUnavailable, true
+	default:
+		return "", false
+	}
+}
+
+func (service *SubscriptionService) apply(ctx context.Context, subscription *domain.Subscription, action string, change func(*domain.Subscription) error) (*dto.SubscriptionOutput, error) {
+	before := subscription.Snapshot()
+
+	if err := change(subscription); err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, action, "subscription", subscription.ID, before, subscription.Snapshot())
+
+	output := dto.FromSubscription(subscription)
+
+	return &output, nil
+}
+
+func (service *SubscriptionService) findPlan(ctx context.Context, accountID, id string) (*domain.Plan, error) {
+	plan, err := service.planRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.AccountID != accountID {
+		return nil, domain.ErrPlanNotFound
+	}
+
+	return plan, nil
+}
+
+func (service *SubscriptionService) find(ctx context.Context, accountID, id string) (*domain.Subscription, error) {
+	subscription, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.AccountID != accountID {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/repository"
+)
+
+type TransferService struct {
+	repository     repository.TransferRepository
+	accountService AccountService
+	eventService   *EventService
+	auditService   *AuditService
+}
+
+func NewTransferService(repository repository.TransferRepository, accountService AccountService, eventService *EventService, auditService *AuditService) *TransferService {
+	return &TransferService{
+		repository:     repository,
+		accountService: accountService,
+		eventService:   eventService,
+		auditService:   auditService,
+	}
+}
+
+// Create moves available balance from sourceAccountID to another account.
+// Retrying with the same idempotency key returns the original transfer
+// instead of moving the money twice.
+func (service *TransferService) Create(ctx context.Context, sourceAccountID, idempotencyKey string, input dto.CreateTransferInput) (*dto.TransferOutput, error) {
+	transfer, err := dto.ToTransfer(input, sourceAccountID, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, err := service.findRetry(ctx, transfer); existing != nil || err != nil {
+		return service.output(existing, err)
+	}
+
+	err = service.accountService.CheckActive(ctx, transfer.DestinationAccountID)
+	if errors.Is(err, domain.ErrAccountNotFound) || errors.Is(err, domain.ErrAccountFrozen) {
+		return nil, domain.ErrInvalidTransferAccount
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repository.Save(ctx, transfer); err != nil {
+		// A concurrent request with the same key got there first.
+		if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+			if existing, findErr := service.findRetry(ctx, transfer); existing != nil || findErr != nil {
+				return service.output(existing, findErr)
+			}
+		}
+
+		return nil, err
+	}
+
+	service.auditService.Record(ctx, "transfer.created", "transfer", transfer.ID, nil, transfer.Snapshot())
+
+	if err := service.publish(ctx, transfer, "transfer.created", "transfer.received"); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromTransfer(transfer)
+
+	return &output, nil
+}
+
+// CreateForOperator is a transfer started by an operator from any account,
+// such as a platform-to-seller adjustment.
+func (service *TransferService) CreateForOperator(ctx context.Context, idempotencyKey string, input dto.AdminTransferInput) (*dto.TransferOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return service.Create(ctx, input.SourceAccountID, idempotencyKey, input.CreateTransferInput)
+}
+
+// findRetry returns the transfer a previous request with the same
+// idempotency key created, if any.
+func (service *TransferService) findRetry(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+	if transfer.IdempotencyKey == "" {
+		return nil, nil
+	}
+
+	existing, err := service.repository.FindByIdempotencyKey(ctx, transfer.SourceAccountID, transfer.IdempotencyKey)
+	if errors.Is(err, domain.ErrTransferNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !existing.Matches(transfer) {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+
+	return existing, nil
+}
+
+// Reverse sends the amount back to the source account. Only the source
+// account can reverse a transfer; an empty accountID is an operator acting
+// on any transfer.
+func (service *TransferService) Reverse(ctx context.Context, accountID, id string, input dto.ReverseTransferInput) (*dto.TransferOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	transfer, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if accountID != "" && transfer.SourceAccountID != accountID {
+		return nil, domain.ErrTransferNotFound
+	}
+
+	before := transfer.Snapshot()
+
+	if err := transfer.Reverse(input.Reason); err != nil {
+		return nil, err
+	}
+
+	reversed, err := service.repository.Reverse(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reversed {
+		return nil, domain.ErrInvalidTransferStatus
+	}
+
+	service.auditService.Record(ctx, "transfer.reversed", "transfer", transfer.ID, before, transfer.Snapshot())
+
+	if err := service.publish(ctx, transfer, "transfer.reversed", "transfer.reversed"); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromTransfer(transfer)
+
+	return &output, nil
+}
+
+func (service *TransferService) Get(ctx context.Context, accountID, id string) (*dto.TransferOutput, error) {
+	transfer, err := service.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.SourceAccountID != accountID && transfer.DestinationAccountID != accountID {
+		return nil, domain.ErrTransferNotFound
+	}
+
+	return service.output(transfer, nil)
+}
+
+func (service *TransferService) Search(ctx context.Context, filter domain.TransferFilter) ([]dto.TransferOutput, error) {
+	transfers, err := service.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]dto.TransferOutput, len(transfers))
+	for i, transfer := range transfers {
+		output[i] = dto.FromTransfer(transfer)
+	}
+
+	return output, nil
+}
+
+func (service *TransferService) publish(ctx context.Context, transfer *domain.Transfer, sourceEvent, destinationEvent string) error {
+	data := map[string]any{
+		"source_account_id":      transfer.SourceAccountID,
+		"destination_account_id": transfer.DestinationAccountID,
+		"amount":                 transfer.Amount,
+		"description":            transfer.Description,
+		"metadata":               transfer.Metadata,
+		"status":                 transfer.Status,
+	}
+
+	if err := service.eventService.Publish(ctx, transfer.SourceAccountID, sourceEvent, "transfer", transfer.ID, data); err != nil {
+		return err
+	}
+
+	return service.eventService.Publish(ctx, transfer.DestinationAccountID, destinationEvent, "transfer", transfer.ID, data)
+}
+
+func (service *TransferService) output(transfer *domain.Transfer, err error) (*dto.TransferOutput, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromTransfer(transfer)
+
+	return &output, nil
+}
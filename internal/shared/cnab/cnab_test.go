@@ -0,0 +1,225 @@
+package cnab
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testCompany = Company{
+	BankCode:     "077",
+	BankName:     "Banco Inter",
+	Name:         "Loja Exemplo Ltda",
+	TaxID:        "11.222.333/0001-81",
+	Agreement:    "123456",
+	Branch:       "0001",
+	BranchDigit:  "9",
+	Account:      "1234567",
+	AccountDigit: "0",
+	Wallet:       "1",
+}
+
+func TestWriteRemittance(t *testing.T) {
+	generatedAt := time.Date(2030, 1, 15, 10, 30, 0, 0, time.Local)
+	dueDate := time.Date(2030, 1, 31, 0, 0, 0, 0, time.Local)
+
+	titles := []Title{
+		{
+			OurNumber:      "00000000001",
+			DocumentNumber: "PEDIDO-1",
+			IssueDate:      generatedAt,
+			DueDate:        dueDate,
+			Amount:         150.5,
+			Interest:       Rate{Percent: true, Value: 1},
+			Payer: Payer{
+				TaxID:   "529.982.247-25",
+				Name:    "José da Conceição",
+				ZipCode: "01310-100",
+				City:    "São Paulo",
+				State:   "SP",
+			},
+		},
+		{
+			OurNumber:      "00000000002",
+			DocumentNumber: "PEDIDO-2",
+			IssueDate:      generatedAt,
+			DueDate:        dueDate,
+			Amount:         49.5,
+			Payer:          Payer{TaxID: "11222333000181", Name: "Empresa Pagadora", ZipCode: "20040002"},
+		},
+	}
+
+	var content bytes.Buffer
+	if err := WriteRemittance(&content, testCompany, 42, generatedAt, titles); err != nil {
+		t.Fatalf("WriteRemittance() error = %v", err)
+	}
+
+	if !strings.HasSuffix(content.String(), lineEnding) {
+		t.Fatal("remittance does not end with CRLF")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content.String(), lineEnding), lineEnding)
+	if len(lines) != 8 {
+		t.Fatalf("got %d lines, want 8 (2 headers, 2 titles with P and Q, 2 trailers)", len(lines))
+	}
+
+	for i, line := range lines {
+		if len(line) != lineLength {
+			t.Errorf("line %d has %d characters, want %d", i+1, len(line), lineLength)
+		}
+	}
+
+	tests := []struct {
+		line       int
+		start, end int
+		want       string
+	}{
+		{1, 1, 3, "077"},
+		{1, 8, 8, "0"},
+		{1, 18, 18, "2"},
+		{1, 19, 32, "11222333000181"},
+		{1, 143, 143, "1"},
+		{1, 144, 157, "15012030103000"},
+		{1, 158, 163, "000042"},
+		{2, 8, 9, "1R"},
+		{2, 184, 191, "00000042"},
+		{3, 9, 13, "00001"},
+		{3, 14, 14, "P"},
+		{3, 18, 22, "00001"},
+		{3, 38, 57, "00000000001         "},
+		{3, 63, 77, "PEDIDO-1       "},
+		{3, 78, 85, "31012030"},
+		{3, 86, 100, "000000000015050"},
+		{3, 118, 126, "201022030"},
+		{3, 142, 142, "0"},
+		{4, 9, 13, "00002"},
+		{4, 14, 14, "Q"},
+		{4, 18, 33, "1000052998224725"},
+		{4, 34, 53, "JOSE DA CONCEICAO   "},
+		{4, 129, 136, "01310100"},
+		{4, 137, 151, "SAO PAULO      "},
+		{6, 18, 18, "2"},
+		{6, 129, 136, "20040002"},
+		{5, 118, 118, "3"},
+		{7, 8, 8, "5"},
+		{7, 18, 23, "000006"},
+		{7, 24, 29, "000002"},
+		{7, 30, 46, "00000000000020000"},
+		{8, 8, 8, "9"},
+		{8, 18, 23, "000001"},
+		{8, 24, 29, "000008"},
+	}
+
+	for _, tt := range tests {
+		if got := field(lines[tt.line-1], tt.start, tt.end); got != tt.want {
+			t.Errorf("line %d positions %d-%d = %q, want %q", tt.line, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+// returnLine builds a return record with the given fields, each as
+// {start, end, value} in FEBRABAN positions.
+func returnLine(segment string, fields ...any) string {
+	line := newRecord()
+	line.num(1, 3, "077")
+
+	switch segment {
+	case "header":
+		line.num(8, 8, "0")
+	case "batch":
+		line.num(8, 8, "1")
+	case "T", "U":
+		line.num(8, 8, "3")
+		line.alpha(14, 14, segment)
+	case "trailer":
+		line.num(8, 8, "9")
+	}
+
+	for i := 0; i < len(fields); i += 3 {
+		line.put(fields[i].(int), fields[i+1].(int), fields[i+2].(string))
+	}
+
+	return line.String()
+}
+
+func returnFile(lines ...string) *strings.Reader {
+	return strings.NewReader(strings.Join(lines, lineEnding) + lineEnding)
+}
+
+func TestParseReturn(t *testing.T) {
+	content := returnFile(
+		returnLine("header", 143, 143, "2", 144, 157, "16012030083000", 158, 163, "000007"),
+		returnLine("batch"),
+		returnLine("T", 16, 17, "06", 38, 57, "00000000001", 59, 73, "PEDIDO-1", 74, 81, "31012030", 82, 96, "000000000015050", 199, 213, "000000000000349"),
+		returnLine("U", 78, 92, "000000000015050", 93, 107, "000000000014701", 138, 145, "15012030", 146, 153, "16012030"),
+		returnLine("T", 16, 17, "03", 38, 57, "00000000002", 82, 96, "000000000004950", 214, 223, "0810"),
+		returnLine("U", 78, 92, "000000000000000"),
+		returnLine("trailer"),
+	)
+
+	result, err := ParseReturn(content)
+	if err != nil {
+		t.Fatalf("ParseReturn() error = %v", err)
+	}
+
+	if result.BankCode != "077" || result.Sequence != 7 {
+		t.Errorf("header = %q/%d, want 077/7", result.BankCode, result.Sequence)
+	}
+
+	if want := time.Date(2030, 1, 16, 8, 30, 0, 0, time.Local); !result.GeneratedAt.Equal(want) {
+		t.Errorf("GeneratedAt = %v, want %v", result.GeneratedAt, want)
+	}
+
+	if len(result.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(result.Items))
+	}
+
+	paid := result.Items[0]
+	if !paid.IsPaid() || paid.OurNumber != "00000000001" || paid.DocumentNumber != "PEDIDO-1" {
+		t.Errorf("paid item = %+v", paid)
+	}
+
+	if paid.Amount != 150.5 || paid.Fee != 3.49 || paid.PaidAmount != 150.5 || paid.CreditedAmount != 147.01 {
+		t.Errorf("paid item amounts = %v/%v/%v/%v, want 150.5/3.49/150.5/147.01", paid.Amount, paid.Fee, paid.PaidAmount, paid.CreditedAmount)
+	}
+
+	if want := time.Date(2030, 1, 15, 0, 0, 0, 0, time.Local); !paid.OccurredAt.Equal(want) {
+		t.Errorf("OccurredAt = %v, want %v", paid.OccurredAt, want)
+	}
+
+	rejected := result.Items[1]
+	if rejected.IsPaid() || rejected.Movement != MovementEntryRejected || rejected.Reasons != "0810" {
+		t.Errorf("rejected item = %+v", rejected)
+	}
+
+	if !rejected.DueDate.IsZero() || !rejected.CreditedAt.IsZero() {
+		t.Errorf("rejected item dates = %v/%v, want none", rejected.DueDate, rejected.CreditedAt)
+	}
+}
+
+func TestParseReturnRejectsInvalidFiles(t *testing.T) {
+	header := returnLine("header", 143, 143, "2", 144, 157, "16012030083000", 158, 163, "000007")
+
+	tests := []struct {
+		name    string
+		content *strings.Reader
+	}{
+		{"short line", returnFile(header, returnLine("T")[:200])},
+		{"segment U without segment T", returnFile(header, returnLine("U"))},
+		{"two segments U for one title", returnFile(header, returnLine("T"), returnLine("U"), returnLine("U"))},
+		{"remittance instead of return", returnFile(returnLine("header", 143, 143, "1"))},
+		{"missing header", returnFile(returnLine("T"), returnLine("U"))},
+		{"invalid amount", returnFile(header, returnLine("T", 82, 96, "00000000000AB50"))},
+		{"empty file", strings.NewReader("")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseReturn(tt.content); !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("ParseReturn() error = %v, want ErrInvalidFile", err)
+			}
+		})
+	}
+}
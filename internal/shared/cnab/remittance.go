@@ -0,0 +1,234 @@
+package cnab
+
+import (
+	"io"
+	"time"
+)
+
+// Title is a boleto to register with the bank.
+type Title struct {
+	OurNumber      string
+	DocumentNumber string
+	CompanyUse     string
+	IssueDate      time.Time
+	DueDate        time.Time
+	Amount         float64
+	Interest       Rate
+	Discount       Rate
+	DiscountUntil  time.Time
+	Payer          Payer
+}
+
+// Rate is a fixed amount or a percentage: per day or per month for interest,
+// off the amount for discounts. A zero rate means none.
+type Rate struct {
+	Percent bool
+	Value   float64
+}
+
+type Payer struct {
+	TaxID    string
+	Name     string
+	Address  string
+	District string
+	ZipCode  string
+	City     string
+	State    string
+}
+
+// WriteRemittance writes a CNAB 240 remittance with a single batch that
+// registers every title. sequence is the file number (NSA), which the bank
+// expects to grow by one with each remittance.
+func WriteRemittance(w io.Writer, company Company, sequence int, generatedAt time.Time, titles []Title) error {
+	lines := []record{fileHeader(company, sequence, generatedAt), batchHeader(company, sequence, generatedAt)}
+
+	var total float64
+	for i, title := range titles {
+		lines = append(lines, segmentP(company, 2*i+1, title), segmentQ(company, 2*i+2, title))
+		total += title.Amount
+	}
+
+	lines = append(lines, batchTrailer(company, len(lines), len(titles), total))
+	lines = append(lines, fileTrailer(company, len(lines)+1))
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line.String()+lineEnding); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// account writes branch, account and their check digits, which sit at the
+// same width in every record from the given position on.
+func (line record) account(start int, company Company) {
+	line.num(start, start+4, company.Branch)
+	line.alpha(start+5, start+5, company.BranchDigit)
+	line.num(start+6, start+17, company.Account)
+	line.alpha(start+18, start+18, company.AccountDigit)
+}
+
+func fileHeader(company Company, sequence int, generatedAt time.Time) record {
+	line := newRecord()
+	line.num(1, 3, company.BankCode)
+	line.num(4, 7, "0000")
+	line.num(8, 8, "0")
+	line.int(18, 18, inscriptionType(company.TaxID))
+	line.num(19, 32, company.TaxID)
+	line.alpha(33, 52, company.Agreement)
+	line.account(53, company)
+	line.alpha(73, 102, company.Name)
+	line.alpha(103, 132, company.BankName)
+	line.num(143, 143, "1")
+	line.date(144, 151, generatedAt)
+	line.num(152, 157, generatedAt.Format(timeLayout))
+	line.int(158, 163, sequence)
+	line.num(164, 166, "087")
+	line.num(167, 171, "")
+
+	return line
+}
+
+func batchHeader(company Company, sequence int, generatedAt time.Time) record {
+	line := newRecord()
+	line.num(1, 3, company.BankCode)
+	line.num(4, 7, "0001")
+	line.num(8, 8, "1")
+	line.alpha(9, 9, "R")
+	line.num(10, 11, "01")
+	line.num(14, 16, "045")
+	line.int(18, 18, inscriptionType(company.TaxID))
+	line.num(19, 33, company.TaxID)
+	line.alpha(34, 53, company.Agreement)
+	line.account(54, company)
+	line.alpha(74, 103, company.Name)
+	line.int(184, 191, sequence)
+	line.date(192, 199, generatedAt)
+	line.num(200, 207, "")
+
+	return line
+}
+
+func segmentP(company Company, number int, title Title) record {
+	line := newRecord()
+	line.num(1, 3, company.BankCode)
+	line.num(4, 7, "0001")
+	line.num(8, 8, "3")
+	line.int(9, 13, number)
+	line.alpha(14, 14, "P")
+	line.num(16, 17, "01")
+	line.account(18, company)
+	line.alpha(38, 57, title.OurNumber)
+	line.num(58, 58, company.Wallet)
+	line.num(59, 59, "1")
+	line.num(60, 60, "1")
+	line.num(61, 61, "2")
+	line.num(62, 62, "2")
+	line.alpha(63, 77, title.DocumentNumber)
+	line.date(78, 85, title.DueDate)
+	line.money(86, 100, title.Amount)
+	line.num(101, 105, "")
+	line.num(106, 106, "")
+	line.num(107, 108, "02")
+	line.alpha(109, 109, "N")
+	line.date(110, 117, title.IssueDate)
+
+	switch {
+	case title.Interest.Value <= 0:
+		line.num(118, 118, "3")
+		line.num(119, 126, "")
+		line.num(127, 141, "")
+	case title.Interest.Percent:
+		line.num(118, 118, "2")
+		line.date(119, 126, title.DueDate.AddDate(0, 0, 1))
+		line.money(127, 141, title.Interest.Value)
+	default:
+		line.num(118, 118, "1")
+		line.date(119, 126, title.DueDate.AddDate(0, 0, 1))
+		line.money(127, 141, title.Interest.Value)
+	}
+
+	switch {
+	case title.Discount.Value <= 0:
+		line.num(142, 142, "0")
+		line.num(143, 150, "")
+		line.num(151, 165, "")
+	case title.Discount.Percent:
+		line.num(142, 142, "2")
+		line.date(143, 150, title.DiscountUntil)
+		line.money(151, 165, title.Discount.Value)
+	default:
+		line.num(142, 142, "1")
+		line.date(143, 150, title.DiscountUntil)
+		line.money(151, 165, title.Discount.Value)
+	}
+
+	line.num(166, 180, "")
+	line.num(181, 195, "")
+	line.alpha(196, 220, title.CompanyUse)
+	line.num(221, 221, "3")
+	line.num(222, 223, "00")
+	line.num(224, 224, "1")
+	line.num(225, 227, "060")
+	line.num(228, 229, "09")
+	line.num(230, 239, "")
+
+	return line
+}
+
+func segmentQ(company Company, number int, title Title) record {
+	zipCode := digits(title.Payer.ZipCode)
+	for len(zipCode) < 8 {
+		zipCode = "0" + zipCode
+	}
+
+	line := newRecord()
+	line.num(1, 3, company.BankCode)
+	line.num(4, 7, "0001")
+	line.num(8, 8, "3")
+	line.int(9, 13, number)
+	line.alpha(14, 14, "Q")
+	line.num(16, 17, "01")
+	line.int(18, 18, inscriptionType(title.Payer.TaxID))
+	line.num(19, 33, title.Payer.TaxID)
+	line.alpha(34, 73, title.Payer.Name)
+	line.alpha(74, 113, title.Payer.Address)
+	line.alpha(114, 128, title.Payer.District)
+	line.num(129, 133, zipCode[:5])
+	line.num(134, 136, zipCode[5:8])
+	line.alpha(137, 151, title.Payer.City)
+	line.alpha(152, 153, title.Payer.State)
+	line.num(154, 154, "0")
+	line.num(155, 169, "")
+	line.num(210, 212, "000")
+
+	return line
+}
+
+// batchTrailer closes the batch; records counts its header, details and
+// this trailer.
+func batchTrailer(company Company, records, titles int, total float64) record {
+	line := newRecord()
+	line.num(1, 3, company.BankCode)
+	line.num(4, 7, "0001")
+	line.num(8, 8, "5")
+	line.int(18, 23, records)
+	line.int(24, 29, titles)
+	line.money(30, 46, total)
+	line.num(47, 115, "")
+
+	return line
+}
+
+func fileTrailer(company Company, records int) record {
+	line := newRecord()
+	line.num(1, 3, company.BankCode)
+	line.num(4, 7, "9999")
+	line.num(8, 8, "9")
+	line.int(18, 23, 1)
+	line.int(24, 29, records)
+	line.num(30, 35, "")
+
+	return line
+}
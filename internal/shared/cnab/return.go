@@ -0,0 +1,186 @@
+package cnab
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Movement codes the bank reports for each title in a return file.
+const (
+	MovementEntryConfirmed    = "02"
+	MovementEntryRejected     = "03"
+	MovementPaid              = "06"
+	MovementWrittenOff        = "09"
+	MovementPaidAfterWriteOff = "17"
+)
+
+// Return is a parsed CNAB 240 return file.
+type Return struct {
+	BankCode    string
+	Sequence    int
+	GeneratedAt time.Time
+	Items       []ReturnItem
+}
+
+// ReturnItem joins the T and U segments the bank sends for a title.
+type ReturnItem struct {
+	Movement       string
+	OurNumber      string
+	DocumentNumber string
+	DueDate        time.Time
+	Amount         float64
+	Fee            float64
+	Reasons        string
+	PaidAmount     float64
+	CreditedAmount float64
+	OccurredAt     time.Time
+	CreditedAt     time.Time
+}
+
+func (item ReturnItem) IsPaid() bool {
+	return item.Movement == MovementPaid || item.Movement == MovementPaidAfterWriteOff
+}
+
+// ParseReturn reads a CNAB 240 return file. Segments other than T and U are
+// skipped; a U segment must follow the T segment of its title.
+func ParseReturn(r io.Reader) (*Return, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1024), 1024)
+
+	result := &Return{}
+	var current *ReturnItem
+
+	number := 0
+	for scanner.Scan() {
+		number++
+
+		line := strings.TrimRight(scanner.Text(), "\r\n\x1a")
+		if line == "" {
+			continue
+		}
+
+		if len(line) != lineLength {
+			return nil, fmt.Errorf("%w: line %d has %d characters, expected %d", ErrInvalidFile, number, len(line), lineLength)
+		}
+
+		var err error
+		switch field(line, 8, 8) {
+		case "0":
+			err = result.header(line)
+		case "3":
+			current, err = result.detail(line, current)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, number, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	if result.BankCode == "" {
+		return nil, fmt.Errorf("%w: missing file header", ErrInvalidFile)
+	}
+
+	return result, nil
+}
+
+func (result *Return) header(line string) error {
+	if field(line, 143, 143) != "2" {
+		return errors.New("not a return file")
+	}
+
+	sequence, err := parseInt(line, 158, 163)
+	if err != nil {
+		return errors.New("invalid file sequence")
+	}
+
+	generatedAt, err := time.ParseInLocation(dateLayout+timeLayout, field(line, 144, 157), time.Local)
+	if err != nil {
+		generatedAt, _ = parseDate(line, 144, 151)
+	}
+
+	result.BankCode = field(line, 1, 3)
+	result.Sequence = sequence
+	result.GeneratedAt = generatedAt
+
+	return nil
+}
+
+// detail reads a T or U segment and returns the title being assembled.
+func (result *Return) detail(line string, current *ReturnItem) (*ReturnItem, error) {
+	switch field(line, 14, 14) {
+	case "T":
+		item, err := segmentT(line)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Items = append(result.Items, item)
+
+		return &result.Items[len(result.Items)-1], nil
+	case "U":
+		if current == nil {
+			return nil, errors.New("segment U without a preceding segment T")
+		}
+
+		if err := current.segmentU(line); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return current, nil
+}
+
+func segmentT(line string) (ReturnItem, error) {
+	item := ReturnItem{
+		Movement:       field(line, 16, 17),
+		OurNumber:      strings.TrimSpace(field(line, 38, 57)),
+		DocumentNumber: strings.TrimSpace(field(line, 59, 73)),
+		Reasons:        strings.TrimSpace(field(line, 214, 223)),
+	}
+
+	var err error
+	if item.DueDate, err = parseDate(line, 74, 81); err != nil {
+		return item, errors.New("invalid due date")
+	}
+
+	if item.Amount, err = parseMoney(line, 82, 96); err != nil {
+		return item, errors.New("invalid title amount")
+	}
+
+	if item.Fee, err = parseMoney(line, 199, 213); err != nil {
+		return item, errors.New("invalid fee")
+	}
+
+	return item, nil
+}
+
+func (item *ReturnItem) segmentU(line string) error {
+	var err error
+	if item.PaidAmount, err = parseMoney(line, 78, 92); err != nil {
+		return errors.New("invalid paid amount")
+	}
+
+	if item.CreditedAmount, err = parseMoney(line, 93, 107); err != nil {
+		return errors.New("invalid credited amount")
+	}
+
+	if item.OccurredAt, err = parseDate(line, 138, 145); err != nil {
+		return errors.New("invalid occurrence date")
+	}
+
+	if item.CreditedAt, err = parseDate(line, 146, 153); err != nil {
+		return errors.New("invalid credit date")
+	}
+
+	return nil
+}
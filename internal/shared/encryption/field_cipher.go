@@ -0,0 +1,158 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+type FieldCipher struct {
+	keys     KeyManager
+	indexKey []byte
+}
+
+// Envelope holds the per-row data key. Fields are sealed with it and bound to
+// the row id, so a ciphertext copied into another row fails to open.
+type Envelope struct {
+	KeyID      string
+	WrappedKey string
+	rowID      string
+	dataKey    []byte
+}
+
+func NewFieldCipher(keys KeyManager, indexKey []byte) *FieldCipher {
+	return &FieldCipher{
+		keys:     keys,
+		indexKey: indexKey,
+	}
+}
+
+func (c *FieldCipher) CurrentKeyID() string {
+	return c.keys.CurrentKeyID()
+}
+
+func (c *FieldCipher) NewEnvelope(rowID string) (*Envelope, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	keyID, wrapped, err := c.keys.Wrap(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		KeyID:      keyID,
+		WrappedKey: base64.StdEncoding.EncodeToString(wrapped),
+		rowID:      rowID,
+		dataKey:    dataKey,
+	}, nil
+}
+
+func (c *FieldCipher) OpenEnvelope(rowID, keyID, wrappedKey string) (*Envelope, error) {
+	wrapped, err := base64.StdEncoding.DecodeString(wrappedKey)
+	if err != nil {
+		return nil, ErrInvalidCipher
+	}
+
+	dataKey, err := c.keys.Unwrap(keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		KeyID:      keyID,
+		WrappedKey: wrappedKey,
+		rowID:      rowID,
+		dataKey:    dataKey,
+	}, nil
+}
+
+// Rewrap moves the envelope to the current key without touching the sealed
+// fields, which is all a key rotation needs.
+func (c *FieldCipher) Rewrap(envelope *Envelope) (*Envelope, error) {
+	keyID, wrapped, err := c.keys.Wrap(envelope.dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		KeyID:      keyID,
+		WrappedKey: base64.StdEncoding.EncodeToString(wrapped),
+		rowID:      envelope.rowID,
+		dataKey:    envelope.dataKey,
+	}, nil
+}
+
+func (c *FieldCipher) BlindIndex(value string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return -1
+	}, value)
+
+	if normalized == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(normalized))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (envelope *Envelope) Encrypt(field, value string) (string, error) {
+	sealed, err := seal(envelope.dataKey, []byte(value), []byte(envelope.rowID+"|"+field))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (envelope *Envelope) Decrypt(field, value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", ErrInvalidCipher
+	}
+
+	plaintext, err := open(envelope.dataKey, sealed, []byte(envelope.rowID+"|"+field))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func (envelope *Envelope) EncryptFields(fields map[string]*string) error {
+	for name, value := range fields {
+		encrypted, err := envelope.Encrypt(name, *value)
+		if err != nil {
+			return err
+		}
+
+		*value = encrypted
+	}
+
+	return nil
+}
+
+func (envelope *Envelope) DecryptFields(fields map[string]*string) error {
+	for name, value := range fields {
+		decrypted, err := envelope.Decrypt(name, *value)
+		if err != nil {
+			return err
+		}
+
+		*value = decrypted
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	ErrUnknownKey      = errors.New("unknown encryption key")
+	ErrInvalidKeyring  = errors.New("invalid keyring")
+	ErrInvalidCipher   = errors.New("invalid ciphertext")
+	ErrMissingIndexKey = errors.New("keyring has no index key")
+)
+
+// KeyManager wraps and unwraps data keys with versioned key encryption keys.
+// FileKeyManager covers local setups; a KMS client only needs to implement
+// these three methods.
+type KeyManager interface {
+	CurrentKeyID() string
+	Wrap(dataKey []byte) (keyID string, wrapped []byte, err error)
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+type Keyring struct {
+	Current  string            `json:"current"`
+	Keys     map[string]string `json:"keys"`
+	IndexKey string            `json:"index_key"`
+}
+
+type FileKeyManager struct {
+	current  string
+	keys     map[string][]byte
+	indexKey []byte
+}
+
+func GenerateKey() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func LoadKeyring(path string) (*Keyring, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyring Keyring
+	if err := json.Unmarshal(b, &keyring); err != nil {
+		return nil, err
+	}
+
+	return &keyring, nil
+}
+
+func (keyring *Keyring) Save(path string) error {
+	b, err := json.MarshalIndent(keyring, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0600)
+}
+
+func NewFileKeyManager(path string) (*FileKeyManager, error) {
+	keyring, err := LoadKeyring(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := &FileKeyManager{
+		current: keyring.Current,
+		keys:    make(map[string][]byte, len(keyring.Keys)),
+	}
+
+	for id, encoded := range keyring.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%w: key %s must be 32 bytes base64", ErrInvalidKeyring, id)
+		}
+
+		manager.keys[id] = key
+	}
+
+	if _, ok := manager.keys[manager.current]; !ok {
+		return nil, fmt.Errorf("%w: current key %q not found", ErrInvalidKeyring, manager.current)
+	}
+
+	if keyring.IndexKey == "" {
+		return nil, ErrMissingIndexKey
+	}
+
+	manager.indexKey, err = base64.StdEncoding.DecodeString(keyring.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: index key must be base64", ErrInvalidKeyring)
+	}
+
+	return manager, nil
+}
+
+func (manager *FileKeyManager) CurrentKeyID() string {
+	return manager.current
+}
+
+func (manager *FileKeyManager) IndexKey() []byte {
+	return manager.indexKey
+}
+
+func (manager *FileKeyManager) Wrap(dataKey []byte) (string, []byte, error) {
+	wrapped, err := seal(manager.keys[manager.current], dataKey, []byte(manager.current))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return manager.current, wrapped, nil
+}
+
+func (manager *FileKeyManager) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := manager.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return open(key, wrapped, []byte(keyID))
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrInvalidCipher
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, sealed, additionalData)
+}
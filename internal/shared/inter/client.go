@@ -0,0 +1,214 @@
+package inter
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/shared"
+)
+
+const (
+	// tokenLeeway renews the access token this long before Inter expires it.
+	tokenLeeway = time.Minute
+
+	// requestTimeout bounds every call to Inter, so a stalled connection
+	// cannot hold a request or a job forever.
+	requestTimeout = 30 * time.Second
+)
+
+// Client talks to the Inter banking APIs: it handles the mTLS certificate and
+// the client credentials token so callers only deal with paths and payloads.
+// One client is shared by every Inter integration, so the certificate is
+// loaded once and the token is reused until it is about to expire.
+type Client struct {
+	clientID     string
+	clientSecret string
+	scope        string
+	certPath     string
+	keyPath      string
+	apiUrl       string
+
+	mu        sync.Mutex
+	client    *http.Client
+	token     string
+	expiresAt time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func NewClient(clientID, clientSecret, scope string) *Client {
+	apiUrl := "https://cdpj-sandbox.partners.uatinter.co"
+	if shared.GetEnv("ENV", "dev") == "prod" {
+		apiUrl = "https://cdpj.partners.bancointer.com.br"
+	}
+
+	tlsPath := shared.GetEnv("INTERBANK_TLS_PATH", "cert/")
+
+	return &Client{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scope:        scope,
+		certPath:     tlsPath + "Sandbox_InterAPI_Certificado.crt",
+		keyPath:      tlsPath + "Sandbox_InterAPI_Chave.key",
+		apiUrl:       apiUrl,
+	}
+}
+
+func (c *Client) httpClient() (*http.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	c.client = &http.Client{Timeout: requestTimeout, Transport: &http.Transport{TLSClientConfig: &tls.Config{Certificates: []tls.Certificate{cert}}}}
+
+	return c.client, nil
+}
+
+// accessToken returns the cached token, fetching a new one when there is
+// none or it is about to expire.
+func (c *Client) accessToken(ctx context.Context, client *http.Client) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	token, err := c.requestToken(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = token.AccessToken
+	c.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenLeeway)
+
+	return c.token, nil
+}
+
+func (c *Client) forgetToken() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = ""
+}
+
+func (c *Client) requestToken(ctx context.Context, client *http.Client) (*tokenResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+	data.Set("client_id", c.clientID)
+	data.Set("client_secret", c.clientSecret)
+	data.Set("scope", c.scope)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiUrl+"/oauth/v2/token", strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("token request failed with status: %d", resp.StatusCode)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Do sends payload as JSON (when not nil) and returns the status code and raw
+// body. Transport and authentication failures come back as errors; API
+// errors are left for the caller to interpret from the status code.
+func (c *Client) Do(ctx context.Context, method, path string, payload any, headers map[string]string) (int, []byte, error) {
+	client, err := c.httpClient()
+	if err != nil {
+		log.Printf("[InterClient] Error loading certificate: %v", err)
+		return 0, nil, err
+	}
+
+	token, err := c.accessToken(ctx, client)
+	if err != nil {
+		log.Printf("[InterClient] Error obtaining access token: %v", err)
+		return 0, nil, err
+	}
+
+	var reader io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		reader = bytes.NewReader(payloadBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiUrl+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("[InterClient] Error sending %s %s: %v", method, path, err)
+		return 0, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	log.Printf("[InterClient] %s %s returned status %d", method, path, resp.StatusCode)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.forgetToken()
+	}
+
+	return resp.StatusCode, body, nil
+}
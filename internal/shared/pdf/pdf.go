@@ -0,0 +1,147 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 40.0
+
+	headingSize = 12.0
+	textSize    = 9.0
+	leading     = 1.4
+)
+
+type line struct {
+	text string
+	size float64
+	bold bool
+}
+
+// Document is a plain text PDF: lines of Courier laid out top to bottom on
+// A4 pages, breaking to a new page when one is full. A monospaced font lets
+// callers align columns by padding.
+type Document struct {
+	pages [][]line
+	y     float64
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+func (document *Document) Heading(text string) {
+	document.add(line{text: text, size: headingSize, bold: true})
+}
+
+func (document *Document) Line(text string) {
+	document.add(line{text: text, size: textSize})
+}
+
+func (document *Document) Blank() {
+	document.add(line{size: textSize})
+}
+
+func (document *Document) add(l line) {
+	height := l.size * leading
+	if len(document.pages) == 0 || document.y-height < margin {
+		document.pages = append(document.pages, nil)
+		document.y = pageHeight - margin
+	}
+
+	document.y -= height
+	last := len(document.pages) - 1
+	document.pages[last] = append(document.pages[last], l)
+}
+
+// WriteTo renders the document. Objects 1 to 4 are the catalog, the page
+// tree and the two fonts; each page then takes a page object and its
+// content stream.
+func (document *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(document.pages) == 0 {
+		document.Blank()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	kids := make([]string, len(document.pages))
+	for i := range document.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(document.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range document.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+
+		content := pageContent(page)
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+func pageContent(page []line) string {
+	var content strings.Builder
+	y := pageHeight - margin
+
+	for _, l := range page {
+		y -= l.size * leading
+		if l.text == "" {
+			continue
+		}
+
+		font := "F1"
+		if l.bold {
+			font = "F2"
+		}
+
+		fmt.Fprintf(&content, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, l.size, margin, y, escape(l.text))
+	}
+
+	return content.String()
+}
+
+// escape encodes text for a WinAnsi string literal. Latin-1 covers the
+// accented letters of Portuguese; anything outside it becomes '?'.
+func escape(text string) string {
+	var escaped bytes.Buffer
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			escaped.WriteByte('\\')
+			escaped.WriteByte(byte(r))
+		case r < 0x20 || (r >= 0x7f && r < 0xa0) || r > 0xff:
+			escaped.WriteByte('?')
+		default:
+			escaped.WriteByte(byte(r))
+		}
+	}
+
+	return escaped.String()
+}
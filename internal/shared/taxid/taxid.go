@@ -0,0 +1,143 @@
+package taxid
+
+import (
+	"errors"
+	"strings"
+)
+
+type Kind string
+
+const (
+	KindCPF  Kind = "CPF"
+	KindCNPJ Kind = "CNPJ"
+)
+
+var (
+	ErrEmpty              = errors.New("tax id is required")
+	ErrInvalidLength      = errors.New("tax id must have 11 (CPF) or 14 (CNPJ) characters")
+	ErrInvalidCharacters  = errors.New("tax id has invalid characters")
+	ErrRepeatedDigits     = errors.New("tax id cannot have all characters repeated")
+	ErrInvalidCheckDigits = errors.New("tax id check digits do not match")
+)
+
+// Normalize strips the usual punctuation (dots, dashes, slashes and spaces) and
+// upper-cases letters, since alphanumeric CNPJs are case-insensitive.
+func Normalize(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r >= 'A' && r <= 'Z':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r == '.', r == '-', r == '/', r == ' ':
+			return -1
+		default:
+			return '?'
+		}
+	}, value)
+}
+
+func Validate(value string) (Kind, error) {
+	normalized := Normalize(value)
+
+	switch {
+	case normalized == "":
+		return "", ErrEmpty
+	case len(normalized) == 11:
+		return KindCPF, validateCPF(normalized)
+	case len(normalized) == 14:
+		return KindCNPJ, validateCNPJ(normalized)
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+func IsValid(value string) bool {
+	_, err := Validate(value)
+
+	return err == nil
+}
+
+// PersonType maps the document kind to the "tipo de pessoa" used by Brazilian
+// banks: CPFs belong to individuals and CNPJs to companies.
+func (kind Kind) PersonType() string {
+	if kind == KindCNPJ {
+		return "JURIDICA"
+	}
+
+	return "FISICA"
+}
+
+func allRepeated(value string) bool {
+	return strings.Count(value, value[:1]) == len(value)
+}
+
+func validateCPF(value string) error {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return ErrInvalidCharacters
+		}
+	}
+
+	if allRepeated(value) {
+		return ErrRepeatedDigits
+	}
+
+	for _, size := range []int{9, 10} {
+		sum := 0
+		for i := 0; i < size; i++ {
+			sum += int(value[i]-'0') * (size + 1 - i)
+		}
+
+		digit := sum * 10 % 11
+		if digit == 10 {
+			digit = 0
+		}
+
+		if int(value[size]-'0') != digit {
+			return ErrInvalidCheckDigits
+		}
+	}
+
+	return nil
+}
+
+// validateCNPJ accepts both the numeric layout and the alphanumeric one
+// introduced by Receita Federal, where each of the first 12 characters is
+// worth its ASCII code minus 48 and the two check digits stay numeric.
+func validateCNPJ(value string) error {
+	for i, r := range value {
+		numeric := r >= '0' && r <= '9'
+		letter := r >= 'A' && r <= 'Z'
+
+		if (i >= 12 && !numeric) || (!numeric && !letter) {
+			return ErrInvalidCharacters
+		}
+	}
+
+	if allRepeated(value) {
+		return ErrRepeatedDigits
+	}
+
+	weights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+
+	for _, size := range []int{12, 13} {
+		offset := len(weights) - size
+
+		sum := 0
+		for i := 0; i < size; i++ {
+			sum += int(value[i]-'0') * weights[offset+i]
+		}
+
+		digit := 0
+		if remainder := sum % 11; remainder >= 2 {
+			digit = 11 - remainder
+		}
+
+		if int(value[size]-'0') != digit {
+			return ErrInvalidCheckDigits
+		}
+	}
+
+	return nil
+}
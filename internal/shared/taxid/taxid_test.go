@@ -0,0 +1,59 @@
+package taxid
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		kind  Kind
+		err   error
+	}{
+		{name: "cpf unformatted", value: "52998224725", kind: KindCPF},
+		{name: "cpf formatted", value: "529.982.247-25", kind: KindCPF},
+		{name: "cpf wrong check digit", value: "529.982.247-26", kind: KindCPF, err: ErrInvalidCheckDigits},
+		{name: "cpf repeated digits", value: "111.111.111-11", kind: KindCPF, err: ErrRepeatedDigits},
+		{name: "cpf with letters", value: "52998224A25", kind: KindCPF, err: ErrInvalidCharacters},
+		{name: "cnpj unformatted", value: "11222333000181", kind: KindCNPJ},
+		{name: "cnpj formatted", value: "11.222.333/0001-81", kind: KindCNPJ},
+		{name: "cnpj wrong check digit", value: "11.222.333/0001-82", kind: KindCNPJ, err: ErrInvalidCheckDigits},
+		{name: "cnpj repeated digits", value: "00.000.000/0000-00", kind: KindCNPJ, err: ErrRepeatedDigits},
+		{name: "alphanumeric cnpj", value: "12.ABC.345/01DE-35", kind: KindCNPJ},
+		{name: "alphanumeric cnpj lower case", value: "12abc34501de35", kind: KindCNPJ},
+		{name: "alphanumeric cnpj wrong check digit", value: "12ABC34501DE36", kind: KindCNPJ, err: ErrInvalidCheckDigits},
+		{name: "cnpj with letter in check digits", value: "12ABC34501DE3A", kind: KindCNPJ, err: ErrInvalidCharacters},
+		{name: "unknown punctuation", value: "529982247*5", kind: KindCPF, err: ErrInvalidCharacters},
+		{name: "empty", value: " ", err: ErrEmpty},
+		{name: "wrong length", value: "1234567890", err: ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, err := Validate(tt.value)
+
+			if err != tt.err {
+				t.Fatalf("Validate(%q) error = %v, want %v", tt.value, err, tt.err)
+			}
+
+			if kind != tt.kind {
+				t.Errorf("Validate(%q) kind = %q, want %q", tt.value, kind, tt.kind)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("12.abc.345/01de-35"); got != "12ABC34501DE35" {
+		t.Errorf("Normalize() = %q, want %q", got, "12ABC34501DE35")
+	}
+}
+
+func TestPersonType(t *testing.T) {
+	if got := KindCPF.PersonType(); got != "FISICA" {
+		t.Errorf("KindCPF.PersonType() = %q, want FISICA", got)
+	}
+
+	if got := KindCNPJ.PersonType(); got != "JURIDICA" {
+		t.Errorf("KindCNPJ.PersonType() = %q, want JURIDICA", got)
+	}
+}
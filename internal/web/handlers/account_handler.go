@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
 	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
 )
 
 type AccountHandler struct {
@@ -18,54 +18,55 @@ func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
 	}
 }
 
-func (handler *AccountHandler) Create(w http.ResponseWriter, r *http.Request) {
-	var input dto.CreateAccountInput
-	err := json.NewDecoder(r.Body).Decode(&input)
+func (handler *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.accountService.FindByID(r.Context(), principal.AccountID)
 
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
 
+func (handler *AccountHandler) Update(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
 		return
 	}
 
-	output, err := handler.accountService.CreateAccount(input)
+	var input dto.UpdateAccountInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
 
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	output, err := handler.accountService.Update(r.Context(), principal.AccountID, input)
 
+	if err != nil {
+		response.Error(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(output)
+	response.JSON(w, http.StatusOK, output)
 }
 
-func (handler *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
-	apiKey := r.Header.Get("X-API-Key")
-	if apiKey == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"error": "API Key is required"})
-
+func (handler *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
 		return
 	}
 
-	output, err := handler.accountService.FindByAPIKey(apiKey)
+	output, err := handler.accountService.Delete(r.Context(), principal.AccountID)
 
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-
+		response.Error(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(output)
+	response.JSON(w, http.StatusOK, output)
 }
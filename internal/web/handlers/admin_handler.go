@@ -0,0 +1,493 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxReturnUpload bounds return files; a CNAB 240 line is 242 bytes, so
+// this holds tens of thousands of titles.
+const maxReturnUpload = 10 << 20
+
+type AdminHandler struct {
+	operatorService       *service.OperatorService
+	accountService        *service.AccountService
+	invoiceService        *service.InvoiceService
+	transferService       *service.TransferService
+	disputeService        *service.DisputeService
+	holdService           *service.BalanceHoldService
+	reconciliationService *service.ReconciliationService
+	cnabService           *service.CNABService
+	auditService          *service.AuditService
+}
+
+func NewAdminHandler(operatorService *service.OperatorService, accountService *service.AccountService, invoiceService *service.InvoiceService, transferService *service.TransferService, disputeService *service.DisputeService, holdService *service.BalanceHoldService, reconciliationService *service.ReconciliationService, cnabService *service.CNABService, auditService *service.AuditService) *AdminHandler {
+	return &AdminHandler{
+		operatorService:       operatorService,
+		accountService:        accountService,
+		invoiceService:        invoiceService,
+		transferService:       transferService,
+		disputeService:        disputeService,
+		holdService:           holdService,
+		reconciliationService: reconciliationService,
+		cnabService:           cnabService,
+		auditService:          auditService,
+	}
+}
+
+func searchLimit(r *http.Request) int {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	return limit
+}
+
+func (handler *AdminHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var input dto.LoginInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.operatorService.Login(r.Context(), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) CreateOperator(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateOperatorInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.operatorService.Create(r.Context(), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+// CreateAccount onboards a merchant. The response carries the account's
+// master API key, which is not shown again.
+func (handler *AdminHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateAccountInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.accountService.CreateAccount(r.Context(), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *AdminHandler) SearchAccounts(w http.ResponseWriter, r *http.Request) {
+	includeDeleted, _ := strconv.ParseBool(r.URL.Query().Get("include_deleted"))
+
+	output, err := handler.accountService.Search(r.Context(), domain.AccountFilter{
+		Query:          r.URL.Query().Get("q"),
+		IncludeDeleted: includeDeleted,
+		Limit:          searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) SearchInvoices(w http.ResponseWriter, r *http.Request) {
+	output, err := handler.invoiceService.Search(r.Context(), domain.InvoiceFilter{
+		AccountID: r.URL.Query().Get("account_id"),
+		Status:    domain.Status(r.URL.Query().Get("status")),
+		Query:     r.URL.Query().Get("q"),
+		Limit:     searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	output, err := handler.accountService.Restore(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) AdjustBalance(w http.ResponseWriter, r *http.Request) {
+	var input dto.BalanceAdjustmentInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.accountService.AdjustBalance(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) SetFeeSchedule(w http.ResponseWriter, r *http.Request) {
+	var input dto.UpdateFeeScheduleInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.accountService.SetFeeSchedule(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) SetReservePolicy(w http.ResponseWriter, r *http.Request) {
+	var input dto.UpdateReservePolicyInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.accountService.SetReservePolicy(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateBalanceHoldInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.holdService.Place(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *AdminHandler) SearchHolds(w http.ResponseWriter, r *http.Request) {
+	output, err := handler.holdService.Search(r.Context(), domain.BalanceHoldFilter{
+		AccountID: chi.URLParam(r, "id"),
+		Status:    domain.BalanceHoldStatus(r.URL.Query().Get("status")),
+		Limit:     searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	var input dto.ReleaseBalanceHoldInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.holdService.Release(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	var input dto.AdminTransferInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.transferService.CreateForOperator(r.Context(), r.Header.Get(idempotencyKeyHeader), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *AdminHandler) ReverseTransfer(w http.ResponseWriter, r *http.Request) {
+	var input dto.ReverseTransferInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.transferService.Reverse(r.Context(), "", chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) SearchDisputes(w http.ResponseWriter, r *http.Request) {
+	output, err := handler.disputeService.Search(r.Context(), domain.DisputeFilter{
+		AccountID: r.URL.Query().Get("account_id"),
+		InvoiceID: r.URL.Query().Get("invoice_id"),
+		Status:    domain.DisputeStatus(r.URL.Query().Get("status")),
+		Limit:     searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	var input dto.ResolveDisputeInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.disputeService.Resolve(r.Context(), "", chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) FreezeAccount(w http.ResponseWriter, r *http.Request) {
+	var input dto.FreezeAccountInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.accountService.Freeze(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) UnfreezeAccount(w http.ResponseWriter, r *http.Request) {
+	var input dto.FreezeAccountInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.accountService.Unfreeze(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) RunReconciliation(w http.ResponseWriter, r *http.Request) {
+	var input dto.RunReconciliationInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.reconciliationService.Run(r.Context(), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *AdminHandler) SearchReconciliations(w http.ResponseWriter, r *http.Request) {
+	output, err := handler.reconciliationService.Search(r.Context(), domain.ReconciliationFilter{Limit: searchLimit(r)})
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+// GetReconciliation returns the report with its items as JSON, or as a file
+// to download with ?format=csv.
+func (handler *AdminHandler) GetReconciliation(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		response.Error(w, r, domain.NewValidationError(domain.NewFieldError("format", "invalid_format", "must be json or csv")))
+		return
+	}
+
+	reconciliation, err := handler.reconciliationService.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if format != "csv" {
+		response.JSON(w, http.StatusOK, dto.FromReconciliation(reconciliation, true))
+		return
+	}
+
+	filename := "reconciliation-" + reconciliation.From.Format("2006-01-02") + "-" + reconciliation.To.Format("2006-01-02") + ".csv"
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if err := dto.WriteReconciliationCSV(w, reconciliation); err != nil {
+		log.Printf("Error writing reconciliation %s as csv: %v", reconciliation.ID, err)
+	}
+}
+
+func (handler *AdminHandler) GenerateRemittance(w http.ResponseWriter, r *http.Request) {
+	var input dto.GenerateRemittanceInput
+	if r.ContentLength != 0 && !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.cnabService.GenerateRemittance(r.Context(), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+// ImportReturn takes the bank's return file in the "file" part of a
+// multipart form.
+func (handler *AdminHandler) ImportReturn(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxReturnUpload)
+	if err := r.ParseMultipartForm(maxReturnUpload); err != nil {
+		response.Error(w, r, domain.NewValidationError(domain.NewFieldError("", "invalid_multipart", "request body must be multipart/form-data within the upload limit")))
+		return
+	}
+
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, r, domain.NewValidationError(domain.NewFieldError("file", "required", "is required")))
+		return
+	}
+
+	content, err := io.ReadAll(file)
+	file.Close()
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	output, err := handler.cnabService.ImportReturn(r.Context(), header.Filename, content)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *AdminHandler) SearchCNABFiles(w http.ResponseWriter, r *http.Request) {
+	output, err := handler.cnabService.Search(r.Context(), domain.CNABFileFilter{
+		Kind:  domain.CNABFileKind(r.URL.Query().Get("kind")),
+		Limit: searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) GetCNABFile(w http.ResponseWriter, r *http.Request) {
+	file, err := handler.cnabService.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, dto.FromCNABFile(file, true))
+}
+
+func (handler *AdminHandler) DownloadCNABFile(w http.ResponseWriter, r *http.Request) {
+	file, err := handler.cnabService.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=iso-8859-1")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+
+	if _, err := w.Write(file.Content); err != nil {
+		log.Printf("Error writing CNAB file %s: %v", file.ID, err)
+	}
+}
+
+func (handler *AdminHandler) SearchAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	output, err := handler.auditService.Search(r.Context(), domain.AuditFilter{
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+		ActorType:  domain.ActorType(query.Get("actor_type")),
+		ActorID:    query.Get("actor_id"),
+		Limit:      searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *AdminHandler) VerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	output, err := handler.auditService.Verify(r.Context())
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (handler *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.CreateAPIKeyInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.apiKeyService.Create(r.Context(), principal.AccountID, input)
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.apiKeyService.List(r.Context(), principal.AccountID)
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.apiKeyService.Revoke(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
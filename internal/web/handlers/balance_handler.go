@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type BalanceHandler struct {
+	balanceService *service.BalanceService
+	holdService    *service.BalanceHoldService
+}
+
+func NewBalanceHandler(balanceService *service.BalanceService, holdService *service.BalanceHoldService) *BalanceHandler {
+	return &BalanceHandler{
+		balanceService: balanceService,
+		holdService:    holdService,
+	}
+}
+
+func (handler *BalanceHandler) ListHolds(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.holdService.Search(r.Context(), domain.BalanceHoldFilter{
+		AccountID: principal.AccountID,
+		Status:    domain.BalanceHoldStatus(r.URL.Query().Get("status")),
+		Limit:     searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *BalanceHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	output, err := handler.balanceService.ListTransactions(r.Context(), principal.AccountID, dto.BalanceTransactionQuery{
+		Type:       query.Get("type"),
+		SourceType: query.Get("source_type"),
+		SourceID:   query.Get("source_id"),
+		From:       query.Get("from"),
+		To:         query.Get("to"),
+	}, searchLimit(r))
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+// Statement returns a month of balance transactions as JSON, or as a file
+// to download with ?format=csv or ?format=pdf.
+func (handler *BalanceHandler) Statement(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	month := chi.URLParam(r, "month")
+	format := r.URL.Query().Get("format")
+
+	if format != "" && format != "json" && format != "csv" && format != "pdf" {
+		response.Error(w, r, domain.NewValidationError(domain.NewFieldError("format", "invalid_format", "must be json, csv or pdf")))
+		return
+	}
+
+	statement, err := handler.balanceService.Statement(r.Context(), principal.AccountID, month)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	filename := "statement-" + month + "." + format
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		err = dto.WriteStatementCSV(w, statement)
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		err = dto.WriteStatementPDF(w, statement)
+	default:
+		response.JSON(w, http.StatusOK, dto.FromStatement(statement))
+	}
+
+	if err != nil {
+		log.Printf("Error writing %s statement %s for account %s: %v", format, month, principal.AccountID, err)
+	}
+}
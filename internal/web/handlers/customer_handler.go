@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type CustomerHandler struct {
+	customerService *service.CustomerService
+	invoiceService  *service.InvoiceService
+}
+
+func NewCustomerHandler(customerService *service.CustomerService, invoiceService *service.InvoiceService) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+		invoiceService:  invoiceService,
+	}
+}
+
+func (handler *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.CustomerInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.customerService.Create(r.Context(), principal.AccountID, input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *CustomerHandler) Search(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	includeDeleted, _ := strconv.ParseBool(query.Get("include_deleted"))
+
+	output, err := handler.customerService.Search(r.Context(), domain.CustomerFilter{
+		AccountID:      principal.AccountID,
+		TaxID:          query.Get("tax_id"),
+		Email:          query.Get("email"),
+		Query:          query.Get("q"),
+		IncludeDeleted: includeDeleted,
+		Limit:          searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *CustomerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.customerService.Get(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *CustomerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.CustomerInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.customerService.Update(r.Context(), principal.AccountID, chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.customerService.Delete(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *CustomerHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.invoiceService.ListByCustomer(r.Context(), principal.AccountID, chi.URLParam(r, "id"), searchLimit(r))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
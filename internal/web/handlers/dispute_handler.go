@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxEvidenceUpload bounds a whole evidence request: every file at its
+// largest plus room for the statement and multipart overhead.
+const maxEvidenceUpload = dto.MaxEvidenceFiles*dto.MaxEvidenceFileSize + 1<<20
+
+type DisputeHandler struct {
+	disputeService *service.DisputeService
+}
+
+func NewDisputeHandler(disputeService *service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+	}
+}
+
+func (handler *DisputeHandler) Search(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.disputeService.Search(r.Context(), domain.DisputeFilter{
+		AccountID: principal.AccountID,
+		InvoiceID: r.URL.Query().Get("invoice_id"),
+		Status:    domain.DisputeStatus(r.URL.Query().Get("status")),
+		Limit:     searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *DisputeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.disputeService.Get(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+// AddEvidence takes a multipart form with an optional "text" field and any
+// number of "files" parts.
+func (handler *DisputeHandler) AddEvidence(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxEvidenceUpload)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		response.Error(w, r, domain.NewValidationError(domain.NewFieldError("", "invalid_multipart", "request body must be multipart/form-data within the upload limit")))
+		return
+	}
+
+	defer r.MultipartForm.RemoveAll()
+
+	input := dto.AddDisputeEvidenceInput{Text: r.FormValue("text")}
+
+	for _, header := range r.MultipartForm.File["files"] {
+		file, err := header.Open()
+		if err != nil {
+			response.Error(w, r, err)
+			return
+		}
+
+		content, err := io.ReadAll(file)
+		file.Close()
+
+		if err != nil {
+			response.Error(w, r, err)
+			return
+		}
+
+		input.Files = append(input.Files, dto.DisputeEvidenceFile{
+			Name:        header.Filename,
+			ContentType: header.Header.Get("Content-Type"),
+			Content:     content,
+		})
+	}
+
+	output, err := handler.disputeService.AddEvidence(r.Context(), principal.AccountID, chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *DisputeHandler) DownloadEvidence(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	evidence, err := handler.disputeService.Evidence(r.Context(), principal.AccountID, chi.URLParam(r, "id"), chi.URLParam(r, "evidenceId"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if evidence.Kind != domain.DisputeEvidenceFile {
+		response.Error(w, r, domain.ErrDisputeEvidenceNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", evidence.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": evidence.FileName}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(evidence.Content)
+}
+
+func (handler *DisputeHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.disputeService.Submit(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *DisputeHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.SimulateDisputeInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.disputeService.Simulate(r.Context(), principal.AccountID, input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *DisputeHandler) SimulateOutcome(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.ResolveDisputeInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.disputeService.Resolve(r.Context(), principal.AccountID, chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
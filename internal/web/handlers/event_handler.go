@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+)
+
+type EventHandler struct {
+	eventService *service.EventService
+}
+
+func NewEventHandler(eventService *service.EventService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+func (handler *EventHandler) Search(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	output, err := handler.eventService.Search(r.Context(), domain.EventFilter{
+		AccountID:  principal.AccountID,
+		Type:       query.Get("type"),
+		ResourceID: query.Get("resource_id"),
+		Limit:      searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
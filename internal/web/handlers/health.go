@@ -1,8 +1,9 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
 )
 
 func GetHealth(w http.ResponseWriter, r *http.Request) {
@@ -10,7 +11,5 @@ func GetHealth(w http.ResponseWriter, r *http.Request) {
 		"status": true,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(status)
+	response.JSON(w, http.StatusOK, status)
 }
@@ -1,12 +1,11 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 
-	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
 	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
 	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -21,86 +20,86 @@ func NewInvoiceHandler(service *service.InvoiceService) *InvoiceHandler {
 }
 
 func (h *InvoiceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
 	var input dto.CreateInvoiceInput
-	err := json.NewDecoder(r.Body).Decode(&input)
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := h.service.Create(r.Context(), principal.AccountID, input)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
 
+func (h *InvoiceHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
 		return
 	}
 
-	input.APIKey = r.Header.Get("X-API-KEY")
+	var input dto.CreateRefundInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
 
-	output, err := h.service.Create(input)
+	output, err := h.service.Refund(r.Context(), principal.AccountID, chi.URLParam(r, "id"), input)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-
+		response.Error(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(output)
+	response.JSON(w, http.StatusCreated, output)
 }
 
-func (h *InvoiceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		http.Error(w, "ID is required", http.StatusBadRequest)
+func (h *InvoiceHandler) ListRefunds(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
 		return
 	}
 
-	apiKey := r.Header.Get("X-API-KEY")
-	if apiKey == "" {
-		http.Error(w, "X-API-KEY is required", http.StatusBadRequest)
+	output, err := h.service.ListRefunds(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
 		return
 	}
 
-	output, err := h.service.GetByID(id, apiKey)
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (h *InvoiceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"), principal.AccountID)
 	if err != nil {
-		switch err {
-		case domain.ErrInvoiceNotFound:
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		case domain.ErrAccountNotFound:
-			http.Error(w, err.Error(), http.StatusUnauthorized)
-			return
-		case domain.ErrUnauthorizedAccess:
-			http.Error(w, err.Error(), http.StatusForbidden)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+		response.Error(w, r, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(output)
+	response.JSON(w, http.StatusOK, output)
 }
 
 func (h *InvoiceHandler) ListByAccount(w http.ResponseWriter, r *http.Request) {
-	apiKey := r.Header.Get("X-API-KEY")
-	if apiKey == "" {
-		http.Error(w, "X-API-KEY is required", http.StatusUnauthorized)
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
 		return
 	}
 
-	output, err := h.service.ListByAccountAPIKey(apiKey)
+	output, err := h.service.ListByAccount(r.Context(), principal.AccountID)
 	if err != nil {
-		switch err {
-		case domain.ErrAccountNotFound:
-			http.Error(w, err.Error(), http.StatusUnauthorized)
-			return
-		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+		response.Error(w, r, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(output)
+	response.JSON(w, http.StatusOK, output)
 }
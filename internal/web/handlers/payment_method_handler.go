@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type PaymentMethodHandler struct {
+	paymentMethodService *service.PaymentMethodService
+}
+
+func NewPaymentMethodHandler(paymentMethodService *service.PaymentMethodService) *PaymentMethodHandler {
+	return &PaymentMethodHandler{
+		paymentMethodService: paymentMethodService,
+	}
+}
+
+func (handler *PaymentMethodHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.CreatePaymentMethodInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.paymentMethodService.Create(r.Context(), principal.AccountID, chi.URLParam(r, "id"), input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *PaymentMethodHandler) List(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.paymentMethodService.List(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *PaymentMethodHandler) SetDefault(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.paymentMethodService.SetDefault(r.Context(), principal.AccountID, chi.URLParam(r, "id"), chi.URLParam(r, "methodId"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *PaymentMethodHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.paymentMethodService.Delete(r.Context(), principal.AccountID, chi.URLParam(r, "id"), chi.URLParam(r, "methodId"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/dto"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type PayoutHandler struct {
+	payoutService *service.PayoutService
+}
+
+func NewPayoutHandler(payoutService *service.PayoutService) *PayoutHandler {
+	return &PayoutHandler{
+		payoutService: payoutService,
+	}
+}
+
+func (handler *PayoutHandler) CreateDestination(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.CreatePayoutDestinationInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.payoutService.CreateDestination(r.Context(), principal.AccountID, input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *PayoutHandler) ListDestinations(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.payoutService.ListDestinations(r.Context(), principal.AccountID)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *PayoutHandler) DeleteDestination(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.payoutService.DeleteDestination(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *PayoutHandler) Create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.CreatePayoutInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	output, err := handler.payoutService.Create(r.Context(), principal.AccountID, input)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, output)
+}
+
+func (handler *PayoutHandler) Search(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.payoutService.Search(r.Context(), domain.PayoutFilter{
+		AccountID: principal.AccountID,
+		Status:    domain.PayoutStatus(r.URL.Query().Get("status")),
+		Limit:     searchLimit(r),
+	})
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
+
+func (handler *PayoutHandler) Get(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := handler.payoutService.Get(r.Context(), principal.AccountID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, output)
+}
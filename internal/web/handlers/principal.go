@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+)
+
+func principalFromRequest(w http.ResponseWriter, r *http.Request) (*domain.Principal, bool) {
+	principal, ok := domain.PrincipalFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, domain.ErrAuthenticationRequired)
+		return nil, false
+	}
+
+	return principal, true
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+)
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		response.Error(w, r, domain.NewValidationError(decodeFieldError(err)))
+		return false
+	}
+
+	if decoder.More() {
+		response.Error(w, r, domain.NewValidationError(domain.NewFieldError("", "invalid_json", "request body must contain a single JSON object")))
+		return false
+	}
+
+	return true
+}
+
+func decodeFieldError(err error) *domain.FieldError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return domain.NewFieldError("", "empty_body", "request body is required")
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return domain.NewFieldError("", "invalid_json", "request body is not valid JSON")
+	case errors.As(err, &typeErr):
+		return domain.NewFieldError(typeErr.Field, "invalid_type", "must be of type "+typeErr.Type.String())
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return domain.NewFieldError(field, "unknown_field", "is not a recognized field")
+	default:
+		return domain.NewFieldError("", "invalid_json", err.Error())
+	}
+}
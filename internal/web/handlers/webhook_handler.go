@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+)
+
+type WebhookHandler struct {
+	invoiceService *service.InvoiceService
+	secret         string
+}
+
+func NewWebhookHandler(invoiceService *service.InvoiceService, secret string) *WebhookHandler {
+	return &WebhookHandler{
+		invoiceService: invoiceService,
+		secret:         secret,
+	}
+}
+
+type interNotification struct {
+	CodigoSolicitacao  string `json:"codigoSolicitacao"`
+	SeuNumero          string `json:"seuNumero"`
+	Situacao           string `json:"situacao"`
+	DataHoraSituacao   string `json:"dataHoraSituacao"`
+	ValorTotalRecebido string `json:"valorTotalRecebido"`
+	OrigemRecebimento  string `json:"origemRecebimento"`
+}
+
+// Inter posts every status change of a cobrança; only payments matter here.
+var interPaidStatuses = map[string]bool{
+	"RECEBIDO":         true,
+	"MARCADO_RECEBIDO": true,
+}
+
+// Inter sends the notification list as a bare array and may add fields
+// without notice, so unknown fields are tolerated here unlike the public API.
+func (handler *WebhookHandler) Inter(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Webhook-Token")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+
+	if handler.secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(handler.secret)) != 1 {
+		response.Error(w, r, domain.ErrInvalidWebhookToken)
+		return
+	}
+
+	var notifications []interNotification
+	if err := json.NewDecoder(r.Body).Decode(&notifications); err != nil {
+		response.Error(w, r, domain.NewValidationError(decodeFieldError(err)))
+		return
+	}
+
+	settled := 0
+	for _, notification := range notifications {
+		if !interPaidStatuses[notification.Situacao] {
+			continue
+		}
+
+		paidAt, err := time.Parse(time.RFC3339, notification.DataHoraSituacao)
+		if err != nil {
+			paidAt = time.Now()
+		}
+
+		received, _ := strconv.ParseFloat(notification.ValorTotalRecebido, 64)
+
+		err = handler.invoiceService.SettleProviderCharge(r.Context(), notification.CodigoSolicitacao, paidAt, received)
+		if errors.Is(err, domain.ErrInvoiceNotFound) {
+			log.Printf("[WebhookHandler] Ignoring Inter notification for unknown charge %s (%s)", notification.CodigoSolicitacao, notification.SeuNumero)
+			continue
+		}
+
+		if err != nil {
+			response.Error(w, r, err)
+			return
+		}
+
+		settled++
+	}
+
+	response.JSON(w, http.StatusOK, map[string]int{"settled": settled})
+}
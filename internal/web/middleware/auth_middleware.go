@@ -1,42 +1,54 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
 	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
 )
 
 type AuthMiddleware struct {
-	accountService *service.AccountService
+	apiKeyService *service.APIKeyService
 }
 
-func NewAuthMiddleware(accountService *service.AccountService) *AuthMiddleware {
+func NewAuthMiddleware(apiKeyService *service.APIKeyService) *AuthMiddleware {
 	return &AuthMiddleware{
-		accountService: accountService,
+		apiKeyService: apiKeyService,
 	}
 }
 
-func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		apiKey := r.Header.Get("X-API-KEY")
+func (m *AuthMiddleware) Authenticate(scope domain.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-API-KEY")
 
-		if apiKey == "" {
-			http.Error(w, "X-API-KEY is required", http.StatusUnauthorized)
-			return
-		}
+			if key == "" {
+				response.Error(w, r, domain.ErrAuthenticationRequired)
+				return
+			}
 
-		_, err := m.accountService.FindByAPIKey(apiKey)
-		if err != nil {
-			if err == domain.ErrAccountNotFound {
-				http.Error(w, err.Error(), http.StatusUnauthorized)
+			principal, err := m.apiKeyService.Authenticate(r.Context(), key)
+			if err != nil {
+				response.Error(w, r, err)
 				return
 			}
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+			if !principal.HasScope(scope) {
+				response.Error(w, r, fmt.Errorf("%w: %s", domain.ErrInsufficientScope, scope))
+				return
+			}
 
-		next.ServeHTTP(w, r)
-	})
+			if principal.AccountFrozen && r.Method != http.MethodGet {
+				response.Error(w, r, domain.ErrAccountFrozen)
+				return
+			}
+
+			ctx := domain.WithPrincipal(r.Context(), principal)
+			ctx = domain.WithActor(ctx, domain.Actor{Type: domain.ActorTypeAPIKey, ID: principal.KeyID})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
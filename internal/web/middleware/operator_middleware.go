@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	"github.com/NewLeonardooliv/gateway-payment/internal/service"
+	"github.com/NewLeonardooliv/gateway-payment/internal/web/response"
+)
+
+type OperatorMiddleware struct {
+	operatorService *service.OperatorService
+}
+
+func NewOperatorMiddleware(operatorService *service.OperatorService) *OperatorMiddleware {
+	return &OperatorMiddleware{
+		operatorService: operatorService,
+	}
+}
+
+func (m *OperatorMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if !found || token == "" {
+			response.Error(w, r, domain.ErrAuthenticationRequired)
+			return
+		}
+
+		operator, err := m.operatorService.Authenticate(r.Context(), token)
+		if err != nil {
+			response.Error(w, r, err)
+			return
+		}
+
+		ctx := domain.WithOperator(r.Context(), operator)
+		ctx = domain.WithActor(ctx, domain.Actor{Type: domain.ActorTypeOperator, ID: operator.ID})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (m *OperatorMiddleware) Require(permission domain.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			operator, ok := domain.OperatorFromContext(r.Context())
+
+			if !ok {
+				response.Error(w, r, domain.ErrAuthenticationRequired)
+				return
+			}
+
+			if !operator.Can(permission) {
+				response.Error(w, r, fmt.Errorf("%w: %s", domain.ErrPermissionDenied, permission))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
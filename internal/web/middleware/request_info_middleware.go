@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+func RequestInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			ip = host
+		}
+
+		requestID := chiMiddleware.GetReqID(r.Context())
+		w.Header().Set(chiMiddleware.RequestIDHeader, requestID)
+
+		ctx := domain.WithRequestInfo(r.Context(), domain.RequestInfo{
+			IP:        ip,
+			RequestID: requestID,
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
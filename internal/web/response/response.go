@@ -0,0 +1,77 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
+)
+
+type Problem struct {
+	Type      string               `json:"type"`
+	Title     string               `json:"title"`
+	Status    int                  `json:"status"`
+	Detail    string               `json:"detail"`
+	Code      string               `json:"code"`
+	Instance  string               `json:"instance,omitempty"`
+	RequestID string               `json:"request_id,omitempty"`
+	Errors    []*domain.FieldError `json:"errors,omitempty"`
+}
+
+func JSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	kind := domain.KindOf(err)
+	status := Status(kind)
+	info := domain.RequestInfoFromContext(r.Context())
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    err.Error(),
+		Code:      domain.CodeOf(err),
+		Instance:  r.URL.Path,
+		RequestID: info.RequestID,
+	}
+
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Detail = "the request contains invalid fields"
+		problem.Errors = validationErr.Errors
+	}
+
+	if kind == domain.KindInternal {
+		log.Printf("[%s] %s %s: %v", info.RequestID, r.Method, r.URL.Path, err)
+		problem.Detail = "an internal error occurred"
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+func Status(kind domain.ErrorKind) int {
+	switch kind {
+	case domain.KindValidation:
+		return http.StatusBadRequest
+	case domain.KindUnauthorized:
+		return http.StatusUnauthorized
+	case domain.KindForbidden:
+		return http.StatusForbidden
+	case domain.KindNotFound:
+		return http.StatusNotFound
+	case domain.KindConflict:
+		return http.StatusConflict
+	case domain.KindProviderUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/NewLeonardooliv/gateway-payment/internal/domain"
 	"github.com/NewLeonardooliv/gateway-payment/internal/service"
 	"github.com/NewLeonardooliv/gateway-payment/internal/web/handlers"
 	"github.com/NewLeonardooliv/gateway-payment/internal/web/middleware"
@@ -12,39 +13,212 @@ import (
 )
 
 type Server struct {
-	router         *chi.Mux
-	server         *http.Server
-	accountService *service.AccountService
-	invoiceService *service.InvoiceService
-	port           string
+	router                *chi.Mux
+	server                *http.Server
+	accountService        *service.AccountService
+	invoiceService        *service.InvoiceService
+	customerService       *service.CustomerService
+	paymentMethodService  *service.PaymentMethodService
+	subscriptionService   *service.SubscriptionService
+	eventService          *service.EventService
+	payoutService         *service.PayoutService
+	transferService       *service.TransferService
+	balanceService        *service.BalanceService
+	disputeService        *service.DisputeService
+	holdService           *service.BalanceHoldService
+	reconciliationService *service.ReconciliationService
+	cnabService           *service.CNABService
+	apiKeyService         *service.APIKeyService
+	operatorService       *service.OperatorService
+	auditService          *service.AuditService
+	webhookSecret         string
+	simulatorEnabled      bool
+	port                  string
 }
 
-func NewServer(accountService *service.AccountService, invoiceService *service.InvoiceService, port string) *Server {
+func NewServer(accountService *service.AccountService, invoiceService *service.InvoiceService, customerService *service.CustomerService, paymentMethodService *service.PaymentMethodService, subscriptionService *service.SubscriptionService, eventService *service.EventService, payoutService *service.PayoutService, transferService *service.TransferService, balanceService *service.BalanceService, disputeService *service.DisputeService, holdService *service.BalanceHoldService, reconciliationService *service.ReconciliationService, cnabService *service.CNABService, apiKeyService *service.APIKeyService, operatorService *service.OperatorService, auditService *service.AuditService, webhookSecret string, simulatorEnabled bool, port string) *Server {
 	return &Server{
-		router:         chi.NewRouter(),
-		accountService: accountService,
-		invoiceService: invoiceService,
-		port:           port,
+		router:                chi.NewRouter(),
+		accountService:        accountService,
+		invoiceService:        invoiceService,
+		customerService:       customerService,
+		paymentMethodService:  paymentMethodService,
+		subscriptionService:   subscriptionService,
+		eventService:          eventService,
+		payoutService:         payoutService,
+		transferService:       transferService,
+		balanceService:        balanceService,
+		disputeService:        disputeService,
+		holdService:           holdService,
+		reconciliationService: reconciliationService,
+		cnabService:           cnabService,
+		apiKeyService:         apiKeyService,
+		operatorService:       operatorService,
+		auditService:          auditService,
+		webhookSecret:         webhookSecret,
+		simulatorEnabled:      simulatorEnabled,
+		port:                  port,
 	}
 }
 
 func (s *Server) ConfigureRoutes() {
+	s.router.Use(chiMiddleware.RequestID)
+	s.router.Use(chiMiddleware.RealIP)
 	s.router.Use(chiMiddleware.Logger)
+	s.router.Use(middleware.RequestInfo)
 
 	accountHandler := handlers.NewAccountHandler(s.accountService)
 	invoiceHandler := handlers.NewInvoiceHandler(s.invoiceService)
-	authMiddleware := middleware.NewAuthMiddleware(s.accountService)
+	customerHandler := handlers.NewCustomerHandler(s.customerService, s.invoiceService)
+	paymentMethodHandler := handlers.NewPaymentMethodHandler(s.paymentMethodService)
+	planHandler := handlers.NewPlanHandler(s.subscriptionService)
+	subscriptionHandler := handlers.NewSubscriptionHandler(s.subscriptionService)
+	eventHandler := handlers.NewEventHandler(s.eventService)
+	payoutHandler := handlers.NewPayoutHandler(s.payoutService)
+	transferHandler := handlers.NewTransferHandler(s.transferService)
+	balanceHandler := handlers.NewBalanceHandler(s.balanceService, s.holdService)
+	disputeHandler := handlers.NewDisputeHandler(s.disputeService)
+	webhookHandler := handlers.NewWebhookHandler(s.invoiceService, s.webhookSecret)
+	apiKeyHandler := handlers.NewAPIKeyHandler(s.apiKeyService)
+	authMiddleware := middleware.NewAuthMiddleware(s.apiKeyService)
+	adminHandler := handlers.NewAdminHandler(s.operatorService, s.accountService, s.invoiceService, s.transferService, s.disputeService, s.holdService, s.reconciliationService, s.cnabService, s.auditService)
+	operatorMiddleware := middleware.NewOperatorMiddleware(s.operatorService)
 
 	s.router.Get("/up", handlers.GetHealth)
 
-	s.router.Post("/accounts", accountHandler.Create)
-	s.router.Get("/accounts", accountHandler.Get)
+	s.router.With(authMiddleware.Authenticate(domain.ScopeAccountsRead)).Get("/accounts", accountHandler.Get)
+	s.router.With(authMiddleware.Authenticate(domain.ScopeAccountsWrite)).Patch("/accounts", accountHandler.Update)
+	s.router.With(authMiddleware.Authenticate(domain.ScopeAccountsWrite)).Delete("/accounts", accountHandler.Delete)
+
+	s.router.With(authMiddleware.Authenticate(domain.ScopeInvoicesWrite)).Post("/invoice", invoiceHandler.Create)
+	s.router.With(authMiddleware.Authenticate(domain.ScopeInvoicesRead)).Get("/invoice/{id}", invoiceHandler.GetByID)
+	s.router.With(authMiddleware.Authenticate(domain.ScopeInvoicesRead)).Get("/invoice", invoiceHandler.ListByAccount)
+	s.router.With(authMiddleware.Authenticate(domain.ScopeRefundsWrite)).Post("/invoice/{id}/refunds", invoiceHandler.Refund)
+	s.router.With(authMiddleware.Authenticate(domain.ScopeInvoicesRead)).Get("/invoice/{id}/refunds", invoiceHandler.ListRefunds)
+
+	s.router.Route("/customers", func(r chi.Router) {
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersWrite)).Post("/", customerHandler.Create)
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersRead)).Get("/", customerHandler.Search)
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersRead)).Get("/{id}", customerHandler.Get)
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersWrite)).Patch("/{id}", customerHandler.Update)
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersWrite)).Delete("/{id}", customerHandler.Delete)
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersRead)).Get("/{id}/invoices", customerHandler.ListInvoices)
+
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersWrite)).Post("/{id}/payment-methods", paymentMethodHandler.Create)
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersRead)).Get("/{id}/payment-methods", paymentMethodHandler.List)
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersWrite)).Post("/{id}/payment-methods/{methodId}/default", paymentMethodHandler.SetDefault)
+		r.With(authMiddleware.Authenticate(domain.ScopeCustomersWrite)).Delete("/{id}/payment-methods/{methodId}", paymentMethodHandler.Delete)
+	})
+
+	s.router.Route("/plans", func(r chi.Router) {
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsWrite)).Post("/", planHandler.Create)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsRead)).Get("/", planHandler.List)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsRead)).Get("/{id}", planHandler.Get)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsWrite)).Delete("/{id}", planHandler.Archive)
+	})
+
+	s.router.Route("/subscriptions", func(r chi.Router) {
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsWrite)).Post("/", subscriptionHandler.Create)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsRead)).Get("/", subscriptionHandler.Search)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsRead)).Get("/{id}", subscriptionHandler.Get)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsWrite)).Post("/{id}/change-plan", subscriptionHandler.ChangePlan)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsWrite)).Post("/{id}/pause", subscriptionHandler.Pause)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsWrite)).Post("/{id}/resume", subscriptionHandler.Resume)
+		r.With(authMiddleware.Authenticate(domain.ScopeSubscriptionsWrite)).Post("/{id}/cancel", subscriptionHandler.Cancel)
+	})
+
+	s.router.With(authMiddleware.Authenticate(domain.ScopeEventsRead)).Get("/events", eventHandler.Search)
+
+	s.router.Route("/payout-destinations", func(r chi.Router) {
+		r.With(authMiddleware.Authenticate(domain.ScopePayoutsWrite)).Post("/", payoutHandler.CreateDestination)
+		r.With(authMiddleware.Authenticate(domain.ScopePayoutsRead)).Get("/", payoutHandler.ListDestinations)
+		r.With(authMiddleware.Authenticate(domain.ScopePayoutsWrite)).Delete("/{id}", payoutHandler.DeleteDestination)
+	})
+
+	s.router.Route("/payouts", func(r chi.Router) {
+		r.With(authMiddleware.Authenticate(domain.ScopePayoutsWrite)).Post("/", payoutHandler.Create)
+		r.With(authMiddleware.Authenticate(domain.ScopePayoutsRead)).Get("/", payoutHandler.Search)
+		r.With(authMiddleware.Authenticate(domain.ScopePayoutsRead)).Get("/{id}", payoutHandler.Get)
+	})
+
+	s.router.Route("/transfers", func(r chi.Router) {
+		r.With(authMiddleware.Authenticate(domain.ScopeTransfersWrite)).Post("/", transferHandler.Create)
+		r.With(authMiddleware.Authenticate(domain.ScopeTransfersRead)).Get("/", transferHandler.Search)
+		r.With(authMiddleware.Authenticate(domain.ScopeTransfersRead)).Get("/{id}", transferHandler.Get)
+		r.With(authMiddleware.Authenticate(domain.ScopeTransfersWrite)).Post("/{id}/reversal", transferHandler.Reverse)
+	})
+
+	s.router.Route("/balance", func(r chi.Router) {
+		r.With(authMiddleware.Authenticate(domain.ScopeBalanceRead)).Get("/transactions", balanceHandler.ListTransactions)
+		r.With(authMiddleware.Authenticate(domain.ScopeBalanceRead)).Get("/statements/{month}", balanceHandler.Statement)
+		r.With(authMiddleware.Authenticate(domain.ScopeBalanceRead)).Get("/holds", balanceHandler.ListHolds)
+	})
+
+	s.router.Route("/disputes", func(r chi.Router) {
+		r.With(authMiddleware.Authenticate(domain.ScopeDisputesRead)).Get("/", disputeHandler.Search)
+		r.With(authMiddleware.Authenticate(domain.ScopeDisputesRead)).Get("/{id}", disputeHandler.Get)
+		r.With(authMiddleware.Authenticate(domain.ScopeDisputesWrite)).Post("/{id}/evidence", disputeHandler.AddEvidence)
+		r.With(authMiddleware.Authenticate(domain.ScopeDisputesRead)).Get("/{id}/evidence/{evidenceId}", disputeHandler.DownloadEvidence)
+		r.With(authMiddleware.Authenticate(domain.ScopeDisputesWrite)).Post("/{id}/submit", disputeHandler.Submit)
+	})
+
+	if s.simulatorEnabled {
+		s.router.Route("/simulator", func(r chi.Router) {
+			r.With(authMiddleware.Authenticate(domain.ScopeDisputesWrite)).Post("/disputes", disputeHandler.Simulate)
+			r.With(authMiddleware.Authenticate(domain.ScopeDisputesWrite)).Post("/disputes/{id}/outcome", disputeHandler.SimulateOutcome)
+		})
+	}
+
+	s.router.Post("/webhooks/inter", webhookHandler.Inter)
 
 	s.router.Group(func(r chi.Router) {
-		r.Use(authMiddleware.Authenticate)
-		s.router.Post("/invoice", invoiceHandler.Create)
-		s.router.Get("/invoice/{id}", invoiceHandler.GetByID)
-		s.router.Get("/invoice", invoiceHandler.ListByAccount)
+		r.Use(authMiddleware.Authenticate(domain.ScopeKeysWrite))
+		r.Post("/api-keys", apiKeyHandler.Create)
+		r.Get("/api-keys", apiKeyHandler.List)
+		r.Delete("/api-keys/{id}", apiKeyHandler.Revoke)
+	})
+
+	s.router.Route("/admin", func(r chi.Router) {
+		r.Post("/login", adminHandler.Login)
+
+		r.Group(func(r chi.Router) {
+			r.Use(operatorMiddleware.Authenticate)
+
+			r.With(operatorMiddleware.Require(domain.PermissionOperatorsManage)).Post("/operators", adminHandler.CreateOperator)
+
+			r.With(operatorMiddleware.Require(domain.PermissionAccountsCreate)).Post("/accounts", adminHandler.CreateAccount)
+			r.With(operatorMiddleware.Require(domain.PermissionAccountsRead)).Get("/accounts", adminHandler.SearchAccounts)
+			r.With(operatorMiddleware.Require(domain.PermissionAccountsRestore)).Post("/accounts/{id}/restore", adminHandler.RestoreAccount)
+			r.With(operatorMiddleware.Require(domain.PermissionBalanceAdjust)).Post("/accounts/{id}/balance-adjustments", adminHandler.AdjustBalance)
+			r.With(operatorMiddleware.Require(domain.PermissionPricingManage)).Put("/accounts/{id}/fee-schedule", adminHandler.SetFeeSchedule)
+			r.With(operatorMiddleware.Require(domain.PermissionBalanceAdjust)).Post("/transfers", adminHandler.CreateTransfer)
+			r.With(operatorMiddleware.Require(domain.PermissionBalanceAdjust)).Post("/transfers/{id}/reversal", adminHandler.ReverseTransfer)
+			r.With(operatorMiddleware.Require(domain.PermissionAccountsFreeze)).Post("/accounts/{id}/freeze", adminHandler.FreezeAccount)
+			r.With(operatorMiddleware.Require(domain.PermissionAccountsFreeze)).Post("/accounts/{id}/unfreeze", adminHandler.UnfreezeAccount)
+
+			r.With(operatorMiddleware.Require(domain.PermissionRiskManage)).Put("/accounts/{id}/reserve-policy", adminHandler.SetReservePolicy)
+			r.With(operatorMiddleware.Require(domain.PermissionRiskManage)).Post("/accounts/{id}/holds", adminHandler.PlaceHold)
+			r.With(operatorMiddleware.Require(domain.PermissionAccountsRead)).Get("/accounts/{id}/holds", adminHandler.SearchHolds)
+			r.With(operatorMiddleware.Require(domain.PermissionRiskManage)).Post("/holds/{id}/release", adminHandler.ReleaseHold)
+
+			r.With(operatorMiddleware.Require(domain.PermissionInvoicesRead)).Get("/invoices", adminHandler.SearchInvoices)
+
+			r.With(operatorMiddleware.Require(domain.PermissionInvoicesRead)).Get("/disputes", adminHandler.SearchDisputes)
+			r.With(operatorMiddleware.Require(domain.PermissionDisputesManage)).Post("/disputes/{id}/resolve", adminHandler.ResolveDispute)
+
+			r.With(operatorMiddleware.Require(domain.PermissionReconcile)).Post("/reconciliations", adminHandler.RunReconciliation)
+			r.With(operatorMiddleware.Require(domain.PermissionInvoicesRead)).Get("/reconciliations", adminHandler.SearchReconciliations)
+			r.With(operatorMiddleware.Require(domain.PermissionInvoicesRead)).Get("/reconciliations/{id}", adminHandler.GetReconciliation)
+
+			r.With(operatorMiddleware.Require(domain.PermissionCNABManage)).Post("/cnab/remittances", adminHandler.GenerateRemittance)
+			r.With(operatorMiddleware.Require(domain.PermissionCNABManage)).Post("/cnab/returns", adminHandler.ImportReturn)
+			r.With(operatorMiddleware.Require(domain.PermissionInvoicesRead)).Get("/cnab/files", adminHandler.SearchCNABFiles)
+			r.With(operatorMiddleware.Require(domain.PermissionInvoicesRead)).Get("/cnab/files/{id}", adminHandler.GetCNABFile)
+			r.With(operatorMiddleware.Require(domain.PermissionInvoicesRead)).Get("/cnab/files/{id}/download", adminHandler.DownloadCNABFile)
+
+			r.With(operatorMiddleware.Require(domain.PermissionAuditRead)).Get("/audit-log", adminHandler.SearchAuditLog)
+			r.With(operatorMiddleware.Require(domain.PermissionAuditRead)).Get("/audit-log/verify", adminHandler.VerifyAuditLog)
+		})
 	})
 }
 